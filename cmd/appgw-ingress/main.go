@@ -7,8 +7,8 @@ package main
 
 import (
 	"context"
-	"errors"
 	"flag"
+	"net/http"
 	"os"
 	"os/signal"
 	"sort"
@@ -25,6 +25,7 @@ import (
 	"github.com/spf13/pflag"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -36,10 +37,17 @@ import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/controller"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
+	gateway "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned"
+	gatewayscheme "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/scheme"
 	istio "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned"
+	istioscheme "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/scheme"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metrics"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/snapshot"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/version"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/webhook"
 )
 
 const (
@@ -67,6 +75,18 @@ var (
 	versionInfo = flags.Bool("version", false, "Print version")
 
 	verbosity = flags.Int(verbosityFlag, 1, "Set logging verbosity level")
+
+	metricsAddress = flags.String("metrics-address", "",
+		"The address AGIC serves metrics on, for instance \":8080\". Metrics are not served when omitted.")
+
+	webhookAddress = flags.String("webhook-address", "",
+		"The address AGIC serves the validating admission webhook on, for instance \":8443\". The webhook is not served when omitted.")
+
+	webhookCertFile = flags.String("webhook-tls-cert-file", "",
+		"Path to the TLS certificate of the validating admission webhook. Required with --webhook-address.")
+
+	webhookKeyFile = flags.String("webhook-tls-key-file", "",
+		"Path to the TLS private key of the validating admission webhook. Required with --webhook-address.")
 )
 
 func main() {
@@ -109,18 +129,23 @@ func main() {
 	kubeClient := kubernetes.NewForConfigOrDie(apiConfig)
 	crdClient := versioned.NewForConfigOrDie(apiConfig)
 	istioCrdClient := istio.NewForConfigOrDie(apiConfig)
+	gatewayCrdClient := gateway.NewForConfigOrDie(apiConfig)
 	recorder := getEventRecorder(kubeClient)
 	namespaces := getNamespacesToWatch(env.WatchNamespace)
-	k8sContext := k8scontext.NewContext(kubeClient, crdClient, istioCrdClient, namespaces, *resyncPeriod)
+	namespaceSelector := getNamespaceSelector(env.WatchNamespaceSelector)
+	k8sContext := k8scontext.NewContext(kubeClient, crdClient, istioCrdClient, gatewayCrdClient, namespaces, namespaceSelector, *resyncPeriod)
 
 	// namespace validations
-	if err := validateNamespaces(namespaces, kubeClient); err != nil {
-		glog.Fatal(err) // side-effect: will panic on non-existent namespace
-	}
-	if len(namespaces) == 0 {
+	validateNamespaces(namespaces, kubeClient)
+	if len(namespaces) == 0 && namespaceSelector == nil {
 		glog.Info("Ingress Controller will observe all namespaces.")
 	} else {
-		glog.Info("Ingress Controller will observe the following namespaces:", strings.Join(namespaces, ","))
+		if len(namespaces) > 0 {
+			glog.Info("Ingress Controller will observe the following namespaces:", strings.Join(namespaces, ","))
+		}
+		if namespaceSelector != nil {
+			glog.Info("Ingress Controller will observe the namespaces matching the label selector: ", namespaceSelector.String())
+		}
 	}
 
 	// fatal config validations
@@ -129,8 +154,30 @@ func main() {
 		glog.Fatal("Got a fatal validation error on existing Application Gateway config. Please update Application Gateway or the controller's helm config. Error:", err)
 	}
 
+	if *metricsAddress != "" {
+		go func() {
+			glog.Fatal(http.ListenAndServe(*metricsAddress, metrics.NewMetricsMux()))
+		}()
+	}
+
+	if *webhookAddress != "" {
+		go func() {
+			glog.Fatal(http.ListenAndServeTLS(*webhookAddress, *webhookCertFile, *webhookKeyFile, webhook.NewWebhookMux(k8sContext)))
+		}()
+	}
+
 	// initiliaze controller
-	appGwIngressController := controller.NewAppGwIngressController(*appGwClient, appGwIdentifier, k8sContext, recorder)
+	var snapshots *snapshot.Store
+	if count := env.GetSnapshotCount(); count > 0 {
+		snapshots = snapshot.NewStore(kubeClient, env.AGICPodNamespace, count)
+	}
+
+	var manifests *ownership.Store
+	if env.EnableOwnershipTracking == "true" {
+		manifests = ownership.NewStore(kubeClient, env.AGICPodNamespace, env.AppGwName)
+	}
+
+	appGwIngressController := controller.NewAppGwIngressController(*appGwClient, appGwIdentifier, k8sContext, recorder, snapshots, manifests)
 
 	// start controller
 	if err := appGwIngressController.Start(env); err != nil{
@@ -144,7 +191,8 @@ func main() {
 	glog.Info("Goodbye!")
 }
 
-func validateNamespaces(namespaces []string, kubeClient *kubernetes.Clientset) error {
+// validateNamespaces warns about namespaces, which do not exist (yet); AGIC starts observing them once they are created.
+func validateNamespaces(namespaces []string, kubeClient *kubernetes.Clientset) {
 	var nonExistent []string
 	for _, ns := range namespaces {
 		if _, err := kubeClient.CoreV1().Namespaces().Get(ns, metav1.GetOptions{}); err != nil {
@@ -152,10 +200,18 @@ func validateNamespaces(namespaces []string, kubeClient *kubernetes.Clientset) e
 		}
 	}
 	if len(nonExistent) > 0 {
-		glog.Errorf("Error creating informers; Namespaces do not exist or Ingress Controller has no access to: %v", strings.Join(nonExistent, ","))
-		return errors.New("namespace does not exist")
+		glog.Warningf("Namespaces do not exist or Ingress Controller has no access to: %v; Ingress Controller will observe them once they are created", strings.Join(nonExistent, ","))
 	}
-	return nil
+}
+
+// getNamespaceSelector parses the label selector of the namespaces to watch; Returns nil when there is none.
+func getNamespaceSelector(selectorEnvVar string) labels.Selector {
+	if selectorEnvVar == "" {
+		return nil
+	}
+	// The selector has been validated with the environment.
+	selector, _ := labels.Parse(selectorEnvVar)
+	return selector
 }
 
 func getNamespacesToWatch(namespaceEnvVar string) []string {
@@ -277,6 +333,13 @@ func getEventRecorder(kubeClient kubernetes.Interface) record.EventRecorder {
 		Component: annotations.ApplicationGatewayIngressClass,
 		Host:      hostname,
 	}
+	// AGIC records events on Istio and Gateway API resources too; The recorder must know their kinds.
+	if err := istioscheme.AddToScheme(scheme.Scheme); err != nil {
+		glog.Error("Could not register the Istio resources with the event recorder", err)
+	}
+	if err := gatewayscheme.AddToScheme(scheme.Scheme); err != nil {
+		glog.Error("Could not register the Gateway API resources with the event recorder", err)
+	}
 	return eventBroadcaster.NewRecorder(scheme.Scheme, source)
 }
 
@@ -56,8 +56,17 @@ var _ = Describe("Test functions used in main.go", func() {
 
 	Context("test validateNamespaces", func() {
 		It("should validate the namespaces", func() {
-			actual := validateNamespaces([]string{}, &kubernetes.Clientset{})
-			Ω(actual).Should(Succeed())
+			Ω(func() { validateNamespaces([]string{}, &kubernetes.Clientset{}) }).ShouldNot(Panic())
+		})
+	})
+
+	Context("test getNamespaceSelector", func() {
+		It("should return nil without a selector", func() {
+			Ω(getNamespaceSelector("")).Should(BeNil())
+		})
+		It("should parse the label selector", func() {
+			actual := getNamespaceSelector("agic=enabled")
+			Ω(actual.String()).Should(Equal("agic=enabled"))
 		})
 	})
 
@@ -0,0 +1,170 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/snapshot"
+)
+
+const usage = `Manages the App Gateway config snapshots kept by the Application Gateway Ingress Controller.
+
+Usage:
+  appgw-snapshot list
+  appgw-snapshot diff <from-snapshot-id> <to-snapshot-id>
+  appgw-snapshot rollback <snapshot-id>
+
+Flags:
+`
+
+var (
+	env = environment.GetEnv()
+
+	flags = pflag.NewFlagSet(`appgw-snapshot`, pflag.ExitOnError)
+
+	kubeConfigFile = flags.String("kubeconfig", "",
+		"Path to kubeconfig file with authorization and master location information. Uses the default kubeconfig when omitted.")
+
+	namespace = flags.String("namespace", env.AGICPodNamespace,
+		"The namespace the Application Gateway Ingress Controller runs in.")
+
+	subscriptionID = flags.String("subscription-id", env.SubscriptionID,
+		"The subscription of the App Gateway. Required for rollback.")
+
+	resourceGroup = flags.String("resource-group", env.ResourceGroupName,
+		"The resource group of the App Gateway. Required for rollback.")
+
+	appGwName = flags.String("appgw-name", env.AppGwName,
+		"The name of the App Gateway. Required for rollback.")
+)
+
+func main() {
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		exitWithError(err)
+	}
+
+	args := flags.Args()
+	if len(args) == 0 || *namespace == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	store := snapshot.NewStore(getKubeClient(), *namespace, 0)
+
+	var err error
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		err = list(store)
+	case args[0] == "diff" && len(args) == 3:
+		err = diff(store, args[1], args[2])
+	case args[0] == "rollback" && len(args) == 2:
+		err = rollback(store, args[1])
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		exitWithError(err)
+	}
+}
+
+func list(store *snapshot.Store) error {
+	snapshots, err := store.List()
+	if err != nil {
+		return err
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tTIMESTAMP")
+	for _, s := range snapshots {
+		fmt.Fprintf(writer, "%s\t%s\n", s.ID, s.Timestamp.Format(time.RFC3339))
+	}
+	return writer.Flush()
+}
+
+func diff(store *snapshot.Store, fromID string, toID string) error {
+	from, err := store.Get(fromID)
+	if err != nil {
+		return fmt.Errorf("%s: %s", fromID, err)
+	}
+	to, err := store.Get(toID)
+	if err != nil {
+		return fmt.Errorf("%s: %s", toID, err)
+	}
+	changes, err := snapshot.Diff(*from, *to)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("Snapshots are identical.")
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	return nil
+}
+
+func rollback(store *snapshot.Store, id string) error {
+	if *subscriptionID == "" || *resourceGroup == "" || *appGwName == "" {
+		return fmt.Errorf("--subscription-id, --resource-group and --appgw-name are required for rollback")
+	}
+	s, err := store.Get(id)
+	if err != nil {
+		return fmt.Errorf("%s: %s", id, err)
+	}
+
+	client := n.NewApplicationGatewaysClient(*subscriptionID)
+	if client.Authorizer, err = getAzAuth(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Rolling back App Gateway %s to snapshot %s...\n", *appGwName, s.ID)
+	if _, err := snapshot.Rollback(context.Background(), client, *resourceGroup, *appGwName, *s); err != nil {
+		return err
+	}
+	fmt.Println("Done.")
+	return nil
+}
+
+func getAzAuth() (autorest.Authorizer, error) {
+	if env.AuthLocation != "" {
+		return auth.NewAuthorizerFromFile(n.DefaultBaseURI)
+	}
+	// Use the credentials of the Azure CLI
+	return auth.NewAuthorizerFromCLI()
+}
+
+func getKubeClient() kubernetes.Interface {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = *kubeConfigFile
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		exitWithError(err)
+	}
+	return kubernetes.NewForConfigOrDie(config)
+}
+
+func exitWithError(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(1)
+}
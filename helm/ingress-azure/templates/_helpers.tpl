@@ -101,4 +101,33 @@ If release name contains chart name it will be used as a full name.
 {{- printf "%s-azidbinding-%s" .Release.Name $name | trunc 63 | trimSuffix "-" -}}
 {{- end -}}
 {{- end -}}
-{{- end -}}
\ No newline at end of file
+{{- end -}}
+{{/*
+Create the name of the Service, TLS secret and ValidatingWebhookConfiguration of the validating admission webhook.
+We truncate at 63 chars because some Kubernetes name fields are limited to this (by the DNS naming spec).
+*/}}
+{{- define "application-gateway-kubernetes-ingress.webhookname" -}}
+{{- printf "%s-webhook" (include "application-gateway-kubernetes-ingress.fullname" . | trunc 55 | trimSuffix "-") -}}
+{{- end -}}
+
+{{/*
+Render "true" when the validating admission webhook is enabled.
+*/}}
+{{- define "application-gateway-kubernetes-ingress.webhookenabled" -}}
+{{- if .Values.webhook -}}
+{{- if .Values.webhook.enabled -}}
+true
+{{- end -}}
+{{- end -}}
+{{- end -}}
+
+{{/*
+Render "true" when AGIC serves its metrics.
+*/}}
+{{- define "application-gateway-kubernetes-ingress.metricsenabled" -}}
+{{- if .Values.metrics -}}
+{{- if .Values.metrics.enabled -}}
+true
+{{- end -}}
+{{- end -}}
+{{- end -}}
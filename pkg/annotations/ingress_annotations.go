@@ -40,6 +40,20 @@ const (
 	// UsePrivateIP defines the key to determine whether to use private ip with the ingress.
 	UsePrivateIPKey = ApplicationGatewayPrefix + "/use-private-ip"
 
+	// CanaryServiceKey defines the key for the Service in the namespace of the Ingress, which receives a share of the requests
+	// to the backends of the Ingress; The share is given with CanaryWeightKey.
+	CanaryServiceKey = ApplicationGatewayPrefix + "/canary-service"
+
+	// CanaryWeightKey defines the percentage, 0 to 100, of the requests to the backends of the Ingress the canary Service receives.
+	CanaryWeightKey = ApplicationGatewayPrefix + "/canary-weight"
+
+	// BackendModeKey defines the key for the addresses App Gateway sends the requests of the Ingress to; One of BackendModes.
+	BackendModeKey = ApplicationGatewayPrefix + "/backend-mode"
+
+	// GatewayAPIListenerKey marks the Ingresses AGIC translates from the listeners of Kubernetes Gateway API Gateways;
+	// Its value is the namespace/name/listener of the Gateway listener. AGIC ignores Ingresses created with this annotation.
+	GatewayAPIListenerKey = ApplicationGatewayPrefix + "/gateway-api-listener"
+
 	// IngressClassKey defines the key of the annotation which needs to be set in order to specify
 	// that this is an ingress resource meant for the application gateway ingress controller.
 	IngressClassKey = "kubernetes.io/ingress.class"
@@ -53,6 +67,33 @@ const (
 	ApplicationGatewayIngressClass = "azure/application-gateway"
 )
 
+const (
+	// BackendModePod sends the requests straight to the pod IPs of the Endpoints of the Service; It requires a flat network.
+	BackendModePod = "pod"
+
+	// BackendModeClusterIP sends the requests to the cluster IP of the Service.
+	BackendModeClusterIP = "clusterip"
+
+	// BackendModeNodePort sends the requests to the node port of the Service on every node of the cluster.
+	BackendModeNodePort = "nodeport"
+
+	// BackendModeLoadBalancer sends the requests to the internal load balancer IP of a Service of type LoadBalancer.
+	BackendModeLoadBalancer = "loadbalancer"
+)
+
+// BackendModes lists the values of BackendModeKey.
+var BackendModes = []string{BackendModePod, BackendModeClusterIP, BackendModeNodePort, BackendModeLoadBalancer}
+
+// IsBackendMode determines whether the value is one of BackendModes.
+func IsBackendMode(mode string) bool {
+	for _, backendMode := range BackendModes {
+		if mode == backendMode {
+			return true
+		}
+	}
+	return false
+}
+
 // IsApplicationGatewayIngress checks if the Ingress resource can be handled by the Application Gateway ingress controller.
 func IsApplicationGatewayIngress(ing *v1beta1.Ingress) (bool, error) {
 	controllerName, err := parseString(ing, IngressClassKey)
@@ -103,6 +144,54 @@ func UsePrivateIP(ing *v1beta1.Ingress) (bool, error) {
 	return parseBool(ing, UsePrivateIPKey)
 }
 
+// CanaryService returns the name of the Service receiving a share of the requests to the backends of the Ingress.
+func CanaryService(ing *v1beta1.Ingress) (string, error) {
+	return parseString(ing, CanaryServiceKey)
+}
+
+// CanaryWeight returns the percentage of the requests to the backends of the Ingress the canary Service receives.
+func CanaryWeight(ing *v1beta1.Ingress) (int32, error) {
+	weight, err := parseInt32(ing, CanaryWeightKey)
+	if err == nil && (weight < 0 || weight > 100) {
+		return 0, errors.NewInvalidAnnotationContent(CanaryWeightKey, ing.Annotations[CanaryWeightKey])
+	}
+	return weight, err
+}
+
+// BackendMode returns the addresses App Gateway sends the requests of the Ingress to.
+func BackendMode(ing *v1beta1.Ingress) (string, error) {
+	mode, err := parseString(ing, BackendModeKey)
+	if err == nil && !IsBackendMode(mode) {
+		return "", errors.NewInvalidAnnotationContent(BackendModeKey, mode)
+	}
+	return mode, err
+}
+
+// GatewayAPIListener returns the namespace/name/listener of the Gateway listener the Ingress was translated from.
+func GatewayAPIListener(ing *v1beta1.Ingress) (string, error) {
+	return parseString(ing, GatewayAPIListenerKey)
+}
+
+// Validate parses all AGIC annotations of the Ingress and returns the errors for the ones with invalid values.
+func Validate(ing *v1beta1.Ingress) []error {
+	var invalid []error
+	for _, parse := range []func(*v1beta1.Ingress) error{
+		func(ing *v1beta1.Ingress) error { _, err := IsSslRedirect(ing); return err },
+		func(ing *v1beta1.Ingress) error { _, err := RequestTimeout(ing); return err },
+		func(ing *v1beta1.Ingress) error { _, err := IsConnectionDraining(ing); return err },
+		func(ing *v1beta1.Ingress) error { _, err := ConnectionDrainingTimeout(ing); return err },
+		func(ing *v1beta1.Ingress) error { _, err := IsCookieBasedAffinity(ing); return err },
+		func(ing *v1beta1.Ingress) error { _, err := UsePrivateIP(ing); return err },
+		func(ing *v1beta1.Ingress) error { _, err := CanaryWeight(ing); return err },
+		func(ing *v1beta1.Ingress) error { _, err := BackendMode(ing); return err },
+	} {
+		if err := parse(ing); err != nil && errors.IsInvalidContent(err) {
+			invalid = append(invalid, err)
+		}
+	}
+	return invalid
+}
+
 func parseBool(ing *v1beta1.Ingress, name string) (bool, error) {
 	if val, ok := ing.Annotations[name]; ok {
 		if boolVal, err := strconv.ParseBool(val); err == nil {
@@ -42,6 +42,10 @@ var _ = Describe("Test ingress annotation functions", func() {
 		"appgw.ingress.kubernetes.io/request-timeout":             "123456",
 		"appgw.ingress.kubernetes.io/connection-draining-timeout": "3456",
 		"appgw.ingress.kubernetes.io/backend-path-prefix":         "prefix-here",
+		"appgw.ingress.kubernetes.io/gateway-api-listener":        "infra/gateway/https",
+		"appgw.ingress.kubernetes.io/canary-service":              "app-v2",
+		"appgw.ingress.kubernetes.io/canary-weight":               "10",
+		"appgw.ingress.kubernetes.io/backend-mode":                "nodeport",
 		"kubernetes.io/ingress.class":                             "azure/application-gateway",
 		"appgw.ingress.istio.io/v1alpha3":                         "azure/application-gateway",
 		"falseKey":                                                "false",
@@ -185,6 +189,92 @@ var _ = Describe("Test ingress annotation functions", func() {
 		})
 	})
 
+	Context("test GatewayAPIListener", func() {
+		It("returns error when ingress has no annotations", func() {
+			ing := &v1beta1.Ingress{}
+			actual, err := GatewayAPIListener(ing)
+			Expect(err).To(HaveOccurred())
+			Expect(actual).To(Equal(""))
+		})
+		It("returns the listener with correct annotation", func() {
+			actual, err := GatewayAPIListener(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal("infra/gateway/https"))
+		})
+	})
+
+	Context("test CanaryService", func() {
+		It("returns error when ingress has no annotations", func() {
+			ing := &v1beta1.Ingress{}
+			actual, err := CanaryService(ing)
+			Expect(err).To(HaveOccurred())
+			Expect(actual).To(Equal(""))
+		})
+		It("returns the service with correct annotation", func() {
+			actual, err := CanaryService(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal("app-v2"))
+		})
+	})
+
+	Context("test CanaryWeight", func() {
+		It("returns the weight with correct annotation", func() {
+			actual, err := CanaryWeight(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal(int32(10)))
+		})
+		It("returns an error for a weight above 100", func() {
+			ing := &v1beta1.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{CanaryWeightKey: "110"},
+				},
+			}
+			_, err := CanaryWeight(ing)
+			Expect(errors.IsInvalidContent(err)).To(BeTrue())
+		})
+	})
+
+	Context("test BackendMode", func() {
+		It("returns the backend mode with correct annotation", func() {
+			actual, err := BackendMode(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal(BackendModeNodePort))
+		})
+		It("returns an error for an unknown backend mode", func() {
+			ing := &v1beta1.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{BackendModeKey: "hostnetwork"},
+				},
+			}
+			_, err := BackendMode(ing)
+			Expect(errors.IsInvalidContent(err)).To(BeTrue())
+		})
+	})
+
+	Context("test Validate", func() {
+		It("returns no errors for valid annotations", func() {
+			Expect(Validate(ing)).To(BeEmpty())
+		})
+		It("returns no errors when ingress has no annotations", func() {
+			Expect(Validate(&v1beta1.Ingress{})).To(BeEmpty())
+		})
+		It("returns an error for each annotation with an invalid value", func() {
+			invalid := &v1beta1.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{
+						RequestTimeoutKey: "abc",
+						SslRedirectKey:    "yes please",
+						UsePrivateIPKey:   "true",
+					},
+				},
+			}
+			errs := Validate(invalid)
+			Expect(len(errs)).To(Equal(2))
+			Expect(errs[0].Error()).To(ContainSubstring(SslRedirectKey))
+			Expect(errs[1].Error()).To(ContainSubstring(RequestTimeoutKey))
+		})
+	})
+
 	Context("test parseBol", func() {
 		It("returns true", func() {
 			actual, err := parseBool(ing, UsePrivateIPKey)
@@ -0,0 +1,41 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package annotations
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
+)
+
+const (
+	// HostnamesKey defines the key of the namespace annotation granting the namespace the hostnames its Ingresses may use.
+	// The value is a comma separated list of hostnames and wildcard patterns, for instance "www.contoso.com,*.team-a.contoso.com".
+	HostnamesKey = ApplicationGatewayPrefix + "/hostnames"
+)
+
+// Hostnames provides the hostnames and wildcard patterns granted to the namespace, in lower case.
+func Hostnames(namespace *v1.Namespace) ([]string, error) {
+	val, ok := namespace.Annotations[HostnamesKey]
+	if !ok {
+		return nil, errors.ErrMissingAnnotations
+	}
+	var hostnames []string
+	for _, hostname := range strings.Split(val, ",") {
+		hostname = strings.ToLower(strings.TrimSpace(hostname))
+		if hostname == "" {
+			continue
+		}
+		// A * may only stand for the entire hostname or for the leftmost labels.
+		if hostname != "*" && strings.Contains(strings.TrimPrefix(hostname, "*."), "*") {
+			return nil, errors.NewInvalidAnnotationContent(HostnamesKey, val)
+		}
+		hostnames = append(hostnames, hostname)
+	}
+	return hostnames, nil
+}
@@ -0,0 +1,47 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package annotations
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
+)
+
+var _ = Describe("Test namespace annotation functions", func() {
+	newNamespace := func(hostnames string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: v1.ObjectMeta{
+				Name:        "team-a",
+				Annotations: map[string]string{HostnamesKey: hostnames},
+			},
+		}
+	}
+
+	Context("test Hostnames", func() {
+		It("returns error when namespace has no annotations", func() {
+			hostnames, err := Hostnames(&corev1.Namespace{})
+			Expect(err).To(Equal(errors.ErrMissingAnnotations))
+			Expect(hostnames).To(BeNil())
+		})
+
+		It("returns the lower case hostnames and patterns", func() {
+			hostnames, err := Hostnames(newNamespace(" WWW.contoso.com, *.team-a.contoso.com,,*"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hostnames).To(Equal([]string{"www.contoso.com", "*.team-a.contoso.com", "*"}))
+		})
+
+		It("returns error for a * which is not the leftmost label", func() {
+			for _, invalid := range []string{"www.*.contoso.com", "*contoso.com", "www.contoso.*"} {
+				_, err := Hostnames(newNamespace(invalid))
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), invalid)
+			}
+		})
+	})
+})
@@ -0,0 +1,48 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AzureIngressManagedTarget is a target AGIC is allowed to mutate; When at least one exists, AGIC is not allowed to mutate anything else.
+type AzureIngressManagedTarget struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AzureIngressManagedTargetSpec `json:"spec"`
+}
+
+// AzureIngressManagedTargetSpec defines a uniquely identifiable target for which AGIC is allowed to mutate config.
+type AzureIngressManagedTargetSpec struct {
+	// +optional
+	// Hostname of the managed target; An empty hostname stands for the listeners without a hostname
+	Hostname string `json:"hostname,omitempty"`
+
+	// +optional
+	// Port number of the managed target; Any port when omitted
+	Port int32 `json:"port,omitempty"`
+
+	// +optional
+	// Paths is a list of URL paths, for which the Ingress Controller is allowed to mutate Application Gateway configuration; Must begin with a / and end with /*
+	Paths []string `json:"paths,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AzureIngressManagedTargetList is the list of managed targets
+type AzureIngressManagedTargetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AzureIngressManagedTarget `json:"items"`
+}
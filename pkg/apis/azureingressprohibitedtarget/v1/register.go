@@ -4,7 +4,7 @@
 // --------------------------------------------------------------------------------------------
 
 // +k8s:deepcopy-gen=package,register
-// +groupName=azureingressprohibitedtargets.appgw.ingress.k8s.io
+// +groupName=appgw.ingress.k8s.io
 
 // Package v1 contains API Schema definitions for the AzureIngressProhibitedTarget v1 API group
 package v1
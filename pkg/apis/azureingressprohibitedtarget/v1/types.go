@@ -6,6 +6,7 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -20,6 +21,9 @@ type AzureIngressProhibitedTarget struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec AzureIngressProhibitedTargetSpec `json:"spec"`
+
+	// +optional
+	Status AzureIngressProhibitedTargetStatus `json:"status,omitempty"`
 }
 
 // AzureIngressProhibitedTargetSpec defines a list of uniquely identifiable targets for which the AGIC is not allowed to mutate config.
@@ -45,6 +49,84 @@ type AzureIngressProhibitedTargetSpec struct {
 	Paths []string `json:"paths,omitempty"`
 }
 
+// AzureIngressProhibitedTargetStatus is the App Gateway configuration and the Ingress rules the prohibited target matched during the last reconcile.
+type AzureIngressProhibitedTargetStatus struct {
+	// +optional
+	// Listeners is the list of names of the App Gateway HTTP listeners matched by the prohibited target
+	Listeners []string `json:"listeners,omitempty"`
+
+	// +optional
+	// RoutingRules is the list of names of the App Gateway request routing rules matched by the prohibited target
+	RoutingRules []string `json:"routingRules,omitempty"`
+
+	// +optional
+	// URLPathMaps is the list of names of the App Gateway URL path maps matched by the prohibited target
+	URLPathMaps []string `json:"urlPathMaps,omitempty"`
+
+	// +optional
+	// BackendAddressPools is the list of names of the App Gateway backend address pools matched by the prohibited target
+	BackendAddressPools []string `json:"backendAddressPools,omitempty"`
+
+	// +optional
+	// BackendHTTPSettings is the list of names of the App Gateway backend HTTP settings matched by the prohibited target
+	BackendHTTPSettings []string `json:"backendHttpSettings,omitempty"`
+
+	// +optional
+	// Probes is the list of names of the App Gateway health probes matched by the prohibited target
+	Probes []string `json:"probes,omitempty"`
+
+	// +optional
+	// PrunedIngressRules is the list of Ingress rules, which AGIC ignored because of the prohibited target
+	PrunedIngressRules []PrunedIngressRule `json:"prunedIngressRules,omitempty"`
+
+	// +optional
+	// Conditions is the list of current conditions of the prohibited target
+	Conditions []AzureIngressProhibitedTargetCondition `json:"conditions,omitempty"`
+}
+
+// PrunedIngressRule identifies a host and path of an Ingress, which AGIC ignored because of a prohibited target.
+type PrunedIngressRule struct {
+	// Ingress is the namespace/name of the Ingress
+	Ingress string `json:"ingress"`
+
+	// +optional
+	// Host is the host of the Ingress rule
+	Host string `json:"host,omitempty"`
+
+	// +optional
+	// Path is the path of the Ingress rule
+	Path string `json:"path,omitempty"`
+}
+
+// AzureIngressProhibitedTargetConditionType is the type of a condition of a prohibited target.
+type AzureIngressProhibitedTargetConditionType string
+
+const (
+	// InvalidSpec is True when the spec of the prohibited target is invalid; AGIC still honours the prohibited target.
+	InvalidSpec AzureIngressProhibitedTargetConditionType = "InvalidSpec"
+)
+
+// AzureIngressProhibitedTargetCondition is a condition of a prohibited target.
+type AzureIngressProhibitedTargetCondition struct {
+	// Type of the condition
+	Type AzureIngressProhibitedTargetConditionType `json:"type"`
+
+	// Status of the condition: True, False or Unknown
+	Status corev1.ConditionStatus `json:"status"`
+
+	// +optional
+	// LastTransitionTime is the last time the condition changed status
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// +optional
+	// Reason is a one-word CamelCase reason for the last transition
+	Reason string `json:"reason,omitempty"`
+
+	// +optional
+	// Message is a human readable description of the last transition
+	Message string `json:"message,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // AzureIngressProhibitedTargetList is the list of prohibited targets
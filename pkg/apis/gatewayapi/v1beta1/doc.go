@@ -0,0 +1,10 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+// +k8s:deepcopy-gen=package,register
+// +groupName=gateway.networking.k8s.io
+
+// Package v1beta1 holds the subset of the Kubernetes Gateway API v1beta1, which AGIC translates into App Gateway config.
+package v1beta1
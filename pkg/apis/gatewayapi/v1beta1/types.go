@@ -0,0 +1,437 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayClass describes a class of Gateways, implemented by the controller named in the spec.
+type GatewayClass struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewayClassSpec `json:"spec"`
+
+	// +optional
+	Status GatewayClassStatus `json:"status,omitempty"`
+}
+
+// GatewayClassSpec names the controller implementing the Gateways of the class.
+type GatewayClassSpec struct {
+	// ControllerName is the domain prefixed name of the controller managing the Gateways of the class.
+	ControllerName string `json:"controllerName"`
+
+	// +optional
+	Description *string `json:"description,omitempty"`
+}
+
+// GatewayClassStatus is the status the controller reports for the class.
+type GatewayClassStatus struct {
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayClassList is the list of GatewayClasses
+type GatewayClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []GatewayClass `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Gateway is an instance of a GatewayClass; Its listeners accept the traffic routed by the attached routes.
+type Gateway struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewaySpec `json:"spec"`
+
+	// +optional
+	Status GatewayStatus `json:"status,omitempty"`
+}
+
+// GatewaySpec defines the listeners of a Gateway.
+type GatewaySpec struct {
+	// GatewayClassName is the name of the GatewayClass of the Gateway.
+	GatewayClassName string `json:"gatewayClassName"`
+
+	Listeners []Listener `json:"listeners"`
+}
+
+// ProtocolType is the protocol a listener accepts.
+type ProtocolType string
+
+const (
+	// HTTPProtocolType accepts plain HTTP traffic.
+	HTTPProtocolType ProtocolType = "HTTP"
+
+	// HTTPSProtocolType accepts HTTP traffic over TLS.
+	HTTPSProtocolType ProtocolType = "HTTPS"
+)
+
+// Listener is a logical endpoint of a Gateway.
+type Listener struct {
+	// Name is unique among the listeners of the Gateway.
+	Name string `json:"name"`
+
+	// +optional
+	// Hostname of the listener; Any hostname when omitted
+	Hostname *string `json:"hostname,omitempty"`
+
+	Port int32 `json:"port"`
+
+	Protocol ProtocolType `json:"protocol"`
+
+	// +optional
+	TLS *GatewayTLSConfig `json:"tls,omitempty"`
+
+	// +optional
+	// AllowedRoutes restricts the routes, which may attach to the listener; Routes of the namespace of the Gateway when omitted
+	AllowedRoutes *AllowedRoutes `json:"allowedRoutes,omitempty"`
+}
+
+// TLSModeType is the TLS behavior of a listener.
+type TLSModeType string
+
+const (
+	// TLSModeTerminate terminates TLS at the Gateway.
+	TLSModeTerminate TLSModeType = "Terminate"
+
+	// TLSModePassthrough passes the TLS traffic through to the backends.
+	TLSModePassthrough TLSModeType = "Passthrough"
+)
+
+// GatewayTLSConfig holds the TLS settings of a listener.
+type GatewayTLSConfig struct {
+	// +optional
+	// Mode is Terminate when omitted
+	Mode *TLSModeType `json:"mode,omitempty"`
+
+	// +optional
+	CertificateRefs []SecretObjectReference `json:"certificateRefs,omitempty"`
+}
+
+// SecretObjectReference refers to a Secret holding a certificate.
+type SecretObjectReference struct {
+	// +optional
+	Group *string `json:"group,omitempty"`
+
+	// +optional
+	Kind *string `json:"kind,omitempty"`
+
+	Name string `json:"name"`
+
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+// FromNamespaces selects the namespaces, from which routes may attach to a listener.
+type FromNamespaces string
+
+const (
+	// NamespacesFromAll allows routes of all namespaces.
+	NamespacesFromAll FromNamespaces = "All"
+
+	// NamespacesFromSame allows routes of the namespace of the Gateway only.
+	NamespacesFromSame FromNamespaces = "Same"
+
+	// NamespacesFromSelector allows routes of the namespaces matching the selector.
+	NamespacesFromSelector FromNamespaces = "Selector"
+)
+
+// AllowedRoutes restricts the routes, which may attach to a listener.
+type AllowedRoutes struct {
+	// +optional
+	Namespaces *RouteNamespaces `json:"namespaces,omitempty"`
+}
+
+// RouteNamespaces selects the namespaces, from which routes may attach to a listener.
+type RouteNamespaces struct {
+	// +optional
+	From *FromNamespaces `json:"from,omitempty"`
+
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// GatewayStatus is the status the controller reports for a Gateway.
+type GatewayStatus struct {
+	// +optional
+	Addresses []GatewayAddress `json:"addresses,omitempty"`
+
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// +optional
+	Listeners []ListenerStatus `json:"listeners,omitempty"`
+}
+
+// GatewayAddress is an address the Gateway accepts traffic on.
+type GatewayAddress struct {
+	// +optional
+	Type *string `json:"type,omitempty"`
+
+	Value string `json:"value"`
+}
+
+// ListenerStatus is the status the controller reports for a listener.
+type ListenerStatus struct {
+	Name string `json:"name"`
+
+	SupportedKinds []RouteGroupKind `json:"supportedKinds"`
+
+	AttachedRoutes int32 `json:"attachedRoutes"`
+
+	Conditions []Condition `json:"conditions"`
+}
+
+// RouteGroupKind is a kind of route.
+type RouteGroupKind struct {
+	// +optional
+	Group *string `json:"group,omitempty"`
+
+	Kind string `json:"kind"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayList is the list of Gateways
+type GatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Gateway `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRoute routes HTTP requests accepted by the listeners of Gateways to Services.
+type HTTPRoute struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HTTPRouteSpec `json:"spec"`
+
+	// +optional
+	Status HTTPRouteStatus `json:"status,omitempty"`
+}
+
+// HTTPRouteSpec defines the Gateways an HTTPRoute attaches to, and its routing rules.
+type HTTPRouteSpec struct {
+	// +optional
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+
+	// +optional
+	// Hostnames of the route; The hostnames of the listeners when omitted
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// +optional
+	Rules []HTTPRouteRule `json:"rules,omitempty"`
+}
+
+// ParentReference refers to a Gateway, or one of its listeners.
+type ParentReference struct {
+	// +optional
+	Group *string `json:"group,omitempty"`
+
+	// +optional
+	Kind *string `json:"kind,omitempty"`
+
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	Name string `json:"name"`
+
+	// +optional
+	// SectionName is the name of the listener; All listeners when omitted
+	SectionName *string `json:"sectionName,omitempty"`
+
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+}
+
+// HTTPRouteRule routes the requests matching any of its matches to its backends.
+type HTTPRouteRule struct {
+	// +optional
+	Matches []HTTPRouteMatch `json:"matches,omitempty"`
+
+	// +optional
+	Filters []HTTPRouteFilter `json:"filters,omitempty"`
+
+	// +optional
+	BackendRefs []HTTPBackendRef `json:"backendRefs,omitempty"`
+}
+
+// PathMatchType is the way a path is matched.
+type PathMatchType string
+
+const (
+	// PathMatchExact matches the path exactly.
+	PathMatchExact PathMatchType = "Exact"
+
+	// PathMatchPathPrefix matches the path by its prefix, element by element.
+	PathMatchPathPrefix PathMatchType = "PathPrefix"
+
+	// PathMatchRegularExpression matches the path with a regular expression.
+	PathMatchRegularExpression PathMatchType = "RegularExpression"
+)
+
+// HTTPRouteMatch are the conditions a request must meet to match a rule.
+type HTTPRouteMatch struct {
+	// +optional
+	// Path matches the prefix "/" when omitted
+	Path *HTTPPathMatch `json:"path,omitempty"`
+
+	// +optional
+	Headers []HTTPHeaderMatch `json:"headers,omitempty"`
+
+	// +optional
+	QueryParams []HTTPQueryParamMatch `json:"queryParams,omitempty"`
+
+	// +optional
+	Method *string `json:"method,omitempty"`
+}
+
+// HTTPPathMatch matches the path of a request.
+type HTTPPathMatch struct {
+	// +optional
+	Type *PathMatchType `json:"type,omitempty"`
+
+	// +optional
+	Value *string `json:"value,omitempty"`
+}
+
+// HTTPHeaderMatch matches a header of a request.
+type HTTPHeaderMatch struct {
+	// +optional
+	Type *string `json:"type,omitempty"`
+
+	Name string `json:"name"`
+
+	Value string `json:"value"`
+}
+
+// HTTPQueryParamMatch matches a query parameter of a request.
+type HTTPQueryParamMatch struct {
+	// +optional
+	Type *string `json:"type,omitempty"`
+
+	Name string `json:"name"`
+
+	Value string `json:"value"`
+}
+
+// HTTPRouteFilter modifies a request or its response; AGIC reports the filters, but does not apply them.
+type HTTPRouteFilter struct {
+	Type string `json:"type"`
+}
+
+// HTTPBackendRef refers to the backend requests are routed to.
+type HTTPBackendRef struct {
+	BackendRef `json:",inline"`
+
+	// +optional
+	Filters []HTTPRouteFilter `json:"filters,omitempty"`
+}
+
+// BackendRef refers to a backend and the share of the requests it receives.
+type BackendRef struct {
+	BackendObjectReference `json:",inline"`
+
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// BackendObjectReference refers to a Service.
+type BackendObjectReference struct {
+	// +optional
+	Group *string `json:"group,omitempty"`
+
+	// +optional
+	Kind *string `json:"kind,omitempty"`
+
+	Name string `json:"name"`
+
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+}
+
+// HTTPRouteStatus is the status the controllers report for each Gateway an HTTPRoute attaches to.
+type HTTPRouteStatus struct {
+	// +optional
+	Parents []RouteParentStatus `json:"parents,omitempty"`
+}
+
+// RouteParentStatus is the status of the route with regard to one of its parents.
+type RouteParentStatus struct {
+	ParentRef ParentReference `json:"parentRef"`
+
+	ControllerName string `json:"controllerName"`
+
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRouteList is the list of HTTPRoutes
+type HTTPRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []HTTPRoute `json:"items"`
+}
+
+// ConditionStatus is the status of a condition.
+type ConditionStatus string
+
+const (
+	// ConditionTrue means the condition holds.
+	ConditionTrue ConditionStatus = "True"
+
+	// ConditionFalse means the condition does not hold.
+	ConditionFalse ConditionStatus = "False"
+
+	// ConditionUnknown means the controller can not tell whether the condition holds.
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// Condition is an observation of the state of a Gateway API resource.
+type Condition struct {
+	Type string `json:"type"`
+
+	Status ConditionStatus `json:"status"`
+
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	Reason string `json:"reason"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
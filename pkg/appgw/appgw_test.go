@@ -20,6 +20,7 @@ import (
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gateway_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
 	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
@@ -417,7 +418,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 		// Create a `k8scontext` to start listiening to ingress resources.
 
-		ctxt = k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, []string{ingressNS}, 1000*time.Second)
+		ctxt = k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, gateway_fake.NewSimpleClientset(), []string{ingressNS}, nil, 1000*time.Second)
 		Expect(ctxt).ShouldNot(BeNil(), "Unable to create `k8scontext`")
 
 		// Initialize the `ConfigBuilder`
@@ -452,7 +453,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 	Context("Tests Application Gateway Configuration", func() {
 		It("Should be able to create Application Gateway Configuration from Ingress", func() {
 			// Start the informers. This will sync the cache with the latest ingress.
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			// Wait for the controller to receive an ingress update.
 			ingressEvent()
@@ -500,7 +501,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			Expect(err).Should(BeNil(), "Unable to delete endpoint resource due to: %v", err)
 
 			// Start the informers. This will sync the cache with the latest ingress.
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			// Wait for the controller to receive an ingress update.
 			ingressEvent()
@@ -611,7 +612,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			Expect(err).Should(BeNil(), "Unabled to update ingress resource due to: %v", err)
 
 			// Start the informers. This will sync the cache with the latest ingress.
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			// Wait for the controller to receive an ingress update.
 			ingressEvent()
@@ -716,7 +717,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			Expect(err).Should(BeNil(), "Unable to update ingress resource due to: %v", err)
 
 			// Start the informers. This will sync the cache with the latest ingress.
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			// Wait for the controller to receive an ingress update.
 			ingressEvent()
@@ -802,7 +803,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 	Context("Tests Application Gateway Generate HTTP Settings Name", func() {
 		It("Should be create an Application Gateway Backend Pool Name With Less than 80 Characters", func() {
 			// Start the informers. This will sync the cache with the latest ingress.
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			// Wait for the controller to receive an ingress update.
 			ingressEvent()
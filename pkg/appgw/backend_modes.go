@@ -0,0 +1,167 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+)
+
+// getBackendMode returns the addresses App Gateway sends the requests of the Ingress to: The backend mode the Ingress is
+// annotated with, else the one AGIC is configured with, else the pod IPs.
+func getBackendMode(ingress *v1beta1.Ingress, env environment.EnvVariables) string {
+	if mode, err := annotations.BackendMode(ingress); err == nil {
+		return mode
+	}
+	if annotations.IsBackendMode(env.BackendMode) {
+		return env.BackendMode
+	}
+	return annotations.BackendModePod
+}
+
+// getServicePort returns the TCP port of the Service the backend of the Ingress references.
+func getServicePort(service *v1.Service, backendID backendIdentifier) *v1.ServicePort {
+	for idx := range service.Spec.Ports {
+		sp := &service.Spec.Ports[idx]
+		if sp.Protocol != v1.ProtocolTCP {
+			continue
+		}
+		if fmt.Sprint(sp.Port) == backendID.Backend.ServicePort.String() ||
+			sp.Name == backendID.Backend.ServicePort.String() ||
+			sp.TargetPort.String() == backendID.Backend.ServicePort.String() {
+			return sp
+		}
+	}
+	return nil
+}
+
+// getBackendModeServicePort resolves the port App Gateway sends the requests of the backend to, when it reaches the backend
+// through its Service: The node port in the nodeport mode, the port of the Service otherwise. Services without a node port
+// keep the port of the Service; Their backend ends up in the default pool.
+func getBackendModeServicePort(service *v1.Service, backendID backendIdentifier, mode string) *serviceBackendPortPair {
+	sp := getServicePort(service, backendID)
+	if sp == nil {
+		return nil
+	}
+	if mode == annotations.BackendModeNodePort && sp.NodePort != 0 {
+		return &serviceBackendPortPair{
+			ServicePort: sp.Port,
+			BackendPort: sp.NodePort,
+		}
+	}
+	return &serviceBackendPortPair{
+		ServicePort: sp.Port,
+		BackendPort: sp.Port,
+	}
+}
+
+// getBackendModeAddressPool creates the pool of a backend App Gateway reaches through its Service rather than its pods;
+// Nil when the Service offers no addresses for the backend mode.
+func (c *appGwConfigBuilder) getBackendModeAddressPool(backendID backendIdentifier, serviceBackendPair serviceBackendPortPair, service *v1.Service, mode string, addressPools map[string]*n.ApplicationGatewayBackendAddressPool) *n.ApplicationGatewayBackendAddressPool {
+	// The mode is part of the name; Ingresses reaching the same Service in different modes need pools of their own.
+	poolName := generateAddressPoolName(fmt.Sprintf("%s-%s", backendID.serviceFullName(), mode), backendID.Backend.ServicePort.String(), serviceBackendPair.BackendPort)
+	if pool, ok := addressPools[poolName]; ok {
+		return pool
+	}
+
+	if canaryService, _, isCanary := getCanary(backendID.Ingress); isCanary && canaryService != backendID.Name {
+		logLine := fmt.Sprintf("Ingress %s/%s reaches its backends in the %s backend mode; Requests are not split with canary service %s", backendID.Ingress.Namespace, backendID.Ingress.Name, mode, canaryService)
+		glog.Warning(logLine)
+		c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonTrafficSplit, logLine)
+	}
+
+	var addresses []n.ApplicationGatewayBackendAddress
+	var missing string
+	switch mode {
+	case annotations.BackendModeClusterIP:
+		if service.Spec.ClusterIP != "" && service.Spec.ClusterIP != v1.ClusterIPNone {
+			addresses = append(addresses, n.ApplicationGatewayBackendAddress{IPAddress: to.StringPtr(service.Spec.ClusterIP)})
+		}
+		missing = "has no cluster IP"
+	case annotations.BackendModeLoadBalancer:
+		if service.Spec.Type == v1.ServiceTypeLoadBalancer {
+			addresses = getLoadBalancerAddresses(service)
+		}
+		missing = "is not a LoadBalancer Service with an IP"
+	case annotations.BackendModeNodePort:
+		if sp := getServicePort(service, backendID); sp != nil && sp.NodePort != 0 {
+			addresses = getNodeAddresses(c.k8sContext.ListNodes())
+			missing = "is reachable through no ready node"
+		} else {
+			missing = fmt.Sprintf("has no node port for port %s", backendID.Backend.ServicePort.String())
+		}
+	}
+
+	if len(addresses) == 0 {
+		logLine := fmt.Sprintf("Service %s %s; Ingress %s/%s cannot reach it in the %s backend mode", backendID.serviceKey(), missing, backendID.Ingress.Namespace, backendID.Ingress.Name, mode)
+		glog.Error(logLine)
+		c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonBackendModeUnavailable, logLine)
+		return nil
+	}
+	glog.V(5).Infof("Backend pool %s reaches Service %s in the %s backend mode", poolName, backendID.serviceKey(), mode)
+	return c.newPoolWithAddresses(poolName, getUniqueAddresses(addresses))
+}
+
+// getLoadBalancerAddresses returns the ingress points of the load balancer of the Service.
+func getLoadBalancerAddresses(service *v1.Service) []n.ApplicationGatewayBackendAddress {
+	var addresses []n.ApplicationGatewayBackendAddress
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if len(ingress.IP) != 0 {
+			addresses = append(addresses, n.ApplicationGatewayBackendAddress{IPAddress: to.StringPtr(ingress.IP)})
+		} else if len(ingress.Hostname) != 0 {
+			addresses = append(addresses, n.ApplicationGatewayBackendAddress{Fqdn: to.StringPtr(ingress.Hostname)})
+		}
+	}
+	return addresses
+}
+
+// getNodeAddresses returns the internal IPs of the ready nodes; kube-proxy on each of them forwards node ports to the pods.
+func getNodeAddresses(nodes []*v1.Node) []n.ApplicationGatewayBackendAddress {
+	var addresses []n.ApplicationGatewayBackendAddress
+	for _, node := range nodes {
+		if !k8scontext.IsNodeReady(node) {
+			continue
+		}
+		for _, address := range node.Status.Addresses {
+			if address.Type == v1.NodeInternalIP && len(address.Address) != 0 {
+				addresses = append(addresses, n.ApplicationGatewayBackendAddress{IPAddress: to.StringPtr(address.Address)})
+			}
+		}
+	}
+	return addresses
+}
+
+// getUniqueAddresses removes the duplicate addresses and sorts the rest.
+func getUniqueAddresses(addresses []n.ApplicationGatewayBackendAddress) *[]n.ApplicationGatewayBackendAddress {
+	ips := make(map[string]interface{})
+	fqdns := make(map[string]interface{})
+	for _, address := range addresses {
+		if address.IPAddress != nil {
+			ips[*address.IPAddress] = nil
+		} else if address.Fqdn != nil {
+			fqdns[*address.Fqdn] = nil
+		}
+	}
+
+	addrSet := make(map[n.ApplicationGatewayBackendAddress]interface{})
+	for ip := range ips {
+		addrSet[n.ApplicationGatewayBackendAddress{IPAddress: to.StringPtr(ip)}] = nil
+	}
+	for fqdn := range fqdns {
+		addrSet[n.ApplicationGatewayBackendAddress{Fqdn: to.StringPtr(fqdn)}] = nil
+	}
+	return getBackendAddressMapKeys(&addrSet)
+}
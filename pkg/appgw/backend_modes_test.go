@@ -0,0 +1,193 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+// appgw_suite_test.go launches these Ginkgo tests
+
+var _ = Describe("Test the backend modes", func() {
+	newNode := func(name string, ip string, ready v1.ConditionStatus) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1.NodeStatus{
+				Addresses: []v1.NodeAddress{
+					{Type: v1.NodeHostName, Address: name},
+					{Type: v1.NodeInternalIP, Address: ip},
+				},
+				Conditions: []v1.NodeCondition{
+					{Type: v1.NodeReady, Status: ready},
+				},
+			},
+		}
+	}
+
+	newService := func() *v1.Service {
+		service := tests.NewServiceFixture(*tests.NewServicePortsFixture()...)
+		service.Spec.Type = v1.ServiceTypeLoadBalancer
+		service.Spec.ClusterIP = "10.0.0.10"
+		service.Spec.Ports[0].NodePort = 30080
+		service.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{IP: "10.240.0.100"}}
+		return service
+	}
+
+	newIngress := func(mode string) *v1beta1.Ingress {
+		ingress := tests.NewIngressFixture()
+		ingress.Spec.Rules = []v1beta1.IngressRule{
+			tests.NewIngressRuleFixture(tests.Host, tests.URLPath1, *tests.NewIngressBackendFixture(tests.ServiceName, 80)),
+		}
+		if mode != "" {
+			ingress.Annotations[annotations.BackendModeKey] = mode
+		}
+		return ingress
+	}
+
+	newFixture := func(service *v1.Service, ingress *v1beta1.Ingress, env environment.EnvVariables) (appGwConfigBuilder, *ConfigBuilderContext) {
+		cb := newConfigBuilderFixture(nil)
+		_ = cb.k8sContext.Caches.Service.Add(service)
+		_ = cb.k8sContext.Caches.Endpoints.Add(tests.NewEndpointsFixture())
+		_ = cb.k8sContext.Caches.Pods.Add(tests.NewPodFixture(tests.ServiceName, tests.Namespace, tests.ContainerName, tests.ContainerPort))
+		_ = cb.k8sContext.Caches.Nodes.Add(newNode("node-1", "10.240.0.5", v1.ConditionTrue))
+		_ = cb.k8sContext.Caches.Nodes.Add(newNode("node-2", "10.240.0.4", v1.ConditionTrue))
+		_ = cb.k8sContext.Caches.Nodes.Add(newNode("node-3", "10.240.0.6", v1.ConditionFalse))
+		cbCtx := &ConfigBuilderContext{
+			ServiceList:  []*v1.Service{service},
+			IngressList:  []*v1beta1.Ingress{ingress},
+			EnvVariables: env,
+		}
+		return cb, cbCtx
+	}
+
+	// getBackend returns the port of the HTTP settings, the addresses of the pool and the probe of the single backend.
+	getBackend := func(cb appGwConfigBuilder, cbCtx *ConfigBuilderContext) (int32, []n.ApplicationGatewayBackendAddress, *n.ApplicationGatewayProbe) {
+		_, settingsMap, _, _ := cb.getBackendsAndSettingsMap(cbCtx)
+		_, probesMap := cb.newProbesMap(cbCtx)
+		for backendID, settings := range settingsMap {
+			pool := cb.newBackendPoolMap(cbCtx)[backendID]
+			var addresses []n.ApplicationGatewayBackendAddress
+			if pool.BackendAddresses != nil {
+				addresses = *pool.BackendAddresses
+			}
+			return *settings.Port, addresses, probesMap[backendID]
+		}
+		return 0, nil, nil
+	}
+
+	Context("ensure the backend mode is resolved", func() {
+		It("should prefer the annotation of the Ingress over the environment", func() {
+			env := environment.GetFakeEnv()
+			Expect(getBackendMode(newIngress(""), env)).To(Equal(annotations.BackendModePod))
+
+			env.BackendMode = annotations.BackendModeNodePort
+			Expect(getBackendMode(newIngress(""), env)).To(Equal(annotations.BackendModeNodePort))
+			Expect(getBackendMode(newIngress(annotations.BackendModeClusterIP), env)).To(Equal(annotations.BackendModeClusterIP))
+			Expect(getBackendMode(newIngress("hostnetwork"), env)).To(Equal(annotations.BackendModeNodePort))
+		})
+	})
+
+	Context("ensure the pod backend mode reaches the pods", func() {
+		cb, cbCtx := newFixture(newService(), newIngress(annotations.BackendModePod), environment.GetFakeEnv())
+		port, addresses, _ := getBackend(cb, cbCtx)
+
+		It("should send the requests to the target port of the pod IPs", func() {
+			Expect(port).To(Equal(tests.ContainerPort))
+			Expect(addresses).To(Equal([]n.ApplicationGatewayBackendAddress{{IPAddress: to.StringPtr("10.9.8.7")}}))
+		})
+	})
+
+	Context("ensure the clusterip backend mode reaches the cluster IP", func() {
+		cb, cbCtx := newFixture(newService(), newIngress(annotations.BackendModeClusterIP), environment.GetFakeEnv())
+		port, addresses, probe := getBackend(cb, cbCtx)
+
+		It("should send the requests to the port of the Service", func() {
+			Expect(port).To(Equal(int32(80)))
+			Expect(addresses).To(Equal([]n.ApplicationGatewayBackendAddress{{IPAddress: to.StringPtr("10.0.0.10")}}))
+		})
+
+		It("should keep the readiness probe of the container port the Service targets", func() {
+			Expect(probe).ToNot(BeNil())
+			Expect(probe.Path).To(Equal(to.StringPtr(tests.HealthPath)))
+		})
+
+		It("should keep the pools of the backend modes apart", func() {
+			pools := cb.getPools(cbCtx)
+			var names []string
+			for _, pool := range pools {
+				names = append(names, *pool.Name)
+			}
+			Expect(names).To(ContainElement(generateAddressPoolName(tests.Namespace+"-"+tests.ServiceName+"-clusterip", "80", 80)))
+		})
+	})
+
+	Context("ensure the nodeport backend mode reaches the ready nodes", func() {
+		env := environment.GetFakeEnv()
+		env.BackendMode = annotations.BackendModeNodePort
+		cb, cbCtx := newFixture(newService(), newIngress(""), env)
+		port, addresses, _ := getBackend(cb, cbCtx)
+
+		It("should send the requests to the node port of the internal IPs of the ready nodes", func() {
+			Expect(port).To(Equal(int32(30080)))
+			Expect(addresses).To(Equal([]n.ApplicationGatewayBackendAddress{
+				{IPAddress: to.StringPtr("10.240.0.4")},
+				{IPAddress: to.StringPtr("10.240.0.5")},
+			}))
+		})
+	})
+
+	Context("ensure the loadbalancer backend mode reaches the load balancer IP", func() {
+		cb, cbCtx := newFixture(newService(), newIngress(annotations.BackendModeLoadBalancer), environment.GetFakeEnv())
+		port, addresses, _ := getBackend(cb, cbCtx)
+
+		It("should send the requests to the port of the Service", func() {
+			Expect(port).To(Equal(int32(80)))
+			Expect(addresses).To(Equal([]n.ApplicationGatewayBackendAddress{{IPAddress: to.StringPtr("10.240.0.100")}}))
+		})
+	})
+
+	Context("ensure backends the backend mode cannot reach fall back to the default pool", func() {
+		service := newService()
+		service.Spec.Type = v1.ServiceTypeClusterIP
+		service.Spec.ClusterIP = v1.ClusterIPNone
+		service.Spec.Ports[0].NodePort = 0
+		service.Status.LoadBalancer.Ingress = nil
+
+		for _, mode := range []string{annotations.BackendModeClusterIP, annotations.BackendModeNodePort, annotations.BackendModeLoadBalancer} {
+			mode := mode
+			It("should warn in the "+mode+" backend mode", func() {
+				cb, cbCtx := newFixture(service, newIngress(mode), environment.GetFakeEnv())
+				port, _, _ := getBackend(cb, cbCtx)
+				Expect(port).To(Equal(int32(80)))
+
+				for _, pool := range cb.newBackendPoolMap(cbCtx) {
+					Expect(*pool.Name).To(Equal(*defaultBackendAddressPool(cb.appGwIdentifier).Name))
+				}
+
+				var reasons []string
+				for len(cb.recorder.(*record.FakeRecorder).Events) > 0 {
+					event := <-cb.recorder.(*record.FakeRecorder).Events
+					reasons = append(reasons, strings.Fields(event)[1])
+				}
+				Expect(reasons).To(ContainElement(events.ReasonBackendModeUnavailable))
+			})
+		}
+	})
+})
@@ -14,6 +14,7 @@ import (
 	"github.com/golang/glog"
 	v1 "k8s.io/api/core/v1"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
@@ -36,7 +37,7 @@ func (c appGwConfigBuilder) getPools(cbCtx *ConfigBuilderContext) []n.Applicatio
 	_, _, serviceBackendPairMap, _ := c.getBackendsAndSettingsMap(cbCtx)
 	for backendID, serviceBackendPair := range serviceBackendPairMap {
 		glog.V(5).Info("Constructing backend pool for service:", backendID.serviceKey())
-		if pool := c.getBackendAddressPool(backendID, serviceBackendPair, managedPoolsByName); pool != nil {
+		if pool := c.getBackendAddressPool(backendID, serviceBackendPair, managedPoolsByName, cbCtx); pool != nil {
 			managedPoolsByName[*pool.Name] = pool
 		}
 	}
@@ -45,10 +46,13 @@ func (c appGwConfigBuilder) getPools(cbCtx *ConfigBuilderContext) []n.Applicatio
 		_, _, istioServiceBackendPairMap, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
 		for destinationID, serviceBackendPair := range istioServiceBackendPairMap {
 			glog.V(5).Info("Constructing backend pool for service:", destinationID.serviceKey())
-			if pool := c.getIstioBackendAddressPool(destinationID, serviceBackendPair, managedPoolsByName); pool != nil {
+			if pool := c.getIstioBackendAddressPool(destinationID, serviceBackendPair, managedPoolsByName, cbCtx); pool != nil {
 				managedPoolsByName[*pool.Name] = pool
 			}
 		}
+		for _, pool := range c.getIstioWeightedPools(cbCtx) {
+			managedPoolsByName[*pool.Name] = pool
+		}
 	}
 
 	var agicCreatedPools []n.ApplicationGatewayBackendAddressPool
@@ -57,7 +61,7 @@ func (c appGwConfigBuilder) getPools(cbCtx *ConfigBuilderContext) []n.Applicatio
 	}
 
 	if cbCtx.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, &defaultPool)
+		er := c.newExistingResources(cbCtx, &defaultPool)
 
 		// Split the existing pools we obtained from App Gateway into ones AGIC is and is not allowed to change.
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedPools()
@@ -81,14 +85,20 @@ func (c *appGwConfigBuilder) newBackendPoolMap(cbCtx *ConfigBuilderContext) map[
 	_, _, serviceBackendPairMap, _ := c.getBackendsAndSettingsMap(cbCtx)
 	for backendID, serviceBackendPair := range serviceBackendPairMap {
 		backendPoolMap[backendID] = &defaultPool
-		if pool := c.getBackendAddressPool(backendID, serviceBackendPair, addressPools); pool != nil {
+		if pool := c.getBackendAddressPool(backendID, serviceBackendPair, addressPools, cbCtx); pool != nil {
 			backendPoolMap[backendID] = pool
 		}
 	}
 	return backendPoolMap
 }
 
-func (c *appGwConfigBuilder) getBackendAddressPool(backendID backendIdentifier, serviceBackendPair serviceBackendPortPair, addressPools map[string]*n.ApplicationGatewayBackendAddressPool) *n.ApplicationGatewayBackendAddressPool {
+func (c *appGwConfigBuilder) getBackendAddressPool(backendID backendIdentifier, serviceBackendPair serviceBackendPortPair, addressPools map[string]*n.ApplicationGatewayBackendAddressPool, cbCtx *ConfigBuilderContext) *n.ApplicationGatewayBackendAddressPool {
+	if service := c.k8sContext.GetService(backendID.serviceKey()); isExternalNameService(service) {
+		return c.getExternalNameBackendAddressPool(backendID, serviceBackendPair, service, addressPools)
+	} else if mode := getBackendMode(backendID.Ingress, cbCtx.EnvVariables); service != nil && mode != annotations.BackendModePod {
+		return c.getBackendModeAddressPool(backendID, serviceBackendPair, service, mode, addressPools)
+	}
+
 	endpoints, err := c.k8sContext.GetEndpointsByService(backendID.serviceKey())
 	if err != nil {
 		logLine := fmt.Sprintf("Failed fetching endpoints for service: %s", backendID.serviceKey())
@@ -102,6 +112,12 @@ func (c *appGwConfigBuilder) getBackendAddressPool(backendID backendIdentifier,
 			poolName := generateAddressPoolName(backendID.serviceFullName(), backendID.Backend.ServicePort.String(), serviceBackendPair.BackendPort)
 			// The same service might be referenced in multiple ingress resources, this might result in multiple `serviceBackendPairMap` having the same service key but different
 			// ingress resource. Thus, while generating the backend address pool, we should make sure that we are generating unique backend address pools.
+			// A canary splits the requests of the backend through a pool of its own.
+			if canaryService, canaryWeight, isCanary := getCanary(backendID.Ingress); isCanary && canaryService != backendID.Name {
+				if pool := c.getCanaryBackendAddressPool(backendID, serviceBackendPair, subset, canaryService, canaryWeight, addressPools); pool != nil {
+					return pool
+				}
+			}
 			if pool, ok := addressPools[poolName]; ok {
 				return pool
 			}
@@ -114,6 +130,23 @@ func (c *appGwConfigBuilder) getBackendAddressPool(backendID backendIdentifier,
 	return nil
 }
 
+// isExternalNameService figures out whether the Service is an alias of a DNS name outside of the cluster; These have no Endpoints.
+func isExternalNameService(service *v1.Service) bool {
+	return service != nil && service.Spec.Type == v1.ServiceTypeExternalName && service.Spec.ExternalName != ""
+}
+
+// getExternalNameBackendAddressPool creates the pool of an ExternalName Service; Its single backend address is the DNS name the Service aliases.
+func (c *appGwConfigBuilder) getExternalNameBackendAddressPool(backendID backendIdentifier, serviceBackendPair serviceBackendPortPair, service *v1.Service, addressPools map[string]*n.ApplicationGatewayBackendAddressPool) *n.ApplicationGatewayBackendAddressPool {
+	poolName := generateAddressPoolName(backendID.serviceFullName(), backendID.Backend.ServicePort.String(), serviceBackendPair.BackendPort)
+	if pool, ok := addressPools[poolName]; ok {
+		return pool
+	}
+	glog.V(5).Infof("Service %s is an ExternalName Service; Backend pool %s targets %s", backendID.serviceKey(), poolName, service.Spec.ExternalName)
+	return c.newPoolWithAddresses(poolName, &[]n.ApplicationGatewayBackendAddress{
+		{Fqdn: to.StringPtr(service.Spec.ExternalName)},
+	})
+}
+
 func getUniqueTCPPorts(subset v1.EndpointSubset) map[int32]interface{} {
 	ports := make(map[int32]interface{})
 	for _, endpointsPort := range subset.Ports {
@@ -125,12 +158,16 @@ func getUniqueTCPPorts(subset v1.EndpointSubset) map[int32]interface{} {
 }
 
 func (c *appGwConfigBuilder) newPool(poolName string, subset v1.EndpointSubset) *n.ApplicationGatewayBackendAddressPool {
+	return c.newPoolWithAddresses(poolName, getAddressesForSubset(subset))
+}
+
+func (c *appGwConfigBuilder) newPoolWithAddresses(poolName string, addresses *[]n.ApplicationGatewayBackendAddress) *n.ApplicationGatewayBackendAddressPool {
 	return &n.ApplicationGatewayBackendAddressPool{
 		Etag: to.StringPtr("*"),
 		Name: &poolName,
 		ID:   to.StringPtr(c.appGwIdentifier.addressPoolID(poolName)),
 		ApplicationGatewayBackendAddressPoolPropertiesFormat: &n.ApplicationGatewayBackendAddressPoolPropertiesFormat{
-			BackendAddresses: getAddressesForSubset(subset),
+			BackendAddresses: addresses,
 		},
 	}
 }
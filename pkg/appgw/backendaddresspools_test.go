@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
@@ -134,7 +135,7 @@ var _ = Describe("Test the creation of Backend Pools from Ingress definition", f
 		}
 
 		// -- Action --
-		actual := cb.getBackendAddressPool(backendID, serviceBackendPair, addressPools)
+		actual := cb.getBackendAddressPool(backendID, serviceBackendPair, addressPools, cbCtx)
 
 		It("should have constructed correct ApplicationGatewayBackendAddressPool", func() {
 			// The order here is deliberate -- ensure this is properly sorted
@@ -157,4 +158,76 @@ var _ = Describe("Test the creation of Backend Pools from Ingress definition", f
 			Expect(*actual).To(Equal(expected))
 		})
 	})
+
+	Context("ensure ExternalName Services get a pool of their external DNS name", func() {
+		externalName := "app.azurewebsites.net"
+		service := tests.NewServiceFixture()
+		service.Spec.Type = v1.ServiceTypeExternalName
+		service.Spec.ExternalName = externalName
+		service.Spec.Selector = nil
+
+		ingress := tests.NewIngressFixture()
+		ingress.Spec.Rules = []v1beta1.IngressRule{
+			tests.NewIngressRuleFixture(tests.Host, tests.URLPath1, *tests.NewIngressBackendFixture(tests.ServiceName, 443)),
+		}
+
+		cb := newConfigBuilderFixture(nil)
+		_ = cb.k8sContext.Caches.Service.Add(service)
+		pod := tests.NewPodFixture(tests.ServiceName, tests.Namespace, tests.ContainerName, tests.ContainerPort)
+		_ = cb.k8sContext.Caches.Pods.Add(pod)
+
+		cbCtx := &ConfigBuilderContext{
+			ServiceList: []*v1.Service{service},
+			IngressList: []*v1beta1.Ingress{ingress},
+		}
+
+		// -- Action --
+		_ = cb.BackendAddressPools(cbCtx)
+		_ = cb.HealthProbesCollection(cbCtx)
+		httpSettings, _, serviceBackendPairs, _ := cb.getBackendsAndSettingsMap(cbCtx)
+
+		It("should target the external DNS name on the port of the Ingress backend", func() {
+			expectedPoolName := generateAddressPoolName(tests.Namespace+"-"+tests.ServiceName, "443", 443)
+			var actual *n.ApplicationGatewayBackendAddressPool
+			for idx := range *cb.appGw.BackendAddressPools {
+				if *(*cb.appGw.BackendAddressPools)[idx].Name == expectedPoolName {
+					actual = &(*cb.appGw.BackendAddressPools)[idx]
+				}
+			}
+			Expect(actual).ToNot(BeNil())
+			Expect(*actual.BackendAddresses).To(Equal([]n.ApplicationGatewayBackendAddress{
+				{Fqdn: to.StringPtr(externalName)},
+			}))
+			Expect(cb.recorder.(*record.FakeRecorder).Events).To(BeEmpty())
+
+			for _, pair := range serviceBackendPairs {
+				Expect(pair).To(Equal(serviceBackendPortPair{ServicePort: 443, BackendPort: 443}))
+			}
+		})
+
+		It("should send the external DNS name rather than the host of the Ingress as the Host header", func() {
+			var settings []n.ApplicationGatewayBackendHTTPSettings
+			for _, setting := range httpSettings {
+				if *setting.Name != defaultBackendHTTPSettingsName {
+					settings = append(settings, setting)
+				}
+			}
+			Expect(len(settings)).To(Equal(1))
+			Expect(settings[0].PickHostNameFromBackendAddress).To(Equal(to.BoolPtr(true)))
+			Expect(settings[0].HostName).To(BeNil())
+		})
+
+		It("should probe the external DNS name rather than the pods or the host of the Ingress", func() {
+			var probes []n.ApplicationGatewayProbe
+			for _, probe := range *cb.appGw.Probes {
+				if *probe.Name != defaultProbeName {
+					probes = append(probes, probe)
+				}
+			}
+			Expect(len(probes)).To(Equal(1))
+			Expect(probes[0].Host).To(BeNil())
+			Expect(probes[0].PickHostNameFromBackendHTTPSettings).To(Equal(to.BoolPtr(true)))
+			Expect(probes[0].Path).To(Equal(to.StringPtr(tests.URLPath1)))
+		})
+	})
 })
@@ -30,7 +30,7 @@ func (c *appGwConfigBuilder) BackendHTTPSettingsCollection(cbCtx *ConfigBuilderC
 	agicHTTPSettings, _, _, err := c.getBackendsAndSettingsMap(cbCtx)
 
 	if cbCtx.EnableBrownfieldDeployment {
-		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		rCtx := c.newExistingResources(cbCtx, nil)
 		allExistingSettings := rCtx.HTTPSettings
 
 		// PathMaps we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
@@ -125,6 +125,14 @@ func (c *appGwConfigBuilder) getBackendsAndSettingsMap(cbCtx *ConfigBuilderConte
 				BackendPort: backendID.Backend.ServicePort.IntVal,
 			}
 			resolvedBackendPorts[pair] = nil
+		} else if isExternalNameService(service) {
+			if pair := getExternalNameServicePort(service, backendID); pair != nil {
+				resolvedBackendPorts[*pair] = nil
+			}
+		} else if mode := getBackendMode(backendID.Ingress, cbCtx.EnvVariables); mode != annotations.BackendModePod {
+			if pair := getBackendModeServicePort(service, backendID, mode); pair != nil {
+				resolvedBackendPorts[*pair] = nil
+			}
 		} else {
 			for _, sp := range service.Spec.Ports {
 				// find the backend port number
@@ -229,6 +237,29 @@ func (c *appGwConfigBuilder) getBackendsAndSettingsMap(cbCtx *ConfigBuilderConte
 	return httpSettings, backendHTTPSettingsMap, finalServiceBackendPairMap, nil
 }
 
+// getExternalNameServicePort resolves the port of the backend of an ExternalName Service; The external host serves the port of the Service,
+// or the port the Ingress gives by number when the Service declares no ports. Target ports do not apply to ExternalName Services.
+func getExternalNameServicePort(service *v1.Service, backendID backendIdentifier) *serviceBackendPortPair {
+	for _, sp := range service.Spec.Ports {
+		if sp.Protocol != v1.ProtocolTCP {
+			continue
+		}
+		if fmt.Sprint(sp.Port) == backendID.Backend.ServicePort.String() || sp.Name == backendID.Backend.ServicePort.String() {
+			return &serviceBackendPortPair{
+				ServicePort: sp.Port,
+				BackendPort: sp.Port,
+			}
+		}
+	}
+	if backendID.Backend.ServicePort.Type == intstr.Int && backendID.Backend.ServicePort.IntVal != 0 {
+		return &serviceBackendPortPair{
+			ServicePort: backendID.Backend.ServicePort.IntVal,
+			BackendPort: backendID.Backend.ServicePort.IntVal,
+		}
+	}
+	return nil
+}
+
 func (c *appGwConfigBuilder) generateHTTPSettings(backendID backendIdentifier, port int32, cbCtx *ConfigBuilderContext) n.ApplicationGatewayBackendHTTPSettings {
 	httpSettingsName := generateHTTPSettingsName(backendID.serviceFullName(), backendID.Backend.ServicePort.String(), port, backendID.Ingress.Name)
 	glog.V(5).Infof("Created a new HTTP setting w/ name: %s\n", httpSettingsName)
@@ -274,5 +305,10 @@ func (c *appGwConfigBuilder) generateHTTPSettings(backendID backendIdentifier, p
 		httpSettings.RequestTimeout = to.Int32Ptr(reqTimeout)
 	}
 
+	// External hosts, like App Service, serve requests for their own name only; The Host header of the Ingress would get a 404.
+	if isExternalNameService(c.k8sContext.GetService(backendID.serviceKey())) {
+		httpSettings.PickHostNameFromBackendAddress = to.BoolPtr(true)
+	}
+
 	return httpSettings
 }
@@ -9,11 +9,13 @@ import (
 	"encoding/base64"
 	"fmt"
 	"sort"
+	"strings"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
@@ -30,15 +32,29 @@ func (c *appGwConfigBuilder) getSslCertificates(cbCtx *ConfigBuilderContext) *[]
 		}
 	}
 
+	if cbCtx.EnableIstioIntegration {
+		for k, v := range c.getIstioSecretToCertificateMap(cbCtx) {
+			secretIDCertificateMap[k] = v
+		}
+	}
+
 	var sslCertificates []n.ApplicationGatewaySslCertificate
 	for secretID, cert := range secretIDCertificateMap {
 		sslCertificates = append(sslCertificates, c.newCert(secretID, cert))
 	}
 
 	if cbCtx.EnableBrownfieldDeployment {
-		// MergePools would produce unique list of pools based on Name. Blacklisted pools, which have the same name
-		// as a managed pool would be overwritten.
-		sslCertificates = brownfield.MergeCerts(*c.appGw.SslCertificates, sslCertificates)
+		er := c.newExistingResources(cbCtx, nil)
+
+		// Certificates we obtained from App Gateway - we segment them into ones AGIC must retain, and ones AGIC created
+		// for secrets, which are no longer referenced by any Ingress.
+		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedCertificates(c.newAGICCertificateNameMatcher())
+
+		brownfield.LogCertificates(existingBlacklisted, existingNonBlacklisted, sslCertificates)
+
+		// MergeCerts would produce unique list of certificates based on Name. Blacklisted certificates, which have the same name
+		// as a managed certificate would be overwritten.
+		sslCertificates = brownfield.MergeCerts(existingBlacklisted, sslCertificates)
 	}
 
 	sort.Sort(sorter.ByCertificateName(sslCertificates))
@@ -52,10 +68,7 @@ func (c *appGwConfigBuilder) getSecretToCertificateMap(ingress *v1beta1.Ingress)
 			continue
 		}
 
-		tlsSecret := secretIdentifier{
-			Name:      tls.SecretName,
-			Namespace: ingress.Namespace,
-		}
+		tlsSecret := newTLSSecretIdentifier(ingress, tls)
 
 		// add hostname-tlsSecret mapping to a per-ingress map
 		if cert := c.k8sContext.CertificateSecretStore.GetPfxCertificate(tlsSecret.secretKey()); cert != nil {
@@ -97,10 +110,7 @@ func (c *appGwConfigBuilder) newHostToSecretMap(ingress *v1beta1.Ingress) map[st
 			continue
 		}
 
-		tlsSecret := secretIdentifier{
-			Name:      tls.SecretName,
-			Namespace: ingress.Namespace,
-		}
+		tlsSecret := newTLSSecretIdentifier(ingress, tls)
 
 		// add hostname-tlsSecret mapping to a per-ingress map
 		cert := c.k8sContext.CertificateSecretStore.GetPfxCertificate(tlsSecret.secretKey())
@@ -136,3 +146,23 @@ func (c *appGwConfigBuilder) newCert(secretID secretIdentifier, cert *string) n.
 		},
 	}
 }
+
+// newAGICCertificateNameMatcher makes a func, which figures out whether a certificate name has the form secretFullName()
+// generates for a secret of an observed namespace: the namespace, a dash, and a valid secret name. The secret need not
+// exist any longer, so that the certificates of deleted and rotated secrets are recognized. Without an ownership manifest
+// these are the certificates AGIC may remove once no Ingress references their secret; GetBlacklistedCertificates retains
+// the ones used by listeners AGIC does not manage regardless.
+func (c *appGwConfigBuilder) newAGICCertificateNameMatcher() func(name string) bool {
+	prefixes := make(map[string]interface{})
+	for _, namespace := range c.k8sContext.ListObservedNamespaces() {
+		prefixes[namespace+"-"] = nil
+	}
+	return func(name string) bool {
+		for prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) && len(validation.IsDNS1123Subdomain(strings.TrimPrefix(name, prefix))) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+}
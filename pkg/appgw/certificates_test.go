@@ -1,9 +1,14 @@
 package appgw
 
 import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // appgw_suite_test.go launches these Ginkgo tests
@@ -77,3 +82,38 @@ var _ = Describe("Testing function newHostToSecretMap", func() {
 		})
 	})
 })
+
+var _ = Describe("Testing function newAGICCertificateNameMatcher", func() {
+	cb := newConfigBuilderFixture(nil)
+	_ = cb.k8sContext.Caches.Namespaces.Add(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+	_ = cb.k8sContext.Caches.Secret.Add(&v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "my-secret"}})
+	isAGICCertificateName := cb.newAGICCertificateNameMatcher()
+
+	It("should recognize certificates of secrets in observed namespaces", func() {
+		Expect(isAGICCertificateName("default-my-secret")).To(BeTrue())
+	})
+
+	It("should recognize certificates of secrets, which were deleted", func() {
+		Expect(isAGICCertificateName("default-deleted-secret")).To(BeTrue())
+	})
+
+	It("should not recognize certificates of namespaces, which are not observed", func() {
+		Expect(isAGICCertificateName("production-my-secret")).To(BeFalse())
+	})
+
+	It("should not recognize certificates, which are not followed by a valid secret name", func() {
+		Expect(isAGICCertificateName("default-")).To(BeFalse())
+		Expect(isAGICCertificateName("default-My_Certificate")).To(BeFalse())
+		Expect(isAGICCertificateName("uploaded-by-hand")).To(BeFalse())
+	})
+
+	It("should remove the certificate of a deleted secret from a shared App Gateway", func() {
+		cb.appGw.SslCertificates = &[]n.ApplicationGatewaySslCertificate{
+			{Name: to.StringPtr("default-deleted-secret")},
+			{Name: to.StringPtr("uploaded-by-hand")},
+		}
+		certificates := cb.getSslCertificates(&ConfigBuilderContext{EnableBrownfieldDeployment: true})
+		Expect(len(*certificates)).To(Equal(1))
+		Expect(*(*certificates)[0].Name).To(Equal("uploaded-by-hand"))
+	})
+})
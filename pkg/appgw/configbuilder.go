@@ -15,8 +15,10 @@ import (
 	"k8s.io/api/extensions/v1beta1"
 	"k8s.io/client-go/tools/record"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/version"
 )
 
@@ -25,6 +27,7 @@ type ConfigBuilder interface {
 	PreBuildValidate(cbCtx *ConfigBuilderContext) error
 	Build(cbCtx *ConfigBuilderContext) (*n.ApplicationGateway, error)
 	PostBuildValidate(cbCtx *ConfigBuilderContext) error
+	Ownership(cbCtx *ConfigBuilderContext) *ownership.Manifest
 }
 
 type memoization struct {
@@ -92,6 +95,13 @@ func (c *appGwConfigBuilder) Build(cbCtx *ConfigBuilderContext) (*n.ApplicationG
 	return &c.appGw, nil
 }
 
+// newExistingResources segments the App Gateway config AGIC started from into the sub-resources AGIC may and may not mutate.
+func (c *appGwConfigBuilder) newExistingResources(cbCtx *ConfigBuilderContext, defaultPool *n.ApplicationGatewayBackendAddressPool) brownfield.ExistingResources {
+	er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets, defaultPool)
+	er.Ownership = cbCtx.Ownership
+	return er
+}
+
 type valFunc func(eventRecorder record.EventRecorder, config *n.ApplicationGatewayPropertiesFormat, envVariables environment.EnvVariables, ingressList []*v1beta1.Ingress, serviceList []*v1.Service) error
 
 // PreBuildValidate runs all the validators that suggest misconfiguration in Kubernetes resources.
@@ -0,0 +1,211 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+)
+
+// defaultPath is the path of the default backend of a listener; Ingress paths "", "/" and "/*" all map to it.
+const defaultPath = "/*"
+
+// hostPathClaim is a host and URL path on a frontend IP, for which an Ingress routes traffic.
+type hostPathClaim struct {
+	Host         string
+	Path         string
+	UsePrivateIP bool
+}
+
+// HostPathConflict is a host and URL path claimed by more than one Ingress.
+type HostPathConflict struct {
+	Host string
+	Path string
+
+	// Ingress is the Ingress the conflict was looked up for.
+	Ingress *v1beta1.Ingress
+
+	// ConflictingIngress is the other Ingress claiming the same host and path.
+	ConflictingIngress *v1beta1.Ingress
+
+	// ConflictingTLS is set when the Ingresses declare different TLS secrets for the host; Path is empty then.
+	ConflictingTLS bool
+}
+
+// GetHostPathConflicts lists the hosts and paths of the given Ingress, which ResolveHostPathConflicts would drop in favour of
+// another Ingress in the list. An Ingress, which is not created yet, is the newest one.
+// The Ingress itself (same namespace and name) is skipped in the list, so this works for updates of an existing Ingress.
+func GetHostPathConflicts(ingress *v1beta1.Ingress, ingressList []*v1beta1.Ingress) []HostPathConflict {
+	candidate := ingress
+	if candidate.CreationTimestamp.IsZero() {
+		candidate = ingress.DeepCopy()
+		candidate.CreationTimestamp = metav1.Now()
+	}
+	candidates := []*v1beta1.Ingress{candidate}
+	for _, other := range ingressList {
+		if other.Namespace == ingress.Namespace && other.Name == ingress.Name {
+			continue
+		}
+		candidates = append(candidates, other)
+	}
+
+	_, resolved := ResolveHostPathConflicts(candidates)
+	var conflicts []HostPathConflict
+	for _, conflict := range resolved {
+		if conflict.Ingress == candidate {
+			conflict.Ingress = ingress
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts
+}
+
+func normalizeIngressPath(path string) string {
+	if path == "" || path == "/" || path == "/*" {
+		return defaultPath
+	}
+	return path
+}
+
+// ResolveHostPathConflicts drops the hosts and paths of Ingresses, which are already claimed with a different backend by
+// another Ingress. The oldest Ingress wins, by creation timestamp and then by UID, so that the outcome does not depend on
+// the order in which Ingresses are listed. Ingresses declaring a different TLS secret for a host claimed by an older Ingress
+// lose all rules for that host, as a listener can only have one certificate.
+// Returns the list in the original order, with losing Ingresses replaced by pruned copies, and the conflicts, which were resolved.
+func ResolveHostPathConflicts(ingressList []*v1beta1.Ingress) ([]*v1beta1.Ingress, []HostPathConflict) {
+	byAge := make([]*v1beta1.Ingress, len(ingressList))
+	copy(byAge, ingressList)
+	sort.SliceStable(byAge, func(i, j int) bool {
+		return isOlderIngress(byAge[i], byAge[j])
+	})
+
+	type pathOwner struct {
+		ingress *v1beta1.Ingress
+		backend string
+	}
+	type tlsOwner struct {
+		ingress *v1beta1.Ingress
+		secret  string
+	}
+	pathOwners := make(map[hostPathClaim]pathOwner)
+	tlsOwners := make(map[hostPathClaim]tlsOwner)
+	resolved := make(map[*v1beta1.Ingress]*v1beta1.Ingress)
+	var conflicts []HostPathConflict
+
+	for _, ingress := range byAge {
+		usePrivateIP, _ := annotations.UsePrivateIP(ingress)
+		pruned := ingress.DeepCopy()
+		modified := false
+
+		if backend := ingress.Spec.Backend; backend != nil {
+			claim := hostPathClaim{Path: defaultPath, UsePrivateIP: usePrivateIP}
+			key := getBackendKey(ingress.Namespace, backend)
+			if owner, exists := pathOwners[claim]; exists && owner.backend != key {
+				conflicts = append(conflicts, HostPathConflict{Path: defaultPath, Ingress: ingress, ConflictingIngress: owner.ingress})
+				pruned.Spec.Backend = nil
+				modified = true
+			} else if !exists {
+				pathOwners[claim] = pathOwner{ingress: ingress, backend: key}
+			}
+		}
+
+		var rules []v1beta1.IngressRule
+		for _, rule := range pruned.Spec.Rules {
+			if rule.HTTP == nil {
+				rules = append(rules, rule)
+				continue
+			}
+			host := strings.ToLower(rule.Host)
+
+			// A host is served by a single HTTPS listener with a single certificate.
+			tlsClaim := hostPathClaim{Host: host, UsePrivateIP: usePrivateIP}
+			if secret := getTLSSecretKey(ingress, rule.Host); secret != "" {
+				if owner, exists := tlsOwners[tlsClaim]; exists && owner.secret != secret {
+					conflicts = append(conflicts, HostPathConflict{Host: host, Ingress: ingress, ConflictingIngress: owner.ingress, ConflictingTLS: true})
+					modified = true
+					continue
+				} else if !exists {
+					tlsOwners[tlsClaim] = tlsOwner{ingress: ingress, secret: secret}
+				}
+			}
+
+			var paths []v1beta1.HTTPIngressPath
+			for _, path := range rule.HTTP.Paths {
+				claim := hostPathClaim{Host: host, Path: normalizeIngressPath(path.Path), UsePrivateIP: usePrivateIP}
+				key := getBackendKey(ingress.Namespace, &path.Backend)
+				if owner, exists := pathOwners[claim]; exists && owner.backend != key {
+					conflicts = append(conflicts, HostPathConflict{Host: host, Path: claim.Path, Ingress: ingress, ConflictingIngress: owner.ingress})
+					modified = true
+					continue
+				} else if !exists {
+					pathOwners[claim] = pathOwner{ingress: ingress, backend: key}
+				}
+				paths = append(paths, path)
+			}
+			if len(paths) == 0 {
+				continue
+			}
+			rule.HTTP = &v1beta1.HTTPIngressRuleValue{Paths: paths}
+			rules = append(rules, rule)
+		}
+
+		if modified {
+			pruned.Spec.Rules = rules
+			resolved[ingress] = pruned
+		}
+	}
+
+	var ingresses []*v1beta1.Ingress
+	for _, ingress := range ingressList {
+		if pruned, exists := resolved[ingress]; exists {
+			ingresses = append(ingresses, pruned)
+		} else {
+			ingresses = append(ingresses, ingress)
+		}
+	}
+	return ingresses, conflicts
+}
+
+// isOlderIngress figures out whether Ingress a takes precedence over Ingress b: the older one does, and the one with the lower UID if created at the same time.
+func isOlderIngress(a, b *v1beta1.Ingress) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.UID < b.UID
+}
+
+// getBackendKey uniquely identifies the backend of an Ingress path across namespaces.
+func getBackendKey(namespace string, backend *v1beta1.IngressBackend) string {
+	return fmt.Sprintf("%s/%s:%s", namespace, backend.ServiceName, backend.ServicePort.String())
+}
+
+// getTLSSecretKey returns the namespace/name of the TLS secret the Ingress declares for the host, falling back to the secret without hosts; Empty when there is none.
+func getTLSSecretKey(ingress *v1beta1.Ingress, host string) string {
+	var defaultSecret string
+	for _, tls := range ingress.Spec.TLS {
+		if len(tls.SecretName) == 0 {
+			continue
+		}
+		secretKey := newTLSSecretIdentifier(ingress, tls).secretKey()
+		if len(tls.Hosts) == 0 {
+			defaultSecret = secretKey
+		}
+		for _, tlsHost := range tls.Hosts {
+			if len(tlsHost) == 0 {
+				defaultSecret = secretKey
+			} else if strings.EqualFold(tlsHost, host) {
+				return secretKey
+			}
+		}
+	}
+	return defaultSecret
+}
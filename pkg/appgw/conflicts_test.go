@@ -0,0 +1,170 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test host and path conflicts between Ingresses", func() {
+
+	newIngress := func(name, host, path string) *v1beta1.Ingress {
+		ingress := tests.NewIngressTestFixture(tests.Namespace, name)
+		ingress.Spec.Rules[0].Host = host
+		ingress.Spec.Rules[0].HTTP.Paths[0].Path = path
+		return &ingress
+	}
+
+	Context("Test GetHostPathConflicts()", func() {
+		It("should find Ingresses claiming the same host and path with a different backend", func() {
+			ingress := newIngress("first", "Hello.com", "/hi")
+			ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName = "other-service"
+			other := newIngress("second", "hello.com", "/hi")
+			conflicts := GetHostPathConflicts(ingress, []*v1beta1.Ingress{ingress, other})
+			Expect(conflicts).To(Equal([]HostPathConflict{
+				{Host: "hello.com", Path: "/hi", Ingress: ingress, ConflictingIngress: other},
+			}))
+		})
+
+		It("should treat empty, / and /* paths as the default backend of the host", func() {
+			ingress := newIngress("first", "hello.com", "/")
+			ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName = "other-service"
+			other := newIngress("second", "hello.com", "/*")
+			Expect(len(GetHostPathConflicts(ingress, []*v1beta1.Ingress{other}))).To(Equal(1))
+		})
+
+		It("should not find conflicts with Ingresses routing the same host and path to the same backend", func() {
+			ingress := newIngress("first", "hello.com", "/hi")
+			other := newIngress("second", "hello.com", "/hi")
+			Expect(GetHostPathConflicts(ingress, []*v1beta1.Ingress{other})).To(BeEmpty())
+		})
+
+		It("should not find conflicts the Ingress wins as the older one", func() {
+			ingress := newIngress("first", "hello.com", "/hi")
+			ingress.CreationTimestamp = metav1.Date(2019, 10, 1, 12, 1, 0, 0, time.UTC)
+			ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName = "other-service"
+			other := newIngress("second", "hello.com", "/hi")
+			other.CreationTimestamp = metav1.Date(2019, 10, 1, 12, 2, 0, 0, time.UTC)
+			Expect(GetHostPathConflicts(ingress, []*v1beta1.Ingress{other})).To(BeEmpty())
+		})
+
+		It("should not find conflicts for other paths, hosts or frontend IPs", func() {
+			ingress := newIngress("first", "hello.com", "/hi")
+			otherPath := newIngress("second", "hello.com", "/bye")
+			otherHost := newIngress("third", "bye.com", "/hi")
+			otherIP := newIngress("fourth", "hello.com", "/hi")
+			otherIP.Annotations[annotations.UsePrivateIPKey] = "true"
+			Expect(GetHostPathConflicts(ingress, []*v1beta1.Ingress{otherPath, otherHost, otherIP})).To(BeEmpty())
+		})
+
+		It("should not find conflicts with a previous version of the same Ingress", func() {
+			ingress := newIngress("first", "hello.com", "/hi")
+			previous := newIngress("first", "hello.com", "/hi")
+			Expect(GetHostPathConflicts(ingress, []*v1beta1.Ingress{previous})).To(BeEmpty())
+		})
+	})
+
+	Context("Test ResolveHostPathConflicts()", func() {
+		created := func(ingress *v1beta1.Ingress, minutes int) *v1beta1.Ingress {
+			ingress.CreationTimestamp = metav1.Date(2019, 10, 1, 12, minutes, 0, 0, time.UTC)
+			return ingress
+		}
+
+		It("should keep the path of the older Ingress and drop the path of the newer one", func() {
+			older := created(newIngress("older", "hello.com", "/hi"), 1)
+			newer := created(newIngress("newer", "hello.com", "/hi"), 2)
+			newer.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName = "other-service"
+			newer.Spec.Rules[0].HTTP.Paths = append(newer.Spec.Rules[0].HTTP.Paths, v1beta1.HTTPIngressPath{
+				Path:    "/bye",
+				Backend: newer.Spec.Rules[0].HTTP.Paths[0].Backend,
+			})
+
+			resolved, conflicts := ResolveHostPathConflicts([]*v1beta1.Ingress{newer, older})
+			Expect(conflicts).To(Equal([]HostPathConflict{
+				{Host: "hello.com", Path: "/hi", Ingress: newer, ConflictingIngress: older},
+			}))
+			Expect(len(resolved)).To(Equal(2))
+			Expect(resolved[1]).To(Equal(older))
+			Expect(resolved[0].Name).To(Equal("newer"))
+			Expect(len(resolved[0].Spec.Rules)).To(Equal(1))
+			Expect(resolved[0].Spec.Rules[0].HTTP.Paths).To(HaveLen(1))
+			Expect(resolved[0].Spec.Rules[0].HTTP.Paths[0].Path).To(Equal("/bye"))
+
+			// The Ingress in the cache must not be mutated.
+			Expect(newer.Spec.Rules[0].HTTP.Paths).To(HaveLen(2))
+		})
+
+		It("should pick the Ingress with the lower UID when created at the same time", func() {
+			first := created(newIngress("first", "hello.com", "/hi"), 1)
+			first.UID = "bbb"
+			second := created(newIngress("second", "hello.com", "/hi"), 1)
+			second.UID = "aaa"
+			second.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName = "other-service"
+
+			resolved, conflicts := ResolveHostPathConflicts([]*v1beta1.Ingress{first, second})
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].Ingress).To(Equal(first))
+			Expect(conflicts[0].ConflictingIngress).To(Equal(second))
+			Expect(resolved[0].Spec.Rules).To(BeEmpty())
+			Expect(resolved[1]).To(Equal(second))
+		})
+
+		It("should keep the same path with the same backend in both Ingresses", func() {
+			older := created(newIngress("older", "hello.com", "/hi"), 1)
+			newer := created(newIngress("newer", "hello.com", "/hi"), 2)
+			resolved, conflicts := ResolveHostPathConflicts([]*v1beta1.Ingress{older, newer})
+			Expect(conflicts).To(BeEmpty())
+			Expect(resolved).To(Equal([]*v1beta1.Ingress{older, newer}))
+		})
+
+		It("should drop the host of the newer Ingress declaring a different TLS secret", func() {
+			older := created(newIngress("older", "hello.com", "/hi"), 1)
+			older.Spec.TLS = []v1beta1.IngressTLS{{SecretName: "older-secret"}}
+			newer := created(newIngress("newer", "Hello.com", "/bye"), 2)
+			newer.Spec.TLS = []v1beta1.IngressTLS{{Hosts: []string{"Hello.com"}, SecretName: "newer-secret"}}
+
+			resolved, conflicts := ResolveHostPathConflicts([]*v1beta1.Ingress{older, newer})
+			Expect(conflicts).To(Equal([]HostPathConflict{
+				{Host: "hello.com", Ingress: newer, ConflictingIngress: older, ConflictingTLS: true},
+			}))
+			Expect(resolved[0]).To(Equal(older))
+			Expect(resolved[1].Spec.Rules).To(BeEmpty())
+		})
+
+		It("should drop the default backend of the newer Ingress", func() {
+			older := created(newIngress("older", "hello.com", "/hi"), 1)
+			older.Spec.Backend = &v1beta1.IngressBackend{ServiceName: "older-service"}
+			newer := created(newIngress("newer", "bye.com", "/hi"), 2)
+			newer.Spec.Backend = &v1beta1.IngressBackend{ServiceName: "newer-service"}
+
+			resolved, conflicts := ResolveHostPathConflicts([]*v1beta1.Ingress{older, newer})
+			Expect(conflicts).To(Equal([]HostPathConflict{
+				{Path: "/*", Ingress: newer, ConflictingIngress: older},
+			}))
+			Expect(resolved[1].Spec.Backend).To(BeNil())
+			Expect(resolved[1].Spec.Rules).To(HaveLen(1))
+		})
+	})
+
+	Context("Test ValidateRedirectHasTLS()", func() {
+		It("should reject ssl-redirect without TLS", func() {
+			ingress := newIngress("first", "hello.com", "/hi")
+			Expect(ValidateRedirectHasTLS(ingress)).To(BeNil())
+			ingress.Annotations[annotations.SslRedirectKey] = "true"
+			Expect(ValidateRedirectHasTLS(ingress)).To(Equal(ErrRedirectWithNoTLS))
+			ingress.Spec.TLS = []v1beta1.IngressTLS{{SecretName: tests.NameOfSecret}}
+			Expect(ValidateRedirectHasTLS(ingress)).To(BeNil())
+		})
+	})
+})
@@ -25,22 +25,16 @@ func (c *appGwConfigBuilder) getListeners(cbCtx *ConfigBuilderContext) *[]n.Appl
 
 	if cbCtx.EnableIstioIntegration {
 		for listenerID, config := range c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices) {
-			listener := c.newListener(listenerID, config.Protocol)
-			listeners = append(listeners, listener)
+			listeners = append(listeners, c.newListenerFromConfig(listenerID, config))
 		}
 	}
 
 	for listenerID, config := range c.getListenerConfigs(cbCtx) {
-		listener := c.newListener(listenerID, config.Protocol)
-		if config.Protocol == n.HTTPS {
-			sslCertificateID := c.appGwIdentifier.sslCertificateID(config.Secret.secretFullName())
-			listener.SslCertificate = resourceRef(sslCertificateID)
-		}
-		listeners = append(listeners, listener)
+		listeners = append(listeners, c.newListenerFromConfig(listenerID, config))
 	}
 
 	if cbCtx.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		er := c.newExistingResources(cbCtx, nil)
 
 		// Listeners we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedListeners()
@@ -62,12 +56,17 @@ func (c *appGwConfigBuilder) getListeners(cbCtx *ConfigBuilderContext) *[]n.Appl
 
 // getListenerConfigs creates an intermediary representation of the listener configs based on the passed list of ingresses
 func (c *appGwConfigBuilder) getListenerConfigs(cbCtx *ConfigBuilderContext) map[listenerIdentifier]listenerAzConfig {
-	// TODO(draychev): Emit an error event if 2 namespaces define different TLS for the same domain!
 	allListeners := make(map[listenerIdentifier]listenerAzConfig)
 	for _, ingress := range cbCtx.IngressList {
 		glog.V(5).Infof("Processing Rules for Ingress: %s/%s", ingress.Namespace, ingress.Name)
 		_, azListenerConfigs := c.processIngressRules(ingress, cbCtx.EnvVariables)
 		for listenerID, azConfig := range azListenerConfigs {
+			// Ingresses defining different TLS for the same host are pruned by the controller (see ResolveHostPathConflicts);
+			// Should one get here regardless, keep the listener of the first Ingress rather than whichever comes last.
+			if existing, exists := allListeners[listenerID]; exists && existing.Secret != azConfig.Secret {
+				glog.Errorf("Ingress %s/%s uses secret %s for host %q, which is already served with secret %s; Ignoring it", ingress.Namespace, ingress.Name, azConfig.Secret.secretKey(), listenerID.HostName, existing.Secret.secretKey())
+				continue
+			}
 			allListeners[listenerID] = azConfig
 		}
 	}
@@ -83,6 +82,16 @@ func (c *appGwConfigBuilder) getListenerConfigs(cbCtx *ConfigBuilderContext) map
 	return allListeners
 }
 
+// newListenerFromConfig creates the listener for the given listener config; HTTPS listeners reference the certificate of their secret.
+func (c *appGwConfigBuilder) newListenerFromConfig(listenerID listenerIdentifier, config listenerAzConfig) n.ApplicationGatewayHTTPListener {
+	listener := c.newListener(listenerID, config.Protocol)
+	if config.Protocol == n.HTTPS {
+		sslCertificateID := c.appGwIdentifier.sslCertificateID(config.Secret.secretFullName())
+		listener.SslCertificate = resourceRef(sslCertificateID)
+	}
+	return listener
+}
+
 func (c *appGwConfigBuilder) newListener(listenerID listenerIdentifier, protocol n.ApplicationGatewayProtocol) n.ApplicationGatewayHTTPListener {
 	frontIPConfiguration := *LookupIPConfigurationByType(c.appGw.FrontendIPConfigurations, listenerID.UsePrivateIP)
 	frontendPort := c.lookupFrontendPortByListenerIdentifier(listenerID)
@@ -30,7 +30,7 @@ func (c *appGwConfigBuilder) HealthProbesCollection(cbCtx *ConfigBuilderContext)
 	}
 
 	if cbCtx.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		er := c.newExistingResources(cbCtx, nil)
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedProbes()
 		brownfield.LogProbes(existingBlacklisted, existingNonBlacklisted, agicCreatedProbes)
 		agicCreatedProbes = brownfield.MergeProbes(existingBlacklisted, agicCreatedProbes)
@@ -50,7 +50,7 @@ func (c *appGwConfigBuilder) newProbesMap(cbCtx *ConfigBuilderContext) (map[stri
 	healthProbeCollection[*defaultProbe.Name] = defaultProbe
 
 	for backendID := range newBackendIdsFiltered(cbCtx) {
-		probe := c.generateHealthProbe(backendID)
+		probe := c.generateHealthProbe(backendID, cbCtx)
 
 		if probe != nil {
 			glog.V(5).Infof("Created probe %s for backend: '%s'", *probe.Name, backendID.Name)
@@ -64,7 +64,7 @@ func (c *appGwConfigBuilder) newProbesMap(cbCtx *ConfigBuilderContext) (map[stri
 	return healthProbeCollection, probesMap
 }
 
-func (c *appGwConfigBuilder) generateHealthProbe(backendID backendIdentifier) *n.ApplicationGatewayProbe {
+func (c *appGwConfigBuilder) generateHealthProbe(backendID backendIdentifier, cbCtx *ConfigBuilderContext) *n.ApplicationGatewayProbe {
 	// TODO(draychev): remove GetService
 	service := c.k8sContext.GetService(backendID.serviceKey())
 	if service == nil {
@@ -84,7 +84,15 @@ func (c *appGwConfigBuilder) generateHealthProbe(backendID backendIdentifier) *n
 		probe.Path = to.StringPtr(backendID.Path.Path)
 	}
 
-	k8sProbeForServiceContainer := c.getProbeForServiceContainer(service, backendID)
+	var k8sProbeForServiceContainer *v1.Probe
+	if isExternalNameService(service) {
+		// ExternalName Services have no pods to take the probe from; The external host answers probes for its own name,
+		// which the HTTP settings pick from the backend address.
+		probe.Host = nil
+		probe.PickHostNameFromBackendHTTPSettings = to.BoolPtr(true)
+	} else {
+		k8sProbeForServiceContainer = c.getProbeForServiceContainer(service, backendID, getBackendMode(backendID.Ingress, cbCtx.EnvVariables))
+	}
 	if k8sProbeForServiceContainer != nil {
 		if len(k8sProbeForServiceContainer.Handler.HTTPGet.Host) != 0 {
 			probe.Host = to.StringPtr(k8sProbeForServiceContainer.Handler.HTTPGet.Host)
@@ -112,7 +120,10 @@ func (c *appGwConfigBuilder) generateHealthProbe(backendID backendIdentifier) *n
 	return &probe
 }
 
-func (c *appGwConfigBuilder) getProbeForServiceContainer(service *v1.Service, backendID backendIdentifier) *v1.Probe {
+// getProbeForServiceContainer returns the HTTP readiness, or liveness, probe of the containers behind the backend. App Gateway
+// probes the port of the HTTP settings; Outside of the pod backend mode the Service forwards it to the container port it
+// targets, so the probe must check that very port.
+func (c *appGwConfigBuilder) getProbeForServiceContainer(service *v1.Service, backendID backendIdentifier, mode string) *v1.Probe {
 	allPorts := make(map[int32]interface{})
 	for _, sp := range service.Spec.Ports {
 		if sp.Protocol != v1.ProtocolTCP {
@@ -152,6 +163,13 @@ func (c *appGwConfigBuilder) getProbeForServiceContainer(service *v1.Service, ba
 					probe = container.LivenessProbe
 				}
 
+				if mode != annotations.BackendModePod {
+					if probe != nil && isProbeOfPort(probe, port) {
+						return probe
+					}
+					continue
+				}
+
 				if probe.Handler.HTTPGet.Port == backendID.Backend.ServicePort {
 					return probe
 				}
@@ -161,3 +179,12 @@ func (c *appGwConfigBuilder) getProbeForServiceContainer(service *v1.Service, ba
 
 	return nil
 }
+
+// isProbeOfPort determines whether the HTTP probe checks the given container port.
+func isProbeOfPort(probe *v1.Probe, port v1.ContainerPort) bool {
+	probePort := probe.Handler.HTTPGet.Port
+	if probePort.Type == intstr.Int {
+		return probePort.IntVal == port.ContainerPort
+	}
+	return len(port.Name) != 0 && probePort.StrVal == port.Name
+}
@@ -0,0 +1,83 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"strings"
+
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
+)
+
+// HostnameGrants maps a namespace to the hostnames and wildcard patterns granted to it with the hostnames namespace annotation.
+// A hostname matched by the grant of any namespace may only be used by the Ingresses of the namespaces it is granted to;
+// Hostnames not granted to any namespace may be used by all of them.
+type HostnameGrants map[string][]string
+
+// NewHostnameGrants collects the hostnames granted to each of the given namespaces.
+func NewHostnameGrants(namespaces []*v1.Namespace) HostnameGrants {
+	grants := make(HostnameGrants)
+	for _, namespace := range namespaces {
+		hostnames, err := annotations.Hostnames(namespace)
+		if err != nil {
+			if errors.IsInvalidContent(err) {
+				glog.Errorf("Namespace %s has invalid value for annotation %s", namespace.Name, annotations.HostnamesKey)
+			}
+			continue
+		}
+		grants[namespace.Name] = hostnames
+	}
+	return grants
+}
+
+// IsOwner figures out whether the Ingresses of the namespace may use the hostname.
+// The empty hostname of an Ingress rule without a host, or the default backend, matches only the "*" pattern.
+func (grants HostnameGrants) IsOwner(namespace string, hostname string) bool {
+	hostname = strings.ToLower(hostname)
+	granted := false
+	for grantee, patterns := range grants {
+		for _, pattern := range patterns {
+			if !hostnameMatches(pattern, hostname) {
+				continue
+			}
+			if grantee == namespace {
+				return true
+			}
+			granted = true
+		}
+	}
+	return !granted
+}
+
+// PruneIngressRules returns the rules of the Ingress for the hostnames its namespace owns, and the hostnames it does not own.
+func (grants HostnameGrants) PruneIngressRules(ingress *v1beta1.Ingress) ([]v1beta1.IngressRule, []string) {
+	var rules []v1beta1.IngressRule
+	var notOwned []string
+	for _, rule := range ingress.Spec.Rules {
+		if grants.IsOwner(ingress.Namespace, rule.Host) {
+			rules = append(rules, rule)
+		} else {
+			notOwned = append(notOwned, rule.Host)
+		}
+	}
+	return rules, notOwned
+}
+
+// hostnameMatches figures out whether the lower case hostname matches the pattern.
+// "*" matches any hostname; "*.contoso.com" matches the subdomains of contoso.com at any depth, but not contoso.com itself.
+func hostnameMatches(pattern string, hostname string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(hostname, pattern[1:])
+	}
+	return pattern == hostname
+}
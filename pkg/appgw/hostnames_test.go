@@ -0,0 +1,85 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test hostname grants of namespaces", func() {
+	newNamespace := func(name string, hostnames string) *v1.Namespace {
+		namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if hostnames != "" {
+			namespace.Annotations = map[string]string{annotations.HostnamesKey: hostnames}
+		}
+		return namespace
+	}
+
+	grants := NewHostnameGrants([]*v1.Namespace{
+		newNamespace("team-a", "*.team-a.contoso.com,www.contoso.com"),
+		newNamespace("team-b", "*.team-b.contoso.com"),
+		newNamespace("infra", "*.contoso.com"),
+		newNamespace("invalid", "www.*.com"),
+		newNamespace("other", ""),
+	})
+
+	Context("Test NewHostnameGrants()", func() {
+		It("should collect the valid grants only", func() {
+			Expect(grants).To(Equal(HostnameGrants{
+				"team-a": {"*.team-a.contoso.com", "www.contoso.com"},
+				"team-b": {"*.team-b.contoso.com"},
+				"infra":  {"*.contoso.com"},
+			}))
+		})
+	})
+
+	Context("Test IsOwner()", func() {
+		It("should let the namespaces granted a hostname use it", func() {
+			Expect(grants.IsOwner("team-a", "api.team-a.contoso.com")).To(BeTrue())
+			Expect(grants.IsOwner("team-a", "WWW.contoso.com")).To(BeTrue())
+			Expect(grants.IsOwner("infra", "api.team-a.contoso.com")).To(BeTrue())
+			Expect(grants.IsOwner("infra", "deep.api.team-b.contoso.com")).To(BeTrue())
+		})
+
+		It("should not let other namespaces use a granted hostname", func() {
+			Expect(grants.IsOwner("team-b", "api.team-a.contoso.com")).To(BeFalse())
+			Expect(grants.IsOwner("other", "www.contoso.com")).To(BeFalse())
+		})
+
+		It("should let any namespace use hostnames, which are not granted", func() {
+			Expect(grants.IsOwner("other", "contoso.com")).To(BeTrue())
+			Expect(grants.IsOwner("team-b", "bing.com")).To(BeTrue())
+			Expect(grants.IsOwner("team-b", "")).To(BeTrue())
+		})
+
+		It("should match the empty hostname with * only", func() {
+			withStar := NewHostnameGrants([]*v1.Namespace{newNamespace("infra", "*")})
+			Expect(withStar.IsOwner("infra", "")).To(BeTrue())
+			Expect(withStar.IsOwner("team-a", "")).To(BeFalse())
+			Expect(withStar.IsOwner("team-a", "bing.com")).To(BeFalse())
+		})
+	})
+
+	Context("Test PruneIngressRules()", func() {
+		It("should drop the rules for hostnames the namespace does not own", func() {
+			ingress := tests.NewIngressTestFixture("team-b", "ingress")
+			ingress.Spec.Rules[0].Host = "www.contoso.com"
+			ingress.Spec.Rules = append(ingress.Spec.Rules, ingress.Spec.Rules[0])
+			ingress.Spec.Rules[1].Host = "api.team-b.contoso.com"
+
+			rules, notOwned := grants.PruneIngressRules(&ingress)
+			Expect(notOwned).To(Equal([]string{"www.contoso.com"}))
+			Expect(rules).To(HaveLen(1))
+			Expect(rules[0].Host).To(Equal("api.team-b.contoso.com"))
+		})
+	})
+})
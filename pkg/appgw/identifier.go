@@ -61,6 +61,10 @@ func (agw Identifier) redirectConfigurationID(configurationName string) string {
 	return agw.gatewayResourceID("redirectConfigurations", configurationName)
 }
 
+func (agw Identifier) rewriteRuleSetID(ruleSetName string) string {
+	return agw.gatewayResourceID("rewriteRuleSets", ruleSetName)
+}
+
 func (agw Identifier) probeID(probeName string) string {
 	return agw.gatewayResourceID("probes", probeName)
 }
@@ -13,12 +13,14 @@ import (
 	"crypto/md5"
 	"fmt"
 	"regexp"
+	"strings"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
 	"k8s.io/api/extensions/v1beta1"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 )
 
@@ -32,6 +34,8 @@ const (
 	prefixRoutingRule  = "rr"
 	prefixRedirect     = "sslr"
 	prefixPathRule     = "pr"
+	prefixRouteRedir   = "rd"
+	prefixRewriteSet   = "rw"
 )
 
 type backendIdentifier struct {
@@ -105,6 +109,17 @@ func (s secretIdentifier) secretFullName() string {
 	return fmt.Sprintf("%v-%v", s.Namespace, s.Name)
 }
 
+// newTLSSecretIdentifier identifies the TLS secret of the Ingress; Ingresses translated from Gateway API listeners refer to the
+// secret in the namespace of the Gateway, which the secret name carries as namespace/name.
+func newTLSSecretIdentifier(ingress *v1beta1.Ingress, tls v1beta1.IngressTLS) secretIdentifier {
+	if _, err := annotations.GatewayAPIListener(ingress); err == nil {
+		if parts := strings.SplitN(tls.SecretName, "/", 2); len(parts) == 2 {
+			return secretIdentifier{Namespace: parts[0], Name: parts[1]}
+		}
+	}
+	return secretIdentifier{Namespace: ingress.Namespace, Name: tls.SecretName}
+}
+
 func getResourceKey(namespace, name string) string {
 	return formatPropName(fmt.Sprintf("%v/%v", namespace, name))
 }
@@ -148,6 +163,14 @@ func generatePathRuleName(namespace, ingress, suffix string) string {
 	return formatPropName(fmt.Sprintf("%s%s-%s-%s-%s", agPrefix, prefixPathRule, namespace, ingress, suffix))
 }
 
+func generateRouteRedirectConfigurationName(listenerID listenerIdentifier, namespace, name, suffix string) string {
+	return formatPropName(fmt.Sprintf("%s%s-%s-%s-%s-%v%v", agPrefix, prefixRouteRedir, namespace, name, suffix, formatHostname(listenerID.HostName), listenerID.FrontendPort))
+}
+
+func generateRewriteRuleSetName(namespace, name, suffix string) string {
+	return formatPropName(fmt.Sprintf("%s%s-%s-%s-%s", agPrefix, prefixRewriteSet, namespace, name, suffix))
+}
+
 var defaultBackendHTTPSettingsName = fmt.Sprintf("%sdefaulthttpsetting", agPrefix)
 var defaultBackendAddressPoolName = fmt.Sprintf("%sdefaultaddresspool", agPrefix)
 var defaultProbeName = fmt.Sprintf("%sdefaultprobe", agPrefix)
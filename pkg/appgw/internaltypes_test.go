@@ -8,9 +8,13 @@ package appgw
 import (
 	"fmt"
 
-	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
 
 // appgw_suite_test.go launches these Ginkgo tests
@@ -220,4 +224,21 @@ var _ = Describe("Test string key generators", func() {
 			Expect(actual).To(Equal(expected))
 		})
 	})
+
+	Context("test newTLSSecretIdentifier", func() {
+		tls := v1beta1.IngressTLS{SecretName: "infra/contoso-cert"}
+
+		It("takes the namespace of the Ingress", func() {
+			ingress := &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: tests.Namespace}}
+			Expect(newTLSSecretIdentifier(ingress, v1beta1.IngressTLS{SecretName: tests.NameOfSecret})).To(Equal(secretIdentifier{Namespace: tests.Namespace, Name: tests.NameOfSecret}))
+		})
+
+		It("takes the namespace from the secret name of Ingresses translated from Gateway listeners", func() {
+			ingress := &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   tests.Namespace,
+				Annotations: map[string]string{annotations.GatewayAPIListenerKey: "infra/gateway/https"},
+			}}
+			Expect(newTLSSecretIdentifier(ingress, tls)).To(Equal(secretIdentifier{Namespace: "infra", Name: "contoso-cert"}))
+		})
+	})
 })
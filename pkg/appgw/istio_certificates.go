@@ -0,0 +1,27 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"encoding/base64"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// getIstioSecretToCertificateMap obtains the certificates of the secrets HTTPS Istio Gateway servers reference.
+func (c *appGwConfigBuilder) getIstioSecretToCertificateMap(cbCtx *ConfigBuilderContext) map[secretIdentifier]*string {
+	secretIDCertificateMap := make(map[secretIdentifier]*string)
+	for _, config := range c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices) {
+		if config.Protocol != n.HTTPS {
+			continue
+		}
+		if cert := c.k8sContext.CertificateSecretStore.GetPfxCertificate(config.Secret.secretKey()); cert != nil {
+			secretIDCertificateMap[config.Secret] = to.StringPtr(base64.StdEncoding.EncodeToString(cert))
+		}
+	}
+	return secretIDCertificateMap
+}
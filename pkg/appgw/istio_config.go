@@ -6,6 +6,8 @@
 package appgw
 
 import (
+	"strings"
+
 	"github.com/golang/glog"
 	"github.com/knative/pkg/apis/istio/v1alpha3"
 )
@@ -42,20 +44,43 @@ func generateIstioMatchID(virtualService *v1alpha3.VirtualService, rule *v1alpha
 	}
 }
 
-func generateIstioDestinationID(virtualService *v1alpha3.VirtualService, destination *v1alpha3.Destination) istioDestinationIdentifier {
-	return istioDestinationIdentifier{
-		serviceIdentifier: serviceIdentifier{
-			Namespace: virtualService.Namespace,
-			Name:      destination.Host,
-		},
+func generateIstioDestinationID(virtualService *v1alpha3.VirtualService, rule *v1alpha3.HTTPRoute, destination *v1alpha3.Destination) istioDestinationIdentifier {
+	destinationID := istioDestinationIdentifier{
+		serviceIdentifier: getIstioDestinationService(virtualService.Namespace, destination.Host),
 
 		istioVirtualServiceIdentifier: istioVirtualServiceIdentifier{
 			Namespace: virtualService.Namespace,
 			Name:      virtualService.Name,
 		},
 
-		DestinationHost:   destination.Host,
-		DestinationSubset: destination.Subset,
-		DestinationPort:   destination.Port.Number,
+		DestinationHost:     destination.Host,
+		DestinationSubset:   destination.Subset,
+		DestinationPort:     destination.Port.Number,
+		DestinationPortName: destination.Port.Name,
+
+		Timeout: rule.Timeout,
+	}
+	if rule.Rewrite != nil {
+		destinationID.RewriteURI = rule.Rewrite.URI
+		destinationID.RewriteAuthority = rule.Rewrite.Authority
+	}
+	return destinationID
+}
+
+// getIstioDestinationService figures out the Service the host of a destination refers to. A short name refers to a
+// Service in the namespace of the VirtualService; "name.namespace" and "name.namespace.svc.cluster.local" to a Service
+// in the given namespace.
+func getIstioDestinationService(namespace string, host string) serviceIdentifier {
+	host = strings.TrimSuffix(host, ".svc.cluster.local")
+	host = strings.TrimSuffix(host, ".svc")
+	if chunks := strings.Split(host, "."); len(chunks) == 2 {
+		return serviceIdentifier{
+			Namespace: chunks[1],
+			Name:      chunks[0],
+		}
+	}
+	return serviceIdentifier{
+		Namespace: namespace,
+		Name:      host,
 	}
 }
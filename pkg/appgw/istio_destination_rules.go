@@ -0,0 +1,170 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"math"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+)
+
+// maxRequestTimeoutInSec is the longest request timeout App Gateway accepts.
+const maxRequestTimeoutInSec = 86400
+
+// getIstioDestinationRule finds the DestinationRule for the Service of the destination.
+func getIstioDestinationRule(cbCtx *ConfigBuilderContext, destinationID istioDestinationIdentifier) *v1alpha3.DestinationRule {
+	for _, destinationRule := range cbCtx.IstioDestinationRules {
+		if getIstioDestinationService(destinationRule.Namespace, destinationRule.Spec.Host) == destinationID.serviceIdentifier {
+			return destinationRule
+		}
+	}
+	return nil
+}
+
+// getIstioSubset finds the subset of the destination in its DestinationRule.
+func getIstioSubset(cbCtx *ConfigBuilderContext, destinationID istioDestinationIdentifier) *v1alpha3.Subset {
+	if destinationID.DestinationSubset == "" {
+		return nil
+	}
+	destinationRule := getIstioDestinationRule(cbCtx, destinationID)
+	if destinationRule == nil {
+		return nil
+	}
+	for idx := range destinationRule.Spec.Subsets {
+		if subset := &destinationRule.Spec.Subsets[idx]; subset.Name == destinationID.DestinationSubset {
+			return subset
+		}
+	}
+	return nil
+}
+
+// getIstioTrafficPolicy works out the traffic policy of the destination. The policy of the subset overrides the one
+// of the DestinationRule; Within either, the settings for the port of the destination override the others.
+func getIstioTrafficPolicy(cbCtx *ConfigBuilderContext, destinationID istioDestinationIdentifier) *v1alpha3.TrafficPolicy {
+	destinationRule := getIstioDestinationRule(cbCtx, destinationID)
+	if destinationRule == nil {
+		return nil
+	}
+	policy := mergeIstioTrafficPolicy(nil, destinationRule.Spec.TrafficPolicy, destinationID)
+	if subset := getIstioSubset(cbCtx, destinationID); subset != nil {
+		policy = mergeIstioTrafficPolicy(policy, subset.TrafficPolicy, destinationID)
+	}
+	return policy
+}
+
+func mergeIstioTrafficPolicy(policy *v1alpha3.TrafficPolicy, override *v1alpha3.TrafficPolicy, destinationID istioDestinationIdentifier) *v1alpha3.TrafficPolicy {
+	if override == nil {
+		return policy
+	}
+	merged := &v1alpha3.TrafficPolicy{}
+	if policy != nil {
+		*merged = *policy
+	}
+	mergeIstioPolicySettings(merged, override.LoadBalancer, override.ConnectionPool, override.TLS)
+	for _, portSettings := range override.PortLevelSettings {
+		if isIstioPortSelected(portSettings.Port, destinationID) {
+			mergeIstioPolicySettings(merged, portSettings.LoadBalancer, portSettings.ConnectionPool, portSettings.TLS)
+		}
+	}
+	return merged
+}
+
+func mergeIstioPolicySettings(policy *v1alpha3.TrafficPolicy, loadBalancer *v1alpha3.LoadBalancerSettings, connectionPool *v1alpha3.ConnectionPoolSettings, tls *v1alpha3.TLSSettings) {
+	if loadBalancer != nil {
+		policy.LoadBalancer = loadBalancer
+	}
+	if connectionPool != nil {
+		policy.ConnectionPool = connectionPool
+	}
+	if tls != nil {
+		policy.TLS = tls
+	}
+}
+
+func isIstioPortSelected(port v1alpha3.PortSelector, destinationID istioDestinationIdentifier) bool {
+	if port.Number != 0 {
+		return port.Number == destinationID.DestinationPort
+	}
+	return port.Name != "" && port.Name == destinationID.DestinationPortName
+}
+
+// applyIstioTrafficPolicy carries over what App Gateway can do of a traffic policy onto the HTTP settings: a
+// consistent hash on a cookie becomes cookie based affinity, the connect timeout the request timeout, and TLS
+// origination HTTPS to the backend.
+func applyIstioTrafficPolicy(httpSettings *n.ApplicationGatewayBackendHTTPSettings, policy *v1alpha3.TrafficPolicy) {
+	if policy == nil {
+		return
+	}
+
+	if lb := policy.LoadBalancer; lb != nil && lb.ConsistentHash != nil && lb.ConsistentHash.HTTPCookie != nil {
+		httpSettings.CookieBasedAffinity = n.Enabled
+		if cookieName := lb.ConsistentHash.HTTPCookie.Name; cookieName != "" {
+			httpSettings.AffinityCookieName = to.StringPtr(cookieName)
+		}
+	}
+
+	if pool := policy.ConnectionPool; pool != nil && pool.TCP != nil && pool.TCP.ConnectTimeout != "" {
+		if timeout, err := time.ParseDuration(pool.TCP.ConnectTimeout); err != nil {
+			glog.Warningf("Ignoring connect timeout %q of the traffic policy: %s", pool.TCP.ConnectTimeout, err)
+		} else {
+			httpSettings.RequestTimeout = to.Int32Ptr(toRequestTimeoutInSec(timeout))
+		}
+	}
+
+	if tls := policy.TLS; tls != nil {
+		switch tls.Mode {
+		case v1alpha3.TLSmodeSimple:
+			httpSettings.Protocol = n.HTTPS
+			if tls.Sni != "" {
+				httpSettings.HostName = to.StringPtr(tls.Sni)
+			}
+		case v1alpha3.TLSmodeDisable, "":
+			// plain HTTP to the backend
+		default:
+			glog.Warningf("TLS mode %s of the traffic policy is not supported; Using HTTP to the backend", tls.Mode)
+		}
+	}
+}
+
+// toRequestTimeoutInSec rounds the timeout up to the whole seconds App Gateway accepts.
+func toRequestTimeoutInSec(timeout time.Duration) int32 {
+	seconds := math.Ceil(timeout.Seconds())
+	if seconds < 1 {
+		return 1
+	}
+	if seconds > maxRequestTimeoutInSec {
+		return maxRequestTimeoutInSec
+	}
+	return int32(seconds)
+}
+
+// filterIstioSubsetAddresses keeps the endpoint addresses of the pods the subset of the destination selects.
+func (c *appGwConfigBuilder) filterIstioSubsetAddresses(endpointSubset v1.EndpointSubset, subset *v1alpha3.Subset, namespace string) v1.EndpointSubset {
+	selectedPods := make(map[string]interface{})
+	for _, pod := range c.k8sContext.ListPodsByServiceSelector(subset.Labels) {
+		if pod.Namespace == namespace {
+			selectedPods[pod.Name] = nil
+		}
+	}
+
+	filtered := v1.EndpointSubset{
+		Ports: endpointSubset.Ports,
+	}
+	for _, address := range endpointSubset.Addresses {
+		if address.TargetRef == nil || address.TargetRef.Kind != "Pod" {
+			continue
+		}
+		if _, ok := selectedPods[address.TargetRef.Name]; ok {
+			filtered.Addresses = append(filtered.Addresses, address)
+		}
+	}
+	return filtered
+}
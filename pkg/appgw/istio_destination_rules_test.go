@@ -0,0 +1,166 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Istio DestinationRules", func() {
+	newVirtualService := func(subset string) *v1alpha3.VirtualService {
+		return &v1alpha3.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "virtual-service",
+				Namespace: tests.Namespace,
+			},
+			Spec: v1alpha3.VirtualServiceSpec{
+				Hosts:    []string{tests.Host},
+				Gateways: []string{"gateway"},
+				HTTP: []v1alpha3.HTTPRoute{
+					{
+						Route: []v1alpha3.HTTPRouteDestination{
+							{
+								Destination: v1alpha3.Destination{
+									Host:   tests.ServiceName,
+									Subset: subset,
+									Port:   v1alpha3.PortSelector{Number: 80},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	destinationRule := &v1alpha3.DestinationRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "destination-rule",
+			Namespace: tests.Namespace,
+		},
+		Spec: v1alpha3.DestinationRuleSpec{
+			Host: tests.ServiceName + "." + tests.Namespace + ".svc.cluster.local",
+			TrafficPolicy: &v1alpha3.TrafficPolicy{
+				LoadBalancer: &v1alpha3.LoadBalancerSettings{
+					ConsistentHash: &v1alpha3.ConsistentHashLB{
+						HTTPCookie: &v1alpha3.HTTPCookie{Name: "session", TTL: "0s"},
+					},
+				},
+				ConnectionPool: &v1alpha3.ConnectionPoolSettings{
+					TCP: &v1alpha3.TCPSettings{ConnectTimeout: "1500ms"},
+				},
+				TLS: &v1alpha3.TLSSettings{Mode: v1alpha3.TLSmodeSimple},
+			},
+			Subsets: []v1alpha3.Subset{
+				{
+					Name:   "v1",
+					Labels: map[string]string{"version": "v1"},
+					TrafficPolicy: &v1alpha3.TrafficPolicy{
+						ConnectionPool: &v1alpha3.ConnectionPoolSettings{
+							TCP: &v1alpha3.TCPSettings{ConnectTimeout: "30s"},
+						},
+					},
+				},
+				{
+					Name:   "v2",
+					Labels: map[string]string{"version": "v2"},
+				},
+			},
+		},
+	}
+
+	newPod := func(name string, version string) *v1.Pod {
+		pod := tests.NewPodTestFixture(tests.Namespace, name)
+		pod.Labels["version"] = version
+		return &pod
+	}
+
+	var cb appGwConfigBuilder
+	BeforeEach(func() {
+		cb = newConfigBuilderFixture(nil)
+		endpoints := tests.NewEndpointsFixture()
+		endpoints.Subsets[0].Addresses = []v1.EndpointAddress{
+			{IP: "10.0.0.1", TargetRef: &v1.ObjectReference{Kind: "Pod", Namespace: tests.Namespace, Name: "pod-v1"}},
+			{IP: "10.0.0.2", TargetRef: &v1.ObjectReference{Kind: "Pod", Namespace: tests.Namespace, Name: "pod-v2"}},
+		}
+		_ = cb.k8sContext.Caches.Endpoints.Add(endpoints)
+		_ = cb.k8sContext.Caches.Service.Add(tests.NewServiceFixture(*tests.NewServicePortsFixture()...))
+		_ = cb.k8sContext.Caches.Pods.Add(newPod("pod-v1", "v1"))
+		_ = cb.k8sContext.Caches.Pods.Add(newPod("pod-v2", "v2"))
+	})
+
+	newContext := func(virtualService *v1alpha3.VirtualService) *ConfigBuilderContext {
+		return &ConfigBuilderContext{
+			IstioVirtualServices:   []*v1alpha3.VirtualService{virtualService},
+			IstioDestinationRules:  []*v1alpha3.DestinationRule{destinationRule},
+			EnableIstioIntegration: true,
+		}
+	}
+
+	getSettings := func(virtualService *v1alpha3.VirtualService) *n.ApplicationGatewayBackendHTTPSettings {
+		_, settingsMap, _, err := cb.getIstioDestinationsAndSettingsMap(newContext(virtualService))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(settingsMap).To(HaveLen(1))
+		for _, settings := range settingsMap {
+			return settings
+		}
+		return nil
+	}
+
+	Context("with a traffic policy", func() {
+		It("maps the cookie hash, the connect timeout and TLS onto the HTTP settings", func() {
+			settings := getSettings(newVirtualService(""))
+			Expect(settings.CookieBasedAffinity).To(Equal(n.Enabled))
+			Expect(*settings.AffinityCookieName).To(Equal("session"))
+			Expect(*settings.RequestTimeout).To(Equal(int32(2)))
+			Expect(settings.Protocol).To(Equal(n.HTTPS))
+		})
+
+		It("lets the policy of the subset override the one of the DestinationRule", func() {
+			settings := getSettings(newVirtualService("v1"))
+			Expect(*settings.RequestTimeout).To(Equal(int32(30)))
+			Expect(settings.CookieBasedAffinity).To(Equal(n.Enabled))
+			Expect(settings.Protocol).To(Equal(n.HTTPS))
+		})
+	})
+
+	Context("with a subset", func() {
+		It("creates a pool with the endpoints of the labelled pods only", func() {
+			virtualService := newVirtualService("v2")
+			pools := cb.newIstioBackendPoolMap(newContext(virtualService))
+			Expect(pools).To(HaveLen(1))
+			for destinationID, pool := range pools {
+				Expect(destinationID.DestinationSubset).To(Equal("v2"))
+				Expect(*pool.Name).To(ContainSubstring(tests.ServiceName + "-v2"))
+				Expect(*pool.BackendAddresses).To(ConsistOf(n.ApplicationGatewayBackendAddress{IPAddress: to.StringPtr("10.0.0.2")}))
+			}
+		})
+
+		It("gives each subset its own settings", func() {
+			v1Settings := getSettings(newVirtualService("v1"))
+			v2Settings := getSettings(newVirtualService("v2"))
+			Expect(*v1Settings.Name).ToNot(Equal(*v2Settings.Name))
+		})
+	})
+
+	Context("with a timeout App Gateway cannot take", func() {
+		It("rounds it up to whole seconds within the range App Gateway accepts", func() {
+			Expect(toRequestTimeoutInSec(10 * time.Millisecond)).To(Equal(int32(1)))
+			Expect(toRequestTimeoutInSec(1500 * time.Millisecond)).To(Equal(int32(2)))
+			Expect(toRequestTimeoutInSec(48 * time.Hour)).To(Equal(int32(maxRequestTimeoutInSec)))
+		})
+	})
+})
@@ -6,39 +6,66 @@
 package appgw
 
 import (
+	"strings"
+
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/golang/glog"
 	"github.com/knative/pkg/apis/istio/v1alpha3"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 )
 
 func (c *appGwConfigBuilder) getListenerConfigsFromIstio(istioGateways []*v1alpha3.Gateway, istioVirtualServices []*v1alpha3.VirtualService) map[listenerIdentifier]listenerAzConfig {
-	knownHosts := make(map[string]interface{})
-	for _, virtualService := range istioVirtualServices {
-		for _, host := range virtualService.Spec.Hosts {
-			knownHosts[host] = nil
-		}
-	}
-
 	allListeners := make(map[listenerIdentifier]listenerAzConfig)
+	// HTTP listeners of servers with TLS.HTTPSRedirect; These redirect to the HTTPS listener for the same host.
+	redirectingListeners := make(map[listenerIdentifier]interface{})
 	for _, igwy := range istioGateways {
-		for _, server := range igwy.Spec.Servers {
-			if server.Port.Protocol != v1alpha3.ProtocolHTTP {
+		for serverIdx := range igwy.Spec.Servers {
+			server := &igwy.Spec.Servers[serverIdx]
+			var config listenerAzConfig
+			switch server.Port.Protocol {
+			case v1alpha3.ProtocolHTTP:
+				config = listenerAzConfig{Protocol: n.HTTP}
+			case v1alpha3.ProtocolHTTPS:
+				secID, ok := c.getIstioServerSecret(igwy, *server)
+				if !ok {
+					continue
+				}
+				config = listenerAzConfig{Protocol: n.HTTPS, Secret: *secID}
+			default:
 				glog.Infof("[istio] AGIC does not support Gateway with Server.Port.Protocol=%+v", server.Port.Protocol)
 				continue
 			}
-			for _, host := range server.Hosts {
-				if _, exist := knownHosts[host]; !exist {
-					continue
-				}
-				listenerID := listenerIdentifier{
-					FrontendPort: int32(server.Port.Number),
-					HostName:     host,
+			for _, virtualService := range istioVirtualServices {
+				for _, listenerID := range getIstioServerListeners(igwy, server, virtualService) {
+					allListeners[listenerID] = config
+					if config.Protocol == n.HTTP && server.TLS != nil && server.TLS.HTTPSRedirect {
+						redirectingListeners[listenerID] = nil
+					}
 				}
-				allListeners[listenerID] = listenerAzConfig{Protocol: n.HTTP}
 			}
 		}
 	}
 
+	// Link the redirecting HTTP listeners and the HTTPS listeners they redirect to with a redirect configuration,
+	// the same way the ssl-redirect annotation does for Ingresses.
+	for listenerID := range redirectingListeners {
+		targetListenerID, exists := getIstioHTTPSListener(allListeners, listenerID.HostName)
+		if !exists {
+			glog.Warningf("[istio] Gateway server for host %s requests an HTTPS redirect, but no HTTPS server serves this host", listenerID.HostName)
+			continue
+		}
+		redirectName := generateSSLRedirectConfigurationName(targetListenerID)
+
+		targetConfig := allListeners[targetListenerID]
+		targetConfig.SslRedirectConfigurationName = redirectName
+		allListeners[targetListenerID] = targetConfig
+
+		config := allListeners[listenerID]
+		config.SslRedirectConfigurationName = redirectName
+		allListeners[listenerID] = config
+	}
+
 	// App Gateway must have at least one listener - the default one!
 	if len(allListeners) == 0 {
 		allListeners[defaultFrontendListenerIdentifier()] = listenerAzConfig{
@@ -49,3 +76,115 @@ func (c *appGwConfigBuilder) getListenerConfigsFromIstio(istioGateways []*v1alph
 
 	return allListeners
 }
+
+// getIstioServerSecret figures out the secret holding the TLS certificate of an HTTPS Gateway server. The secret is
+// the one named by TLS.CredentialName in the namespace of the Gateway; It must have been loaded in the secret store.
+func (c *appGwConfigBuilder) getIstioServerSecret(gateway *v1alpha3.Gateway, server v1alpha3.Server) (*secretIdentifier, bool) {
+	if server.TLS == nil || server.TLS.CredentialName == "" {
+		// TLS.ServerCertificate and TLS.PrivateKey are paths within the Istio ingress gateway pod; AGIC cannot read them.
+		glog.Infof("[istio] AGIC requires TLS.CredentialName for HTTPS servers of Gateway %s/%s", gateway.Namespace, gateway.Name)
+		return nil, false
+	}
+	if server.TLS.Mode != "" && server.TLS.Mode != v1alpha3.TLSModeSimple {
+		glog.Infof("[istio] AGIC does not support Gateway with Server.TLS.Mode=%+v", server.TLS.Mode)
+		return nil, false
+	}
+	secID := secretIdentifier{
+		Namespace: gateway.Namespace,
+		Name:      server.TLS.CredentialName,
+	}
+	if c.k8sContext.CertificateSecretStore.GetPfxCertificate(secID.secretKey()) == nil {
+		glog.Errorf("[istio] Unable to find the secret %s of Gateway %s/%s", secID.secretKey(), gateway.Namespace, gateway.Name)
+		return nil, false
+	}
+	return &secID, true
+}
+
+// getIstioHTTPSListener finds the HTTPS listener for the given host; The one on the lowest port when there are several.
+func getIstioHTTPSListener(listeners map[listenerIdentifier]listenerAzConfig, hostName string) (listenerIdentifier, bool) {
+	var found listenerIdentifier
+	exists := false
+	for listenerID, config := range listeners {
+		if config.Protocol != n.HTTPS || listenerID.HostName != hostName {
+			continue
+		}
+		if !exists || listenerID.FrontendPort < found.FrontendPort {
+			found = listenerID
+			exists = true
+		}
+	}
+	return found, exists
+}
+
+// getIstioVirtualServiceListeners lists the listeners, among the given ones, which serve the hosts of the VirtualService;
+// Along with the Gateways their servers belong to.
+func getIstioVirtualServiceListeners(istioGateways []*v1alpha3.Gateway, virtualService *v1alpha3.VirtualService, listenerConfigs map[listenerIdentifier]listenerAzConfig) map[listenerIdentifier][]*v1alpha3.Gateway {
+	listenerGateways := make(map[listenerIdentifier][]*v1alpha3.Gateway)
+	for _, igwy := range istioGateways {
+		for serverIdx := range igwy.Spec.Servers {
+			for _, listenerID := range getIstioServerListeners(igwy, &igwy.Spec.Servers[serverIdx], virtualService) {
+				if _, exists := listenerConfigs[listenerID]; !exists {
+					continue
+				}
+				listenerGateways[listenerID] = append(listenerGateways[listenerID], igwy)
+			}
+		}
+	}
+	return listenerGateways
+}
+
+// getIstioServerListeners lists the listeners of the Gateway server for the hosts of the VirtualService; One per host
+// the server accepts, provided the VirtualService is bound to the Gateway.
+func getIstioServerListeners(gateway *v1alpha3.Gateway, server *v1alpha3.Server, virtualService *v1alpha3.VirtualService) []listenerIdentifier {
+	if !k8scontext.IsIstioGatewayBound(virtualService, gateway) {
+		return nil
+	}
+	var listenerIDs []listenerIdentifier
+	for _, host := range virtualService.Spec.Hosts {
+		accepted := false
+		for _, serverHost := range server.Hosts {
+			if istioServerAcceptsHost(gateway, serverHost, virtualService, host) {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			continue
+		}
+		hostName := host
+		if host == "*" {
+			// A listener without a host name serves every host.
+			hostName = ""
+		} else if strings.Contains(host, "*") {
+			glog.Infof("[istio] AGIC does not support the wildcard host %s of VirtualService %s/%s", host, virtualService.Namespace, virtualService.Name)
+			continue
+		}
+		listenerIDs = append(listenerIDs, listenerIdentifier{
+			FrontendPort: int32(server.Port.Number),
+			HostName:     hostName,
+		})
+	}
+	return listenerIDs
+}
+
+// istioServerAcceptsHost figures out whether the host of the Gateway server, which may be a wildcard and may be prefixed
+// with the namespaces of the VirtualServices it accepts ("namespace/host"), accepts the host of the VirtualService.
+func istioServerAcceptsHost(gateway *v1alpha3.Gateway, serverHost string, virtualService *v1alpha3.VirtualService, host string) bool {
+	if chunks := strings.SplitN(serverHost, "/", 2); len(chunks) == 2 {
+		namespace := chunks[0]
+		if namespace == "." {
+			namespace = gateway.Namespace
+		}
+		if namespace != "*" && namespace != virtualService.Namespace {
+			return false
+		}
+		serverHost = chunks[1]
+	}
+	switch {
+	case serverHost == "*" || serverHost == host:
+		return true
+	case strings.HasPrefix(serverHost, "*."):
+		return strings.HasSuffix(host, serverHost[1:])
+	}
+	return false
+}
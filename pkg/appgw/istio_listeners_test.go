@@ -0,0 +1,129 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Istio Gateway servers", func() {
+	listener80 := listenerIdentifier{FrontendPort: 80, HostName: tests.Host}
+	listener443 := listenerIdentifier{FrontendPort: 443, HostName: tests.Host}
+	secretID := secretIdentifier{Namespace: tests.Namespace, Name: tests.NameOfSecret}
+	redirectName := generateSSLRedirectConfigurationName(listener443)
+
+	newGateway := func(servers ...v1alpha3.Server) *v1alpha3.Gateway {
+		return &v1alpha3.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway",
+				Namespace: tests.Namespace,
+				Annotations: map[string]string{
+					annotations.IstioGatewayKey: annotations.ApplicationGatewayIngressClass,
+				},
+			},
+			Spec: v1alpha3.GatewaySpec{
+				Servers: servers,
+			},
+		}
+	}
+	httpServer := v1alpha3.Server{
+		Port:  v1alpha3.Port{Number: 80, Protocol: v1alpha3.ProtocolHTTP},
+		Hosts: []string{tests.Host},
+		TLS:   &v1alpha3.TLSOptions{HTTPSRedirect: true},
+	}
+	httpsServer := v1alpha3.Server{
+		Port:  v1alpha3.Port{Number: 443, Protocol: v1alpha3.ProtocolHTTPS},
+		Hosts: []string{tests.Host},
+		TLS: &v1alpha3.TLSOptions{
+			Mode:           v1alpha3.TLSModeSimple,
+			CredentialName: tests.NameOfSecret,
+		},
+	}
+	virtualServices := []*v1alpha3.VirtualService{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "virtual-service",
+				Namespace: tests.Namespace,
+			},
+			Spec: v1alpha3.VirtualServiceSpec{
+				Hosts:    []string{tests.Host},
+				Gateways: []string{"gateway"},
+			},
+		},
+	}
+
+	Context("with an HTTPS server and an HTTP server redirecting to it", func() {
+		cb := newConfigBuilderFixture(nil)
+		cbCtx := &ConfigBuilderContext{
+			IstioGateways:          []*v1alpha3.Gateway{newGateway(httpServer, httpsServer)},
+			IstioVirtualServices:   virtualServices,
+			EnableIstioIntegration: true,
+		}
+
+		It("creates an HTTPS listener with the certificate of the credential", func() {
+			configs := cb.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+			Expect(configs).To(HaveLen(2))
+			Expect(configs[listener443]).To(Equal(listenerAzConfig{
+				Protocol:                     n.HTTPS,
+				Secret:                       secretID,
+				SslRedirectConfigurationName: redirectName,
+			}))
+			Expect(configs[listener80]).To(Equal(listenerAzConfig{
+				Protocol:                     n.HTTP,
+				SslRedirectConfigurationName: redirectName,
+			}))
+
+			certificates := cb.getSslCertificates(cbCtx)
+			Expect(*certificates).To(HaveLen(1))
+			Expect(*(*certificates)[0].Name).To(Equal(secretID.secretFullName()))
+		})
+
+		It("redirects the HTTP listener to the HTTPS listener", func() {
+			redirects := cb.getRedirectConfigurations(cbCtx)
+			Expect(*redirects).To(HaveLen(1))
+			Expect(*(*redirects)[0].Name).To(Equal(redirectName))
+			Expect(*(*redirects)[0].TargetListener.ID).To(Equal(cb.appGwIdentifier.listenerID(generateListenerName(listener443))))
+
+			pathMaps := cb.getIstioPathMaps(cbCtx)
+			Expect(pathMaps).To(HaveKey(listener80))
+			Expect(*pathMaps[listener80].DefaultRedirectConfiguration.ID).To(Equal(cb.appGwIdentifier.redirectConfigurationID(redirectName)))
+			Expect(pathMaps[listener80].DefaultBackendAddressPool).To(BeNil())
+		})
+	})
+
+	Context("with HTTPS servers AGIC cannot terminate TLS for", func() {
+		cb := newConfigBuilderFixture(nil)
+
+		It("skips servers without a credential, with an unknown secret, or with a mode other than SIMPLE", func() {
+			noCredential := httpsServer
+			noCredential.TLS = &v1alpha3.TLSOptions{ServerCertificate: "/etc/certs/server.pem", PrivateKey: "/etc/certs/key.pem"}
+			unknownSecret := httpsServer
+			unknownSecret.TLS = &v1alpha3.TLSOptions{CredentialName: "does-not-exist"}
+			passThrough := httpsServer
+			passThrough.TLS = &v1alpha3.TLSOptions{Mode: v1alpha3.TLSModePassThrough, CredentialName: tests.NameOfSecret}
+
+			for _, server := range []v1alpha3.Server{noCredential, unknownSecret, passThrough} {
+				configs := cb.getListenerConfigsFromIstio([]*v1alpha3.Gateway{newGateway(server)}, virtualServices)
+				Expect(configs).To(HaveLen(1))
+				Expect(configs).To(HaveKey(defaultFrontendListenerIdentifier()))
+			}
+		})
+
+		It("does not redirect the HTTP listener when no HTTPS listener serves the host", func() {
+			configs := cb.getListenerConfigsFromIstio([]*v1alpha3.Gateway{newGateway(httpServer)}, virtualServices)
+			Expect(configs).To(Equal(map[listenerIdentifier]listenerAzConfig{
+				listener80: {Protocol: n.HTTP},
+			}))
+		})
+	})
+})
@@ -6,14 +6,36 @@
 package appgw
 
 import (
+	"crypto/md5"
 	"fmt"
+	"strings"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 )
 
-func (c *appGwConfigBuilder) getIstioBackendAddressPool(destinationID istioDestinationIdentifier, serviceBackendPair serviceBackendPortPair, addressPools map[string]*n.ApplicationGatewayBackendAddressPool) *n.ApplicationGatewayBackendAddressPool {
+func (c *appGwConfigBuilder) getIstioBackendAddressPool(destinationID istioDestinationIdentifier, serviceBackendPair serviceBackendPortPair, addressPools map[string]*n.ApplicationGatewayBackendAddressPool, cbCtx *ConfigBuilderContext) *n.ApplicationGatewayBackendAddressPool {
+	subset := c.getIstioDestinationSubset(destinationID, serviceBackendPair, cbCtx)
+	if subset == nil {
+		return nil
+	}
+	poolName := generateAddressPoolName(destinationID.destinationName(), destinationID.destinationPort(), serviceBackendPair.BackendPort)
+	if pool, ok := addressPools[poolName]; ok {
+		return pool
+	}
+	pool := c.newPool(poolName, *subset)
+	pool.ID = to.StringPtr(c.appGwIdentifier.addressPoolID(poolName))
+	return pool
+}
+
+// getIstioDestinationSubset returns the endpoints of the destination serving the backend port, narrowed down to the pods of
+// its subset; Nil when there are none.
+func (c *appGwConfigBuilder) getIstioDestinationSubset(destinationID istioDestinationIdentifier, serviceBackendPair serviceBackendPortPair, cbCtx *ConfigBuilderContext) *v1.EndpointSubset {
 	endpoints, err := c.k8sContext.GetEndpointsByService(destinationID.serviceKey())
 	if err != nil {
 		logLine := fmt.Sprintf("Failed fetching endpoints for service: %s", destinationID.serviceKey())
@@ -22,21 +44,19 @@ func (c *appGwConfigBuilder) getIstioBackendAddressPool(destinationID istioDesti
 		return nil
 	}
 
+	istioSubset := getIstioSubset(cbCtx, destinationID)
+	if destinationID.DestinationSubset != "" && istioSubset == nil {
+		glog.Errorf("Subset %s of service %s is not defined by any DestinationRule", destinationID.DestinationSubset, destinationID.serviceKey())
+		return nil
+	}
+
 	for _, subset := range endpoints.Subsets {
 		if _, portExists := getUniqueTCPPorts(subset)[serviceBackendPair.BackendPort]; portExists {
-			backendServicePort := ""
-			if destinationID.DestinationPort != 0 {
-				backendServicePort = fmt.Sprint(destinationID.DestinationPort)
-			} else {
-				// TODO(delqn): lookup port by name
+			if istioSubset != nil {
+				// only the pods labelled for the subset
+				subset = c.filterIstioSubsetAddresses(subset, istioSubset, destinationID.serviceIdentifier.Namespace)
 			}
-			poolName := generateAddressPoolName(destinationID.serviceFullName(), backendServicePort, serviceBackendPair.BackendPort)
-			if pool, ok := addressPools[poolName]; ok {
-				return pool
-			}
-			pool := c.newPool(poolName, subset)
-			pool.ID = to.StringPtr(c.appGwIdentifier.addressPoolID(poolName))
-			return pool
+			return &subset
 		}
 		logLine := fmt.Sprintf("Backend target port %d does not have matching endpoint port", serviceBackendPair.BackendPort)
 		glog.Error(logLine)
@@ -54,9 +74,89 @@ func (c *appGwConfigBuilder) newIstioBackendPoolMap(cbCtx *ConfigBuilderContext)
 	_, _, istioServiceBackendPairMap, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
 	for destinationID, serviceBackendPair := range istioServiceBackendPairMap {
 		backendPoolMap[destinationID] = &defaultPool
-		if pool := c.getIstioBackendAddressPool(destinationID, serviceBackendPair, addressPools); pool != nil {
+		if pool := c.getIstioBackendAddressPool(destinationID, serviceBackendPair, addressPools, cbCtx); pool != nil {
 			backendPoolMap[destinationID] = pool
 		}
 	}
 	return backendPoolMap
 }
+
+// getIstioWeightedPool builds the pool, which splits the requests of the HTTP route across its weighted destinations in
+// proportion to the pod addresses of each; Nil when a single destination gets the requests. Destinations are served with
+// the HTTP settings of the primary destination, so those with another backend port are left out of the split.
+func (c *appGwConfigBuilder) getIstioWeightedPool(cbCtx *ConfigBuilderContext, virtSvc *v1alpha3.VirtualService, ruleIdx int, serviceBackendPairs map[istioDestinationIdentifier]serviceBackendPortPair) *n.ApplicationGatewayBackendAddressPool {
+	rule := &virtSvc.Spec.HTTP[ruleIdx]
+	var weighted []*v1alpha3.HTTPRouteDestination
+	for idx := range rule.Route {
+		if rule.Route[idx].Weight > 0 {
+			weighted = append(weighted, &rule.Route[idx])
+		}
+	}
+	if len(weighted) < 2 {
+		return nil
+	}
+
+	primaryID := generateIstioDestinationID(virtSvc, rule, getIstioPrimaryDestination(rule))
+	primaryPair, exists := serviceBackendPairs[primaryID]
+	if !exists {
+		return nil
+	}
+
+	var backends []weightedBackend
+	var splitNames []string
+	// Destinations of the same pods, like one Service on two ports, take the requests together.
+	backendIdxByName := make(map[string]int)
+	for _, routeDestination := range weighted {
+		destinationID := generateIstioDestinationID(virtSvc, rule, &routeDestination.Destination)
+		if pair, exists := serviceBackendPairs[destinationID]; !exists || pair.BackendPort != primaryPair.BackendPort {
+			logLine := fmt.Sprintf("VirtualService %s/%s routes to %s on another backend port than %s; Leaving it out of the weighted split", virtSvc.Namespace, virtSvc.Name, destinationID.destinationName(), primaryID.destinationName())
+			glog.Warning("[istio] ", logLine)
+			c.recorder.Event(virtSvc, v1.EventTypeWarning, events.ReasonTrafficSplit, logLine)
+			continue
+		}
+		splitNames = append(splitNames, fmt.Sprintf("%s-%d", destinationID.destinationName(), routeDestination.Weight))
+		if idx, exists := backendIdxByName[destinationID.destinationName()]; exists {
+			backends[idx].Weight += int32(routeDestination.Weight)
+			continue
+		}
+		backendIdxByName[destinationID.destinationName()] = len(backends)
+
+		var addresses []n.ApplicationGatewayBackendAddress
+		if subset := c.getIstioDestinationSubset(destinationID, primaryPair, cbCtx); subset != nil {
+			addresses = *getAddressesForSubset(*subset)
+		}
+		backends = append(backends, weightedBackend{
+			Name:      destinationID.destinationName(),
+			Weight:    int32(routeDestination.Weight),
+			Addresses: addresses,
+		})
+	}
+	if len(backends) < 2 {
+		return nil
+	}
+
+	hash := md5.Sum([]byte(strings.Join(splitNames, "|")))
+	poolName := generateAddressPoolName(fmt.Sprintf("%s-weighted-%x", primaryID.destinationName(), hash[:4]), primaryID.destinationPort(), primaryPair.BackendPort)
+	addresses, counts := getWeightedAddresses(backends)
+	logLine := fmt.Sprintf("VirtualService %s/%s splits the requests of HTTP route %d through backend pool %s: %s", virtSvc.Namespace, virtSvc.Name, ruleIdx, poolName, describeSplit(backends, counts))
+	glog.V(3).Info("[istio] ", logLine)
+	c.recorder.Event(virtSvc, getSplitEventType(backends, counts), events.ReasonTrafficSplit, logLine)
+	return c.newPoolWithAddresses(poolName, addresses)
+}
+
+// getIstioWeightedPools builds the pools of the HTTP routes splitting their requests across weighted destinations.
+func (c *appGwConfigBuilder) getIstioWeightedPools(cbCtx *ConfigBuilderContext) []*n.ApplicationGatewayBackendAddressPool {
+	_, _, serviceBackendPairs, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
+	var pools []*n.ApplicationGatewayBackendAddressPool
+	for _, virtSvc := range cbCtx.IstioVirtualServices {
+		for ruleIdx := range virtSvc.Spec.HTTP {
+			if virtSvc.Spec.HTTP[ruleIdx].Redirect != nil {
+				continue
+			}
+			if pool := c.getIstioWeightedPool(cbCtx, virtSvc, ruleIdx, serviceBackendPairs); pool != nil {
+				pools = append(pools, pool)
+			}
+		}
+	}
+	return pools
+}
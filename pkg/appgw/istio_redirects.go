@@ -0,0 +1,78 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+)
+
+// getIstioRedirectConfigurations creates the redirect configurations for the HTTP routes of the VirtualServices, which
+// redirect rather than forward requests; One for every listener the route applies to.
+func (c *appGwConfigBuilder) getIstioRedirectConfigurations(cbCtx *ConfigBuilderContext) []n.ApplicationGatewayRedirectConfiguration {
+	var redirectConfigs []n.ApplicationGatewayRedirectConfiguration
+	listenerConfigs := c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+	for _, virtSvc := range cbCtx.IstioVirtualServices {
+		listenerGateways := getIstioVirtualServiceListeners(cbCtx.IstioGateways, virtSvc, listenerConfigs)
+		for ruleIdx := range virtSvc.Spec.HTTP {
+			rule := &virtSvc.Spec.HTTP[ruleIdx]
+			if rule.Redirect == nil {
+				continue
+			}
+			for listenerID, gateways := range listenerGateways {
+				if paths, catchAll := getIstioPaths(virtSvc, rule, gateways); len(paths) == 0 && !catchAll {
+					continue
+				}
+				if redirect := c.newIstioRedirectConfig(listenerID, listenerConfigs[listenerID], virtSvc, ruleIdx); redirect != nil {
+					redirectConfigs = append(redirectConfigs, *redirect)
+				}
+			}
+		}
+	}
+	return redirectConfigs
+}
+
+// newIstioRedirectConfig translates the Redirect of the HTTP route with the given index for the listener. The redirect
+// keeps the scheme of the listener, and the host of the listener unless the Redirect sets another authority; nil when
+// the listener accepts any host and the Redirect sets none, as App Gateway redirects to a fixed URL.
+func (c *appGwConfigBuilder) newIstioRedirectConfig(listenerID listenerIdentifier, listenerConfig listenerAzConfig, virtSvc *v1alpha3.VirtualService, ruleIdx int) *n.ApplicationGatewayRedirectConfiguration {
+	redirect := virtSvc.Spec.HTTP[ruleIdx].Redirect
+	authority := redirect.Authority
+	if authority == "" {
+		if listenerID.HostName == "" {
+			glog.Warningf("[istio] Redirect of HTTP route %d of VirtualService %s/%s has no authority; Skipping it for the listener of any host", ruleIdx, virtSvc.Namespace, virtSvc.Name)
+			return nil
+		}
+		authority = listenerID.HostName
+		if listenerID.FrontendPort != 80 && listenerID.FrontendPort != 443 {
+			authority = fmt.Sprintf("%s:%d", authority, listenerID.FrontendPort)
+		}
+	}
+
+	scheme := "http"
+	if listenerConfig.Protocol == n.HTTPS {
+		scheme = "https"
+	}
+
+	// Istio replaces the path when the Redirect has a URI, and keeps it otherwise.
+	redirectName := generateRouteRedirectConfigurationName(listenerID, virtSvc.Namespace, virtSvc.Name, fmt.Sprint(ruleIdx))
+	return &n.ApplicationGatewayRedirectConfiguration{
+		Etag: to.StringPtr("*"),
+		Name: to.StringPtr(redirectName),
+		ID:   to.StringPtr(c.appGwIdentifier.redirectConfigurationID(redirectName)),
+		ApplicationGatewayRedirectConfigurationPropertiesFormat: &n.ApplicationGatewayRedirectConfigurationPropertiesFormat{
+			// Istio redirects with 301 Moved Permanently
+			RedirectType:       n.Permanent,
+			TargetURL:          to.StringPtr(fmt.Sprintf("%s://%s%s", scheme, authority, redirect.URI)),
+			IncludePath:        to.BoolPtr(redirect.URI == ""),
+			IncludeQueryString: to.BoolPtr(true),
+		},
+	}
+}
@@ -0,0 +1,109 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"github.com/knative/pkg/apis/istio/common/v1alpha1"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Istio HTTP route redirects", func() {
+	listener80 := listenerIdentifier{FrontendPort: 80, HostName: tests.Host}
+
+	gateway := &v1alpha3.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: tests.Namespace,
+		},
+		Spec: v1alpha3.GatewaySpec{
+			Servers: []v1alpha3.Server{
+				{
+					Port:  v1alpha3.Port{Number: 80, Protocol: v1alpha3.ProtocolHTTP},
+					Hosts: []string{tests.Host},
+				},
+			},
+		},
+	}
+
+	newVirtualService := func(routes ...v1alpha3.HTTPRoute) *v1alpha3.VirtualService {
+		return &v1alpha3.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "virtual-service",
+				Namespace: tests.Namespace,
+			},
+			Spec: v1alpha3.VirtualServiceSpec{
+				Hosts:    []string{tests.Host},
+				Gateways: []string{"gateway"},
+				HTTP:     routes,
+			},
+		}
+	}
+
+	var cb appGwConfigBuilder
+	BeforeEach(func() {
+		cb = newConfigBuilderFixture(nil)
+	})
+
+	Context("with a route redirecting a path", func() {
+		virtualService := newVirtualService(v1alpha3.HTTPRoute{
+			Match: []v1alpha3.HTTPMatchRequest{
+				{URI: &v1alpha1.StringMatch{Exact: tests.URLPath1}},
+			},
+			Redirect: &v1alpha3.HTTPRedirect{URI: tests.URLPath2, Authority: tests.OtherHost},
+		})
+		cbCtx := &ConfigBuilderContext{
+			IstioGateways:          []*v1alpha3.Gateway{gateway},
+			IstioVirtualServices:   []*v1alpha3.VirtualService{virtualService},
+			EnableIstioIntegration: true,
+		}
+
+		It("creates a redirect configuration to the new URI and authority", func() {
+			redirects := cb.getIstioRedirectConfigurations(cbCtx)
+			Expect(redirects).To(HaveLen(1))
+			Expect(*redirects[0].TargetURL).To(Equal("http://" + tests.OtherHost + tests.URLPath2))
+			Expect(*redirects[0].IncludePath).To(BeFalse())
+			Expect(*redirects[0].IncludeQueryString).To(BeTrue())
+		})
+
+		It("attaches the redirect configuration to the path rule instead of a backend", func() {
+			redirects := cb.getIstioRedirectConfigurations(cbCtx)
+			pathRules := *cb.getIstioPathMaps(cbCtx)[listener80].PathRules
+			Expect(pathRules).To(HaveLen(1))
+			Expect(*pathRules[0].Paths).To(Equal([]string{tests.URLPath1}))
+			Expect(*pathRules[0].RedirectConfiguration.ID).To(Equal(*redirects[0].ID))
+			Expect(pathRules[0].BackendAddressPool).To(BeNil())
+			Expect(pathRules[0].BackendHTTPSettings).To(BeNil())
+		})
+	})
+
+	Context("with a catch-all route redirecting to the host of the listener", func() {
+		It("redirects every request of the listener, keeping the path", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Redirect: &v1alpha3.HTTPRedirect{},
+			})
+			cbCtx := &ConfigBuilderContext{
+				IstioGateways:          []*v1alpha3.Gateway{gateway},
+				IstioVirtualServices:   []*v1alpha3.VirtualService{virtualService},
+				EnableIstioIntegration: true,
+			}
+			redirects := cb.getIstioRedirectConfigurations(cbCtx)
+			Expect(redirects).To(HaveLen(1))
+			Expect(*redirects[0].TargetURL).To(Equal("http://" + tests.Host))
+			Expect(*redirects[0].IncludePath).To(BeTrue())
+
+			pathMap := cb.getIstioPathMaps(cbCtx)[listener80]
+			Expect(*pathMap.PathRules).To(BeEmpty())
+			Expect(*pathMap.DefaultRedirectConfiguration.ID).To(Equal(*redirects[0].ID))
+			Expect(pathMap.DefaultBackendAddressPool).To(BeNil())
+			Expect(pathMap.DefaultBackendHTTPSettings).To(BeNil())
+		})
+	})
+})
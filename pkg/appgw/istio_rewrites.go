@@ -0,0 +1,104 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+	"sort"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+)
+
+// istioHeadersRewriteRuleName names the one rewrite rule in the rewrite rule set of an HTTP route.
+const istioHeadersRewriteRuleName = "headers"
+
+// getIstioRewriteRuleSets creates the rewrite rule sets for the HTTP routes of the VirtualServices, which set or remove
+// headers.
+func (c *appGwConfigBuilder) getIstioRewriteRuleSets(cbCtx *ConfigBuilderContext) []n.ApplicationGatewayRewriteRuleSet {
+	var ruleSets []n.ApplicationGatewayRewriteRuleSet
+	for _, virtSvc := range cbCtx.IstioVirtualServices {
+		for ruleIdx := range virtSvc.Spec.HTTP {
+			if ruleSet := c.newIstioRewriteRuleSet(virtSvc, ruleIdx); ruleSet != nil {
+				ruleSets = append(ruleSets, *ruleSet)
+			}
+		}
+	}
+	return ruleSets
+}
+
+// newIstioRewriteRuleSet translates the headers the HTTP route with the given index sets and removes into a rewrite
+// rule set; nil when the route leaves the headers alone. App Gateway removes a header, which is set to an empty value.
+func (c *appGwConfigBuilder) newIstioRewriteRuleSet(virtSvc *v1alpha3.VirtualService, ruleIdx int) *n.ApplicationGatewayRewriteRuleSet {
+	rule := &virtSvc.Spec.HTTP[ruleIdx]
+	var requestOperations, responseOperations *v1alpha3.HeaderOperations
+	if rule.Headers != nil {
+		requestOperations = rule.Headers.Request
+		responseOperations = rule.Headers.Response
+	}
+	requestHeaders := getIstioHeaderConfigurations(requestOperations, nil)
+	responseHeaders := getIstioHeaderConfigurations(responseOperations, rule.RemoveResponseHeaders)
+	if len(requestHeaders) == 0 && len(responseHeaders) == 0 {
+		return nil
+	}
+
+	actionSet := &n.ApplicationGatewayRewriteRuleActionSet{}
+	if len(requestHeaders) > 0 {
+		actionSet.RequestHeaderConfigurations = &requestHeaders
+	}
+	if len(responseHeaders) > 0 {
+		actionSet.ResponseHeaderConfigurations = &responseHeaders
+	}
+
+	ruleSetName := generateRewriteRuleSetName(virtSvc.Namespace, virtSvc.Name, fmt.Sprint(ruleIdx))
+	return &n.ApplicationGatewayRewriteRuleSet{
+		Etag: to.StringPtr("*"),
+		Name: to.StringPtr(ruleSetName),
+		ID:   to.StringPtr(c.appGwIdentifier.rewriteRuleSetID(ruleSetName)),
+		ApplicationGatewayRewriteRuleSetPropertiesFormat: &n.ApplicationGatewayRewriteRuleSetPropertiesFormat{
+			RewriteRules: &[]n.ApplicationGatewayRewriteRule{
+				{
+					Name:         to.StringPtr(istioHeadersRewriteRuleName),
+					RuleSequence: to.Int32Ptr(100),
+					ActionSet:    actionSet,
+				},
+			},
+		},
+	}
+}
+
+// getIstioHeaderConfigurations lists the headers to set and to remove, sorted by name; The deprecated
+// removeResponseHeaders of an HTTP route name the headers to remove by their keys.
+func getIstioHeaderConfigurations(operations *v1alpha3.HeaderOperations, removed map[string]string) []n.ApplicationGatewayHeaderConfiguration {
+	values := make(map[string]string)
+	for name := range removed {
+		values[name] = ""
+	}
+	if operations != nil {
+		for _, name := range operations.Remove {
+			values[name] = ""
+		}
+		for name, value := range operations.Set {
+			values[name] = value
+		}
+	}
+
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var headers []n.ApplicationGatewayHeaderConfiguration
+	for _, name := range names {
+		headers = append(headers, n.ApplicationGatewayHeaderConfiguration{
+			HeaderName:  to.StringPtr(name),
+			HeaderValue: to.StringPtr(values[name]),
+		})
+	}
+	return headers
+}
@@ -0,0 +1,140 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/common/v1alpha1"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Istio HTTP route rewrites", func() {
+	listener80 := listenerIdentifier{FrontendPort: 80, HostName: tests.Host}
+
+	gateway := &v1alpha3.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: tests.Namespace,
+		},
+		Spec: v1alpha3.GatewaySpec{
+			Servers: []v1alpha3.Server{
+				{
+					Port:  v1alpha3.Port{Number: 80, Protocol: v1alpha3.ProtocolHTTP},
+					Hosts: []string{tests.Host},
+				},
+			},
+		},
+	}
+
+	route := v1alpha3.HTTPRoute{
+		Match: []v1alpha3.HTTPMatchRequest{
+			{URI: &v1alpha1.StringMatch{Prefix: tests.URLPath1}},
+		},
+		Route: []v1alpha3.HTTPRouteDestination{
+			{
+				Destination: v1alpha3.Destination{
+					Host: tests.ServiceName,
+					Port: v1alpha3.PortSelector{Number: 80},
+				},
+			},
+		},
+		Rewrite: &v1alpha3.HTTPRewrite{URI: "/", Authority: tests.OtherHost},
+		Timeout: "90s",
+		Headers: &v1alpha3.Headers{
+			Request: &v1alpha3.HeaderOperations{
+				Set:    map[string]string{"x-forwarded-by": "agic"},
+				Remove: []string{"x-debug"},
+			},
+			Response: &v1alpha3.HeaderOperations{
+				Remove: []string{"server"},
+				Add:    map[string]string{"x-cache": "miss"},
+			},
+		},
+		Retries: &v1alpha3.HTTPRetry{Attempts: 3},
+	}
+
+	virtualService := &v1alpha3.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "virtual-service",
+			Namespace: tests.Namespace,
+		},
+		Spec: v1alpha3.VirtualServiceSpec{
+			Hosts:    []string{tests.Host},
+			Gateways: []string{"gateway"},
+			HTTP:     []v1alpha3.HTTPRoute{route},
+		},
+	}
+
+	cbCtx := &ConfigBuilderContext{
+		IstioGateways:          []*v1alpha3.Gateway{gateway},
+		IstioVirtualServices:   []*v1alpha3.VirtualService{virtualService},
+		EnableIstioIntegration: true,
+	}
+
+	var cb appGwConfigBuilder
+	BeforeEach(func() {
+		cb = newConfigBuilderFixture(nil)
+		_ = cb.k8sContext.Caches.Endpoints.Add(tests.NewEndpointsFixture())
+		_ = cb.k8sContext.Caches.Service.Add(tests.NewServiceFixture(*tests.NewServicePortsFixture()...))
+	})
+
+	Context("with a route rewriting the URI and authority and setting a timeout", func() {
+		It("applies them to the HTTP settings of the destination", func() {
+			_, settingsByDestination, _, err := cb.getIstioDestinationsAndSettingsMap(cbCtx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(settingsByDestination).To(HaveLen(1))
+			for _, settings := range settingsByDestination {
+				Expect(*settings.Path).To(Equal("/"))
+				Expect(*settings.HostName).To(Equal(tests.OtherHost))
+				Expect(*settings.RequestTimeout).To(Equal(int32(90)))
+			}
+		})
+	})
+
+	Context("with a route setting and removing headers", func() {
+		It("creates a rewrite rule set and attaches it to the path rule", func() {
+			ruleSets := cb.getIstioRewriteRuleSets(cbCtx)
+			Expect(ruleSets).To(HaveLen(1))
+			actionSet := (*ruleSets[0].RewriteRules)[0].ActionSet
+			Expect(*actionSet.RequestHeaderConfigurations).To(Equal([]n.ApplicationGatewayHeaderConfiguration{
+				{HeaderName: to.StringPtr("x-debug"), HeaderValue: to.StringPtr("")},
+				{HeaderName: to.StringPtr("x-forwarded-by"), HeaderValue: to.StringPtr("agic")},
+			}))
+			Expect(*actionSet.ResponseHeaderConfigurations).To(Equal([]n.ApplicationGatewayHeaderConfiguration{
+				{HeaderName: to.StringPtr("server"), HeaderValue: to.StringPtr("")},
+			}))
+
+			pathRules := *cb.getIstioPathMaps(cbCtx)[listener80].PathRules
+			Expect(pathRules).To(HaveLen(1))
+			Expect(*pathRules[0].RewriteRuleSet.ID).To(Equal(*ruleSets[0].ID))
+		})
+
+		It("keeps the rewrite rule sets someone else created", func() {
+			cb.appGw.RewriteRuleSets = &[]n.ApplicationGatewayRewriteRuleSet{{Name: to.StringPtr("foreign")}}
+			ruleSets := *cb.getRewriteRuleSets(cbCtx)
+			Expect(ruleSets).To(HaveLen(2))
+			Expect(*ruleSets[0].Name).To(Equal("foreign"))
+		})
+	})
+
+	Context("with features App Gateway cannot express", func() {
+		It("reports them with an event", func() {
+			_ = cb.getIstioPathMaps(cbCtx)
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			event := <-recorder.Events
+			Expect(event).To(ContainSubstring("headers.response.add"))
+			Expect(event).To(ContainSubstring("retries"))
+		})
+	})
+})
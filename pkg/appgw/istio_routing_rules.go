@@ -7,91 +7,164 @@ package appgw
 
 import (
 	"fmt"
-	"strconv"
+	"strings"
+	"time"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 )
 
 func (c *appGwConfigBuilder) getIstioPathMaps(cbCtx *ConfigBuilderContext) map[listenerIdentifier]*n.ApplicationGatewayURLPathMap {
 	defaultAddressPoolID := to.StringPtr(c.appGwIdentifier.addressPoolID(defaultBackendAddressPoolName))
 	defaultHTTPSettingsID := to.StringPtr(c.appGwIdentifier.httpSettingsID(defaultBackendHTTPSettingsName))
 
-	// TODO(delqn)
-	istioHTTPSettings, _, _, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
-
+	_, settingsByDestination, serviceBackendPairs, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
 	backendByDestination := c.newIstioBackendPoolMap(cbCtx)
+	listenerConfigs := c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
 
 	urlPathMaps := make(map[listenerIdentifier]*n.ApplicationGatewayURLPathMap)
+	// Istio applies the first route matching a request, App Gateway the path rule with the most specific path; Routes
+	// shadowed by a catch-all route or the same paths of an earlier route are left out.
+	hasCatchAll := make(map[listenerIdentifier]interface{})
+	claimedPaths := make(map[listenerIdentifier]map[string]interface{})
 	for virtSvcIdx, virtSvc := range cbCtx.IstioVirtualServices {
-		for _, http := range virtSvc.Spec.HTTP {
-			// TODO(delqn): consider weights
-			host := http.Route[0].Destination.Host
-			var port uint32
-			if http.Route[0].Destination.Port.Number != 0 {
-				port = http.Route[0].Destination.Port.Number
-			} else {
-				port64, _ := strconv.ParseUint(http.Route[0].Destination.Port.Name, 10, 32)
-				port = uint32(port64)
-			}
-			for matchIdx, match := range http.Match {
-				dst := istioDestinationIdentifier{
-					serviceIdentifier: serviceIdentifier{
-						Namespace: virtSvc.Namespace,
-						Name:      virtSvc.Name,
-					},
-					istioVirtualServiceIdentifier: istioVirtualServiceIdentifier{
-						Namespace: virtSvc.Namespace,
-						Name:      virtSvc.Name,
-					},
-					// TODO(delqn)
-					DestinationHost: host,
-					DestinationPort: port,
+		c.reportUnsupportedIstioMatches(virtSvc)
+		c.reportUnsupportedIstioRouteFeatures(virtSvc)
+		listenerGateways := getIstioVirtualServiceListeners(cbCtx.IstioGateways, virtSvc, listenerConfigs)
+		for ruleIdx := range virtSvc.Spec.HTTP {
+			rule := &virtSvc.Spec.HTTP[ruleIdx]
+
+			// A route either redirects, or forwards to its destination.
+			var pool *n.ApplicationGatewayBackendAddressPool
+			var settings *n.ApplicationGatewayBackendHTTPSettings
+			if rule.Redirect == nil {
+				destination := getIstioPrimaryDestination(rule)
+				if destination == nil {
+					continue
 				}
 
-				// TODO(delqn)
-				listenerID := listenerIdentifier{
-					FrontendPort: 80,
-					HostName:     virtSvc.Spec.Hosts[0], // TODO(delqn),
+				destinationID := generateIstioDestinationID(virtSvc, rule, destination)
+				var poolFound, settingsFound bool
+				pool, poolFound = backendByDestination[destinationID]
+				settings, settingsFound = settingsByDestination[destinationID]
+				if !poolFound || !settingsFound {
+					continue
+				}
+				if weightedPool := c.getIstioWeightedPool(cbCtx, virtSvc, ruleIdx, serviceBackendPairs); weightedPool != nil {
+					pool = weightedPool
 				}
-				pool, found := backendByDestination[dst]
+			}
+			rewriteRuleSet := c.newIstioRewriteRuleSet(virtSvc, ruleIdx)
 
-				if !found {
+			for listenerID, gateways := range listenerGateways {
+				paths, catchAll := getIstioPaths(virtSvc, rule, gateways)
+				if len(paths) == 0 && !catchAll {
 					continue
 				}
-				pathMap := n.ApplicationGatewayURLPathMap{
-					Etag: to.StringPtr("*"),
-					Name: to.StringPtr(generateURLPathMapName(listenerID)),
-					ID:   to.StringPtr(c.appGwIdentifier.urlPathMapID(generateURLPathMapName(listenerID))),
-					ApplicationGatewayURLPathMapPropertiesFormat: &n.ApplicationGatewayURLPathMapPropertiesFormat{
-						DefaultBackendAddressPool:  &n.SubResource{ID: defaultAddressPoolID},
-						DefaultBackendHTTPSettings: &n.SubResource{ID: defaultHTTPSettingsID},
-						PathRules:                  &[]n.ApplicationGatewayPathRule{},
-					},
+				if _, shadowed := hasCatchAll[listenerID]; shadowed {
+					c.reportShadowedIstioRoute(virtSvc, ruleIdx, listenerID, nil)
+					continue
+				}
+				if !catchAll {
+					var shadowedPaths []string
+					if paths, shadowedPaths = getUnclaimedPaths(paths, claimedPaths[listenerID]); len(shadowedPaths) > 0 {
+						c.reportShadowedIstioRoute(virtSvc, ruleIdx, listenerID, shadowedPaths)
+					}
+					if len(paths) == 0 {
+						continue
+					}
 				}
 
-				pathRuleIdx := fmt.Sprintf("%d-%d", virtSvcIdx, matchIdx)
+				var redirect *n.ApplicationGatewayRedirectConfiguration
+				if rule.Redirect != nil {
+					if redirect = c.newIstioRedirectConfig(listenerID, listenerConfigs[listenerID], virtSvc, ruleIdx); redirect == nil {
+						continue
+					}
+				}
 
+				pathMap, exists := urlPathMaps[listenerID]
+				if !exists {
+					pathMap = &n.ApplicationGatewayURLPathMap{
+						Etag: to.StringPtr("*"),
+						Name: to.StringPtr(generateURLPathMapName(listenerID)),
+						ID:   to.StringPtr(c.appGwIdentifier.urlPathMapID(generateURLPathMapName(listenerID))),
+						ApplicationGatewayURLPathMapPropertiesFormat: &n.ApplicationGatewayURLPathMapPropertiesFormat{
+							DefaultBackendAddressPool:  &n.SubResource{ID: defaultAddressPoolID},
+							DefaultBackendHTTPSettings: &n.SubResource{ID: defaultHTTPSettingsID},
+							PathRules:                  &[]n.ApplicationGatewayPathRule{},
+						},
+					}
+					urlPathMaps[listenerID] = pathMap
+				}
+
+				if catchAll {
+					if redirect != nil {
+						pathMap.DefaultRedirectConfiguration = &n.SubResource{ID: redirect.ID}
+						pathMap.DefaultBackendAddressPool = nil
+						pathMap.DefaultBackendHTTPSettings = nil
+					} else {
+						pathMap.DefaultBackendAddressPool = &n.SubResource{ID: pool.ID}
+						pathMap.DefaultBackendHTTPSettings = &n.SubResource{ID: settings.ID}
+						if rewriteRuleSet != nil {
+							pathMap.DefaultRewriteRuleSet = &n.SubResource{ID: rewriteRuleSet.ID}
+						}
+					}
+					hasCatchAll[listenerID] = nil
+					continue
+				}
+
+				pathRuleIdx := fmt.Sprintf("%d-%d", virtSvcIdx, ruleIdx)
 				pathRule := n.ApplicationGatewayPathRule{
 					Etag: to.StringPtr("*"),
 					Name: to.StringPtr(generatePathRuleName(virtSvc.Namespace, virtSvc.Name, pathRuleIdx)),
 					ApplicationGatewayPathRulePropertiesFormat: &n.ApplicationGatewayPathRulePropertiesFormat{
-						Paths: &[]string{
-							match.URI.Prefix,
-						},
-						BackendAddressPool: &n.SubResource{ID: pool.ID},
-						// TODO(delqn)
-						BackendHTTPSettings: &n.SubResource{ID: istioHTTPSettings[0].ID},
+						Paths: &paths,
 					},
 				}
-				pathMap.PathRules = &[]n.ApplicationGatewayPathRule{
-					pathRule,
+				if redirect != nil {
+					pathRule.RedirectConfiguration = &n.SubResource{ID: redirect.ID}
+				} else {
+					pathRule.BackendAddressPool = &n.SubResource{ID: pool.ID}
+					pathRule.BackendHTTPSettings = &n.SubResource{ID: settings.ID}
+					if rewriteRuleSet != nil {
+						pathRule.RewriteRuleSet = &n.SubResource{ID: rewriteRuleSet.ID}
+					}
+				}
+				pathRules := append(*pathMap.PathRules, pathRule)
+				pathMap.PathRules = &pathRules
+
+				if _, exists := claimedPaths[listenerID]; !exists {
+					claimedPaths[listenerID] = make(map[string]interface{})
+				}
+				for _, path := range paths {
+					claimedPaths[listenerID][path] = nil
 				}
-				urlPathMaps[listenerID] = &pathMap
 			}
 		}
 	}
 
+	// HTTP listeners of servers with TLS.HTTPSRedirect redirect all requests to the HTTPS listener for the same host.
+	for listenerID, config := range listenerConfigs {
+		if config.Protocol != n.HTTP || config.SslRedirectConfigurationName == "" {
+			continue
+		}
+		urlPathMaps[listenerID] = &n.ApplicationGatewayURLPathMap{
+			Etag: to.StringPtr("*"),
+			Name: to.StringPtr(generateURLPathMapName(listenerID)),
+			ID:   to.StringPtr(c.appGwIdentifier.urlPathMapID(generateURLPathMapName(listenerID))),
+			ApplicationGatewayURLPathMapPropertiesFormat: &n.ApplicationGatewayURLPathMapPropertiesFormat{
+				DefaultRedirectConfiguration: resourceRef(c.appGwIdentifier.redirectConfigurationID(config.SslRedirectConfigurationName)),
+				PathRules:                    &[]n.ApplicationGatewayPathRule{},
+			},
+		}
+	}
+
 	// if no url pathmaps were created, then add a default path map since this will be translated to
 	// a basic request routing rule which is needed on Application Gateway to avoid validation error.
 	if len(urlPathMaps) == 0 {
@@ -111,3 +184,213 @@ func (c *appGwConfigBuilder) getIstioPathMaps(cbCtx *ConfigBuilderContext) map[l
 
 	return urlPathMaps
 }
+
+// reportShadowedIstioRoute reports the HTTP route of the VirtualService, which earlier routes on the listener shadow, with an
+// event on the VirtualService; Either the given paths of the route, or the whole route when no paths are given.
+func (c *appGwConfigBuilder) reportShadowedIstioRoute(virtSvc *v1alpha3.VirtualService, ruleIdx int, listenerID listenerIdentifier, paths []string) {
+	logLine := fmt.Sprintf("HTTP route %d of VirtualService %s/%s is shadowed by an earlier catch-all route on listener %s:%d; Ignoring the route", ruleIdx, virtSvc.Namespace, virtSvc.Name, listenerID.HostName, listenerID.FrontendPort)
+	if len(paths) > 0 {
+		logLine = fmt.Sprintf("HTTP route %d of VirtualService %s/%s matches paths %s, which an earlier route on listener %s:%d matches already; Ignoring these paths", ruleIdx, virtSvc.Namespace, virtSvc.Name, strings.Join(paths, ", "), listenerID.HostName, listenerID.FrontendPort)
+	}
+	glog.Warning("[istio] ", logLine)
+	c.recorder.Event(virtSvc, v1.EventTypeWarning, events.ReasonShadowedRoute, logLine)
+}
+
+// reportUnsupportedIstioMatches reports the HTTP matches of the VirtualService, which App Gateway cannot express, with an
+// event on the VirtualService.
+func (c *appGwConfigBuilder) reportUnsupportedIstioMatches(virtSvc *v1alpha3.VirtualService) {
+	for ruleIdx := range virtSvc.Spec.HTTP {
+		for matchIdx := range virtSvc.Spec.HTTP[ruleIdx].Match {
+			if unsupported := getUnsupportedIstioMatchFields(&virtSvc.Spec.HTTP[ruleIdx].Match[matchIdx]); len(unsupported) > 0 {
+				logLine := fmt.Sprintf("VirtualService %s/%s has an HTTP match on %s, which App Gateway does not support; Ignoring the match", virtSvc.Namespace, virtSvc.Name, strings.Join(unsupported, ", "))
+				glog.Warning("[istio] ", logLine)
+				c.recorder.Event(virtSvc, v1.EventTypeWarning, events.ReasonUnsupportedMatch, logLine)
+			}
+		}
+	}
+}
+
+// reportUnsupportedIstioRouteFeatures reports what the HTTP routes of the VirtualService ask for, which App Gateway cannot
+// do, with an event on the VirtualService.
+func (c *appGwConfigBuilder) reportUnsupportedIstioRouteFeatures(virtSvc *v1alpha3.VirtualService) {
+	for ruleIdx := range virtSvc.Spec.HTTP {
+		if unsupported := getUnsupportedIstioRouteFields(virtSvc, &virtSvc.Spec.HTTP[ruleIdx]); len(unsupported) > 0 {
+			logLine := fmt.Sprintf("VirtualService %s/%s has an HTTP route with %s, which App Gateway does not support; Ignoring these", virtSvc.Namespace, virtSvc.Name, strings.Join(unsupported, ", "))
+			glog.Warning("[istio] ", logLine)
+			c.recorder.Event(virtSvc, v1.EventTypeWarning, events.ReasonUnsupportedRouteFeature, logLine)
+		}
+	}
+}
+
+// getUnsupportedIstioRouteFields lists the fields of the HTTP route App Gateway cannot express.
+func getUnsupportedIstioRouteFields(virtSvc *v1alpha3.VirtualService, rule *v1alpha3.HTTPRoute) []string {
+	var unsupported []string
+	if rule.Redirect != nil && rule.Redirect.Authority == "" {
+		for _, host := range virtSvc.Spec.Hosts {
+			if host == "*" {
+				// App Gateway redirects to a fixed URL; The listener for any host has none to redirect to.
+				unsupported = append(unsupported, "a redirect without authority for host *")
+				break
+			}
+		}
+	}
+	if rule.Timeout != "" {
+		if _, err := time.ParseDuration(rule.Timeout); err != nil {
+			unsupported = append(unsupported, fmt.Sprintf("timeout %q", rule.Timeout))
+		}
+	}
+	if rule.Headers != nil {
+		if rule.Headers.Request != nil && len(rule.Headers.Request.Add) > 0 {
+			unsupported = append(unsupported, "headers.request.add")
+		}
+		if rule.Headers.Response != nil && len(rule.Headers.Response.Add) > 0 {
+			unsupported = append(unsupported, "headers.response.add")
+		}
+	}
+	if len(rule.DeprecatedAppendHeaders) > 0 {
+		unsupported = append(unsupported, "appendHeaders")
+	}
+	if rule.Retries != nil {
+		unsupported = append(unsupported, "retries")
+	}
+	if rule.Fault != nil {
+		unsupported = append(unsupported, "fault")
+	}
+	if rule.Mirror != nil {
+		unsupported = append(unsupported, "mirror")
+	}
+	if rule.CorsPolicy != nil {
+		unsupported = append(unsupported, "corsPolicy")
+	}
+	return unsupported
+}
+
+// getIstioPaths translates the matches of the HTTP route, which apply to any of the given Gateways, into App Gateway
+// path patterns. The route is a catch-all, when it has no matches or one of its matches applies to every path. Matches
+// App Gateway cannot express are left out.
+func getIstioPaths(virtSvc *v1alpha3.VirtualService, rule *v1alpha3.HTTPRoute, gateways []*v1alpha3.Gateway) ([]string, bool) {
+	if len(rule.Match) == 0 {
+		return nil, true
+	}
+
+	var paths []string
+	for matchIdx := range rule.Match {
+		match := &rule.Match[matchIdx]
+		if len(getUnsupportedIstioMatchFields(match)) > 0 {
+			continue
+		}
+		if len(match.Gateways) > 0 && !isAnyIstioGatewayReferenced(match.Gateways, virtSvc.Namespace, gateways) {
+			// The gateways of a match override the ones of the VirtualService.
+			continue
+		}
+
+		if match.URI == nil {
+			return nil, true
+		}
+
+		switch {
+		case match.URI.Exact != "":
+			paths = appendUniquePaths(paths, match.URI.Exact)
+		case match.URI.Prefix == "/":
+			return nil, true
+		case strings.HasSuffix(match.URI.Prefix, "/"):
+			// App Gateway allows the wildcard only at the end of a path, following a slash.
+			paths = appendUniquePaths(paths, match.URI.Prefix+"*")
+		default:
+			paths = appendUniquePaths(paths, match.URI.Prefix, match.URI.Prefix+"/*")
+		}
+	}
+	return paths, false
+}
+
+func isAnyIstioGatewayReferenced(references []string, namespace string, gateways []*v1alpha3.Gateway) bool {
+	for _, gateway := range gateways {
+		if k8scontext.IsIstioGatewayReferenced(references, namespace, gateway) {
+			return true
+		}
+	}
+	return false
+}
+
+// getUnsupportedIstioMatchFields lists the fields of the HTTP match App Gateway cannot express.
+func getUnsupportedIstioMatchFields(match *v1alpha3.HTTPMatchRequest) []string {
+	var unsupported []string
+	if match.URI != nil {
+		if match.URI.Regex != "" {
+			unsupported = append(unsupported, "uri.regex")
+		}
+		if match.URI.Suffix != "" {
+			unsupported = append(unsupported, "uri.suffix")
+		}
+		if match.URI.Exact != "" && !strings.HasPrefix(match.URI.Exact, "/") {
+			unsupported = append(unsupported, "uri.exact not starting with /")
+		}
+		if match.URI.Prefix != "" && !strings.HasPrefix(match.URI.Prefix, "/") {
+			unsupported = append(unsupported, "uri.prefix not starting with /")
+		}
+		if match.URI.Exact == "" && match.URI.Prefix == "" && match.URI.Regex == "" && match.URI.Suffix == "" {
+			unsupported = append(unsupported, "an empty uri")
+		}
+	}
+	if len(match.Headers) > 0 {
+		unsupported = append(unsupported, "headers")
+	}
+	if match.Method != nil {
+		unsupported = append(unsupported, "method")
+	}
+	if match.Scheme != nil {
+		unsupported = append(unsupported, "scheme")
+	}
+	if match.Authority != nil {
+		unsupported = append(unsupported, "authority")
+	}
+	if match.Port != 0 {
+		unsupported = append(unsupported, "port")
+	}
+	return unsupported
+}
+
+// getUnclaimedPaths splits the paths into the ones no earlier route claimed and the ones an earlier route claimed.
+func getUnclaimedPaths(paths []string, claimed map[string]interface{}) ([]string, []string) {
+	var unclaimed, shadowed []string
+	for _, path := range paths {
+		if _, exists := claimed[path]; exists {
+			shadowed = append(shadowed, path)
+		} else {
+			unclaimed = append(unclaimed, path)
+		}
+	}
+	return unclaimed, shadowed
+}
+
+func appendUniquePaths(paths []string, pathsToAppend ...string) []string {
+	for _, path := range pathsToAppend {
+		exists := false
+		for _, existing := range paths {
+			if existing == path {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// getIstioPrimaryDestination picks the destination of the HTTP route receiving the largest share of the traffic; The
+// first one among equals. App Gateway routes the paths of a rule to a single backend pool, with the HTTP settings of this
+// destination; Weighted destinations share the pool.
+func getIstioPrimaryDestination(rule *v1alpha3.HTTPRoute) *v1alpha3.Destination {
+	var primary *v1alpha3.HTTPRouteDestination
+	for idx := range rule.Route {
+		if primary == nil || rule.Route[idx].Weight > primary.Weight {
+			primary = &rule.Route[idx]
+		}
+	}
+	if primary == nil {
+		return nil
+	}
+	return &primary.Destination
+}
@@ -0,0 +1,361 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"strings"
+
+	"github.com/knative/pkg/apis/istio/common/v1alpha1"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Istio HTTP route matches", func() {
+	listener80 := listenerIdentifier{FrontendPort: 80, HostName: tests.Host}
+	destination := v1alpha3.HTTPRouteDestination{
+		Destination: v1alpha3.Destination{
+			Host: tests.ServiceName,
+			Port: v1alpha3.PortSelector{Number: 80},
+		},
+	}
+
+	gateway := &v1alpha3.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: tests.Namespace,
+		},
+		Spec: v1alpha3.GatewaySpec{
+			Servers: []v1alpha3.Server{
+				{
+					Port:  v1alpha3.Port{Number: 80, Protocol: v1alpha3.ProtocolHTTP},
+					Hosts: []string{tests.Host},
+				},
+			},
+		},
+	}
+
+	newVirtualService := func(routes ...v1alpha3.HTTPRoute) *v1alpha3.VirtualService {
+		return &v1alpha3.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "virtual-service",
+				Namespace: tests.Namespace,
+			},
+			Spec: v1alpha3.VirtualServiceSpec{
+				Hosts:    []string{tests.Host},
+				Gateways: []string{"gateway"},
+				HTTP:     routes,
+			},
+		}
+	}
+
+	var cb appGwConfigBuilder
+	BeforeEach(func() {
+		cb = newConfigBuilderFixture(nil)
+		_ = cb.k8sContext.Caches.Endpoints.Add(tests.NewEndpointsFixture())
+		_ = cb.k8sContext.Caches.Service.Add(tests.NewServiceFixture(*tests.NewServicePortsFixture()...))
+	})
+
+	getPathMap := func(virtualService *v1alpha3.VirtualService) *[]string {
+		cbCtx := &ConfigBuilderContext{
+			IstioGateways:          []*v1alpha3.Gateway{gateway},
+			IstioVirtualServices:   []*v1alpha3.VirtualService{virtualService},
+			EnableIstioIntegration: true,
+		}
+		pathMaps := cb.getIstioPathMaps(cbCtx)
+		Expect(pathMaps).To(HaveLen(1))
+		Expect(pathMaps).To(HaveKey(listener80))
+		var paths []string
+		for _, pathRule := range *pathMaps[listener80].PathRules {
+			paths = append(paths, *pathRule.Paths...)
+		}
+		return &paths
+	}
+
+	Context("with several routes and matches", func() {
+		It("merges the path rules of every match into the path map of the listener", func() {
+			virtualService := newVirtualService(
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{
+						{URI: &v1alpha1.StringMatch{Prefix: tests.URLPath1}},
+						{URI: &v1alpha1.StringMatch{Exact: tests.URLPath2}},
+					},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{
+						{URI: &v1alpha1.StringMatch{Prefix: tests.URLPath3 + "/"}},
+					},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+			)
+			Expect(*getPathMap(virtualService)).To(Equal([]string{
+				tests.URLPath1,
+				tests.URLPath1 + "/*",
+				tests.URLPath2,
+				tests.URLPath3 + "/*",
+			}))
+		})
+
+		It("routes requests matching no path to the destination of a catch-all route", func() {
+			virtualService := newVirtualService(
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{
+						{URI: &v1alpha1.StringMatch{Prefix: tests.URLPath1}},
+					},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+				v1alpha3.HTTPRoute{
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+			)
+			cbCtx := &ConfigBuilderContext{
+				IstioGateways:          []*v1alpha3.Gateway{gateway},
+				IstioVirtualServices:   []*v1alpha3.VirtualService{virtualService},
+				EnableIstioIntegration: true,
+			}
+			pathMap := cb.getIstioPathMaps(cbCtx)[listener80]
+			Expect(*pathMap.PathRules).To(HaveLen(1))
+			Expect(*pathMap.DefaultBackendAddressPool.ID).To(Equal(*(*pathMap.PathRules)[0].BackendAddressPool.ID))
+			Expect(*pathMap.DefaultBackendHTTPSettings.ID).To(Equal(*(*pathMap.PathRules)[0].BackendHTTPSettings.ID))
+		})
+	})
+
+	Context("with routes shadowed by earlier routes", func() {
+		getShadowedRouteEvents := func() []string {
+			var shadowed []string
+			recorder := cb.recorder.(*record.FakeRecorder)
+			for len(recorder.Events) > 0 {
+				if event := <-recorder.Events; strings.Contains(event, events.ReasonShadowedRoute) {
+					shadowed = append(shadowed, event)
+				}
+			}
+			return shadowed
+		}
+
+		It("ignores the routes following a catch-all route", func() {
+			virtualService := newVirtualService(
+				v1alpha3.HTTPRoute{
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{
+						{URI: &v1alpha1.StringMatch{Prefix: tests.URLPath1}},
+					},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+			)
+			Expect(*getPathMap(virtualService)).To(BeEmpty())
+
+			shadowed := getShadowedRouteEvents()
+			Expect(shadowed).To(HaveLen(1))
+			Expect(shadowed[0]).To(ContainSubstring("HTTP route 1 of VirtualService " + tests.Namespace + "/virtual-service is shadowed by an earlier catch-all route"))
+		})
+
+		It("ignores the paths an earlier route matches already", func() {
+			virtualService := newVirtualService(
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{
+						{URI: &v1alpha1.StringMatch{Prefix: tests.URLPath1}},
+					},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{
+						{URI: &v1alpha1.StringMatch{Exact: tests.URLPath1}},
+						{URI: &v1alpha1.StringMatch{Exact: tests.URLPath2}},
+					},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{
+						{URI: &v1alpha1.StringMatch{Exact: tests.URLPath2}},
+					},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+			)
+			Expect(*getPathMap(virtualService)).To(Equal([]string{
+				tests.URLPath1,
+				tests.URLPath1 + "/*",
+				tests.URLPath2,
+			}))
+
+			shadowed := getShadowedRouteEvents()
+			Expect(shadowed).To(HaveLen(2))
+			Expect(shadowed[0]).To(ContainSubstring("HTTP route 1 of VirtualService " + tests.Namespace + "/virtual-service matches paths " + tests.URLPath1 + ","))
+			Expect(shadowed[1]).To(ContainSubstring("HTTP route 2 of VirtualService " + tests.Namespace + "/virtual-service matches paths " + tests.URLPath2 + ","))
+		})
+
+		It("ignores the routes of later VirtualServices on the same listener", func() {
+			catchAll := newVirtualService(v1alpha3.HTTPRoute{
+				Route: []v1alpha3.HTTPRouteDestination{destination},
+			})
+			catchAll.Name = "catch-all"
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Match: []v1alpha3.HTTPMatchRequest{
+					{URI: &v1alpha1.StringMatch{Exact: tests.URLPath1}},
+				},
+				Route: []v1alpha3.HTTPRouteDestination{destination},
+			})
+			cbCtx := &ConfigBuilderContext{
+				IstioGateways:          []*v1alpha3.Gateway{gateway},
+				IstioVirtualServices:   []*v1alpha3.VirtualService{catchAll, virtualService},
+				EnableIstioIntegration: true,
+			}
+
+			pathMap := cb.getIstioPathMaps(cbCtx)[listener80]
+			Expect(*pathMap.PathRules).To(BeEmpty())
+			Expect(pathMap.DefaultBackendAddressPool).ToNot(BeNil())
+
+			shadowed := getShadowedRouteEvents()
+			Expect(shadowed).To(HaveLen(1))
+			Expect(shadowed[0]).To(ContainSubstring("VirtualService " + tests.Namespace + "/virtual-service"))
+		})
+
+		It("keeps the path rules preceding a catch-all route", func() {
+			virtualService := newVirtualService(
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{
+						{URI: &v1alpha1.StringMatch{Exact: tests.URLPath1}},
+					},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+				v1alpha3.HTTPRoute{
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+			)
+			Expect(*getPathMap(virtualService)).To(Equal([]string{tests.URLPath1}))
+			Expect(getShadowedRouteEvents()).To(BeEmpty())
+		})
+	})
+
+	Context("with matches App Gateway cannot express", func() {
+		It("ignores them and reports them with an event", func() {
+			virtualService := newVirtualService(
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{
+						{URI: &v1alpha1.StringMatch{Regex: "/api[0-9]+"}},
+						{URI: &v1alpha1.StringMatch{Prefix: tests.URLPath1}, Headers: map[string]v1alpha1.StringMatch{"x-canary": {Exact: "true"}}},
+						{URI: &v1alpha1.StringMatch{Prefix: tests.URLPath2}, Method: &v1alpha1.StringMatch{Exact: "GET"}},
+						{URI: &v1alpha1.StringMatch{Exact: tests.URLPath3}},
+					},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+			)
+			Expect(*getPathMap(virtualService)).To(Equal([]string{tests.URLPath3}))
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(3))
+			Expect(<-recorder.Events).To(ContainSubstring("uri.regex"))
+			Expect(<-recorder.Events).To(ContainSubstring("headers"))
+			Expect(<-recorder.Events).To(ContainSubstring("method"))
+		})
+	})
+
+	Context("with several hosts, servers and destinations", func() {
+		It("creates a listener for every host of the VirtualService a server of a bound Gateway accepts", func() {
+			multiServerGateway := gateway.DeepCopy()
+			multiServerGateway.Spec.Servers = append(multiServerGateway.Spec.Servers, v1alpha3.Server{
+				Port:  v1alpha3.Port{Number: 8080, Protocol: v1alpha3.ProtocolHTTP},
+				Hosts: []string{"*.contoso.com"},
+			})
+			virtualService := newVirtualService()
+			virtualService.Spec.Hosts = []string{tests.Host, "www.contoso.com", "www.fabrikam.com"}
+			unboundVirtualService := newVirtualService()
+			unboundVirtualService.Name = "unbound"
+			unboundVirtualService.Spec.Hosts = []string{tests.OtherHost}
+			unboundVirtualService.Spec.Gateways = []string{"some-other-gateway"}
+
+			configs := cb.getListenerConfigsFromIstio([]*v1alpha3.Gateway{multiServerGateway}, []*v1alpha3.VirtualService{virtualService, unboundVirtualService})
+			Expect(getMapKeys(&configs)).To(ConsistOf(
+				listener80,
+				listenerIdentifier{FrontendPort: 8080, HostName: "www.contoso.com"},
+			))
+		})
+
+		It("gives each destination its own settings and routes to the one with the largest weight", func() {
+			canary := v1alpha3.HTTPRouteDestination{
+				Destination: v1alpha3.Destination{
+					Host: tests.ServiceName,
+					Port: v1alpha3.PortSelector{Number: 80},
+				},
+				Weight: 20,
+			}
+			stable := v1alpha3.HTTPRouteDestination{
+				Destination: v1alpha3.Destination{
+					Host: tests.ServiceName + "." + tests.Namespace + ".svc.cluster.local",
+					Port: v1alpha3.PortSelector{Name: tests.ServiceHTTPPort},
+				},
+				Weight: 80,
+			}
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Match: []v1alpha3.HTTPMatchRequest{
+					{URI: &v1alpha1.StringMatch{Prefix: tests.URLPath1}},
+				},
+				Route: []v1alpha3.HTTPRouteDestination{canary, stable},
+			})
+			cbCtx := &ConfigBuilderContext{
+				IstioGateways:          []*v1alpha3.Gateway{gateway},
+				IstioVirtualServices:   []*v1alpha3.VirtualService{virtualService},
+				EnableIstioIntegration: true,
+			}
+
+			_, settingsByDestination, _, err := cb.getIstioDestinationsAndSettingsMap(cbCtx)
+			Expect(err).ToNot(HaveOccurred())
+			canaryID := generateIstioDestinationID(virtualService, &virtualService.Spec.HTTP[0], &canary.Destination)
+			stableID := generateIstioDestinationID(virtualService, &virtualService.Spec.HTTP[0], &stable.Destination)
+			Expect(stableID.serviceKey()).To(Equal(canaryID.serviceKey()))
+			Expect(settingsByDestination).To(HaveKey(canaryID))
+			Expect(settingsByDestination).To(HaveKey(stableID))
+			Expect(*settingsByDestination[stableID].Name).ToNot(Equal(*settingsByDestination[canaryID].Name))
+
+			pathRules := *cb.getIstioPathMaps(cbCtx)[listener80].PathRules
+			Expect(pathRules).To(HaveLen(1))
+			Expect(*pathRules[0].BackendHTTPSettings.ID).To(Equal(*settingsByDestination[stableID].ID))
+		})
+	})
+
+	Context("with matches restricted to some of the Gateways", func() {
+		It("applies the matches to the listeners of these Gateways only", func() {
+			otherGateway := gateway.DeepCopy()
+			otherGateway.Name = "other-gateway"
+			otherGateway.Spec.Servers[0].Port.Number = 8080
+			listener8080 := listenerIdentifier{FrontendPort: 8080, HostName: tests.Host}
+
+			virtualService := newVirtualService(
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{
+						{URI: &v1alpha1.StringMatch{Exact: tests.URLPath1}, Gateways: []string{tests.Namespace + "/other-gateway"}},
+					},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{
+						{URI: &v1alpha1.StringMatch{Exact: tests.URLPath2}},
+					},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+			)
+			virtualService.Spec.Gateways = []string{"gateway", "other-gateway"}
+			cbCtx := &ConfigBuilderContext{
+				IstioGateways:          []*v1alpha3.Gateway{gateway, otherGateway},
+				IstioVirtualServices:   []*v1alpha3.VirtualService{virtualService},
+				EnableIstioIntegration: true,
+			}
+
+			pathMaps := cb.getIstioPathMaps(cbCtx)
+			Expect(pathMaps).To(HaveLen(2))
+			Expect(*pathMaps[listener80].PathRules).To(HaveLen(1))
+			Expect(*(*pathMaps[listener80].PathRules)[0].Paths).To(Equal([]string{tests.URLPath2}))
+			Expect(*pathMaps[listener8080].PathRules).To(HaveLen(2))
+		})
+	})
+})
@@ -8,6 +8,7 @@ package appgw
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -25,12 +26,10 @@ func istioMatchDestinationIds(cbCtx *ConfigBuilderContext) ([]istioMatchIdentifi
 			destinations := make([]*v1alpha3.Destination, 0)
 			for _, routeDestination := range rule.Route {
 				if routeDestination.Weight != 0 {
+					// The route splits its requests by weight through a pool of its own; See getIstioWeightedPool.
 					destinations = append(destinations, &routeDestination.Destination)
-					/* TODO(rhea): Weights are being ignored for now, since this is not
-					yet supported on App Gateway. Include gates from routeDestination when
-					this is supported */
 				}
-				destinationID := generateIstioDestinationID(virtualService, &routeDestination.Destination)
+				destinationID := generateIstioDestinationID(virtualService, &rule, &routeDestination.Destination)
 				destinationIDs[destinationID] = nil
 			}
 			for _, match := range rule.Match {
@@ -78,8 +77,7 @@ func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBui
 					continue
 				}
 
-				// TODO(delqn): implement correctly port lookup by name
-				if sp.Port == destinationPortNum || sp.TargetPort.String() == fmt.Sprint(destinationPortNum) {
+				if isIstioDestinationPort(destinationID, service, sp) {
 					// matched a service port with a port from the service
 					if sp.TargetPort.String() == "" {
 						// targetPort is not defined, by default targetPort == port
@@ -121,7 +119,7 @@ func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBui
 			//TODO(rhea): Add error event
 
 			unresolvedDestinationID = append(unresolvedDestinationID, destinationID)
-			break
+			continue
 		}
 
 		// Merge serviceBackendPairsMap[backendID] into resolvedBackendPorts
@@ -132,25 +130,23 @@ func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBui
 			serviceBackendPairsMap[destinationID][portPair] = nil
 		}
 	}
+
+	// Destinations, which could not be resolved, are left out; These must not take the destinations of other routes with them.
+	var err error
 	if len(unresolvedDestinationID) > 0 {
-		return nil, nil, nil, errors.New("unable to resolve backend port for some services")
+		err = errors.New("unable to resolve backend port for some services")
 	}
 
 	httpSettingsCollection := make(map[string]n.ApplicationGatewayBackendHTTPSettings)
 	for destinationID, serviceBackendPairs := range serviceBackendPairsMap {
 		if len(serviceBackendPairs) > 1 {
 			// more than one possible backend port exposed through ingress
-			backendServicePort := ""
-			if destinationID.DestinationPort != 0 {
-				backendServicePort = fmt.Sprint(destinationID.DestinationPort)
-			} else {
-				// TODO(delqn): implement port lookup by name
-			}
 			logLine := fmt.Sprintf("service:port [%s:%s] has more than one service-backend port binding",
-				destinationID.serviceKey(), backendServicePort)
+				destinationID.serviceKey(), destinationID.destinationPort())
 			glog.Warning(logLine)
 			//TODO(rhea): add error event recorder
-			return nil, nil, nil, errors.New("more than one service-backend port binding is not allowed")
+			err = errors.New("more than one service-backend port binding is not allowed")
+			continue
 		}
 
 		// At this point there will be only one pair
@@ -170,17 +166,23 @@ func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBui
 		httpSettings = append(httpSettings, backend)
 	}
 
-	return httpSettings, backendHTTPSettingsMap, finalServiceBackendPairMap, nil
+	return httpSettings, backendHTTPSettingsMap, finalServiceBackendPairMap, err
 }
 
-func (c *appGwConfigBuilder) generateIstioHTTPSettings(destinationID istioDestinationIdentifier, port int32, cbCtx *ConfigBuilderContext) n.ApplicationGatewayBackendHTTPSettings {
-	backendServicePort := ""
-	if destinationID.DestinationPort != 0 {
-		backendServicePort = fmt.Sprint(destinationID.DestinationPort)
-	} else {
-		// TODO(delqn): Implement port lookup by name
+// isIstioDestinationPort figures out whether the destination refers to the given port of the Service: by number, by
+// name, or implicitly when the destination has no port and the Service a single one.
+func isIstioDestinationPort(destinationID istioDestinationIdentifier, service *v1.Service, sp v1.ServicePort) bool {
+	switch {
+	case destinationID.DestinationPort != 0:
+		return sp.Port == int32(destinationID.DestinationPort) || sp.TargetPort.String() == fmt.Sprint(destinationID.DestinationPort)
+	case destinationID.DestinationPortName != "":
+		return sp.Name == destinationID.DestinationPortName
 	}
-	httpSettingsName := generateHTTPSettingsName(destinationID.serviceFullName(), backendServicePort, port, destinationID.istioVirtualServiceIdentifier.Name)
+	return len(service.Spec.Ports) == 1
+}
+
+func (c *appGwConfigBuilder) generateIstioHTTPSettings(destinationID istioDestinationIdentifier, port int32, cbCtx *ConfigBuilderContext) n.ApplicationGatewayBackendHTTPSettings {
+	httpSettingsName := generateHTTPSettingsName(destinationID.destinationName(), destinationID.destinationPort(), port, destinationID.settingsOwner())
 	glog.V(5).Infof("Created a new HTTP setting w/ name: %s\n", httpSettingsName)
 	httpSettings := n.ApplicationGatewayBackendHTTPSettings{
 		Etag: to.StringPtr("*"),
@@ -192,7 +194,28 @@ func (c *appGwConfigBuilder) generateIstioHTTPSettings(destinationID istioDestin
 		},
 	}
 
-	//TODO(rhea): check relevant annotations and modify http settings accordingly
+	applyIstioTrafficPolicy(&httpSettings, getIstioTrafficPolicy(cbCtx, destinationID))
+	applyIstioRouteSettings(&httpSettings, destinationID)
 
 	return httpSettings
 }
+
+// applyIstioRouteSettings carries over the settings of the HTTP route of the destination onto its HTTP settings: the
+// rewritten URI becomes the path, the rewritten authority the host name, and the timeout the request timeout.
+func applyIstioRouteSettings(httpSettings *n.ApplicationGatewayBackendHTTPSettings, destinationID istioDestinationIdentifier) {
+	if destinationID.RewriteURI != "" {
+		httpSettings.Path = to.StringPtr(destinationID.RewriteURI)
+	}
+
+	if destinationID.RewriteAuthority != "" {
+		httpSettings.HostName = to.StringPtr(destinationID.RewriteAuthority)
+	}
+
+	if destinationID.Timeout != "" {
+		if timeout, err := time.ParseDuration(destinationID.Timeout); err != nil {
+			glog.Warningf("Ignoring timeout %q of the HTTP route: %s", destinationID.Timeout, err)
+		} else {
+			httpSettings.RequestTimeout = to.Int32Ptr(toRequestTimeoutInSec(timeout))
+		}
+	}
+}
@@ -5,7 +5,13 @@
 
 package appgw
 
-import "github.com/knative/pkg/apis/istio/v1alpha3"
+import (
+	"crypto/md5"
+	"fmt"
+	"strings"
+
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+)
 
 type istioMatchIdentifier struct {
 	Namespace      string
@@ -25,7 +31,39 @@ type istioDestinationIdentifier struct {
 	serviceIdentifier
 	istioVirtualServiceIdentifier
 
-	DestinationHost   string
-	DestinationSubset string
-	DestinationPort   uint32
+	DestinationHost     string
+	DestinationSubset   string
+	DestinationPort     uint32
+	DestinationPortName string
+
+	// The settings of the HTTP route, which App Gateway applies through the HTTP settings of the destination.
+	RewriteURI       string
+	RewriteAuthority string
+	Timeout          string
+}
+
+// destinationPort is the port of the destination as the VirtualService gives it: its number, or else its name.
+func (d istioDestinationIdentifier) destinationPort() string {
+	if d.DestinationPort != 0 {
+		return fmt.Sprint(d.DestinationPort)
+	}
+	return d.DestinationPortName
+}
+
+// settingsOwner names what the HTTP settings of the destination are generated for: the VirtualService, along with the
+// settings of the HTTP route, which set the HTTP settings apart from those of the same destination in other routes.
+func (d istioDestinationIdentifier) settingsOwner() string {
+	if d.RewriteURI == "" && d.RewriteAuthority == "" && d.Timeout == "" {
+		return d.istioVirtualServiceIdentifier.Name
+	}
+	hash := md5.Sum([]byte(strings.Join([]string{d.RewriteURI, d.RewriteAuthority, d.Timeout}, "|")))
+	return fmt.Sprintf("%s-%x", d.istioVirtualServiceIdentifier.Name, hash[:4])
+}
+
+// destinationName names the Service of the destination, and its subset if it has one.
+func (d istioDestinationIdentifier) destinationName() string {
+	if d.DestinationSubset != "" {
+		return fmt.Sprintf("%s-%s", d.serviceFullName(), d.DestinationSubset)
+	}
+	return d.serviceFullName()
 }
@@ -0,0 +1,176 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"k8s.io/api/extensions/v1beta1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// Ownership lists the sub-resources AGIC generated for the App Gateway config, along with the Ingresses and Services they
+// were generated for. This must run after Build: generated sub-resources, which did not make it into the built config
+// (for instance because a prohibited sub-resource took their place), are left out. Sub-resources of the previous manifest
+// stay in the manifest while they are on App Gateway.
+func (c *appGwConfigBuilder) Ownership(cbCtx *ConfigBuilderContext) *ownership.Manifest {
+	built := getSubResourceNames(c.appGw)
+	manifest := ownership.NewManifest()
+	add := func(resourceType ownership.ResourceType, name string, ingress string, service string) {
+		if _, exists := built[resourceType][name]; exists {
+			manifest.Add(resourceType, name, ingress, service)
+		}
+	}
+
+	addListener := func(listenerID listenerIdentifier, config listenerAzConfig, ingress string) {
+		add(ownership.HTTPListeners, generateListenerName(listenerID), ingress, "")
+		add(ownership.FrontendPorts, generateFrontendPortName(listenerID.FrontendPort), ingress, "")
+		add(ownership.RequestRoutingRules, generateRequestRoutingRuleName(listenerID), ingress, "")
+		add(ownership.URLPathMaps, generateURLPathMapName(listenerID), ingress, "")
+		if config.SslRedirectConfigurationName != "" {
+			add(ownership.RedirectConfigurations, config.SslRedirectConfigurationName, ingress, "")
+		}
+		if config.Protocol == n.HTTPS {
+			add(ownership.SslCertificates, config.Secret.secretFullName(), ingress, "")
+		}
+	}
+
+	// The defaults AGIC creates regardless of Ingresses
+	add(ownership.BackendAddressPools, defaultBackendAddressPoolName, "", "")
+	add(ownership.BackendHTTPSettings, defaultBackendHTTPSettingsName, "", "")
+	add(ownership.Probes, defaultProbeName, "", "")
+	for listenerID, config := range c.getListenerConfigs(cbCtx) {
+		addListener(listenerID, config, "")
+	}
+
+	for _, ingress := range cbCtx.IngressList {
+		ingressKey := getIngressKey(ingress)
+		_, listenerConfigs := c.processIngressRules(ingress, cbCtx.EnvVariables)
+		for listenerID, config := range listenerConfigs {
+			addListener(listenerID, config, ingressKey)
+		}
+	}
+
+	_, settingsByBackend, _, _ := c.getBackendsAndSettingsMap(cbCtx)
+	poolsByBackend := c.newBackendPoolMap(cbCtx)
+	for backendID, settings := range settingsByBackend {
+		ingressKey := getIngressKey(backendID.Ingress)
+		serviceKey := backendID.serviceKey()
+		add(ownership.BackendHTTPSettings, *settings.Name, ingressKey, serviceKey)
+		if settings.Probe != nil && settings.Probe.ID != nil {
+			add(ownership.Probes, utils.GetLastChunkOfSlashed(*settings.Probe.ID), ingressKey, serviceKey)
+		}
+		if pool := poolsByBackend[backendID]; pool != nil && *pool.Name != defaultBackendAddressPoolName {
+			add(ownership.BackendAddressPools, *pool.Name, ingressKey, serviceKey)
+		}
+	}
+
+	if cbCtx.EnableIstioIntegration {
+		for listenerID, config := range c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices) {
+			addListener(listenerID, config, "")
+		}
+		istioSettings, _, _, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
+		for _, settings := range istioSettings {
+			add(ownership.BackendHTTPSettings, *settings.Name, "", "")
+		}
+		for _, pool := range c.newIstioBackendPoolMap(cbCtx) {
+			add(ownership.BackendAddressPools, *pool.Name, "", "")
+		}
+		for _, redirect := range c.getIstioRedirectConfigurations(cbCtx) {
+			add(ownership.RedirectConfigurations, *redirect.Name, "", "")
+		}
+		for _, ruleSet := range c.getIstioRewriteRuleSets(cbCtx) {
+			add(ownership.RewriteRuleSets, *ruleSet.Name, "", "")
+		}
+	}
+
+	// AGIC keeps owning what it created while it is on App Gateway, even when it no longer generates it; Like the
+	// sub-resources of a hostname prohibited since, which AGIC leaves alone until the prohibited target is deleted.
+	if cbCtx.Ownership != nil {
+		for _, resource := range cbCtx.Ownership.Resources {
+			add(resource.Type, resource.Name, "", "")
+		}
+	}
+
+	manifest.Sort()
+	return manifest
+}
+
+// getIngressKey identifies the Ingress in the ownership manifest.
+func getIngressKey(ingress *v1beta1.Ingress) string {
+	return fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name)
+}
+
+// getSubResourceNames indexes the names of the sub-resources of the given App Gateway config by their type.
+func getSubResourceNames(appGw n.ApplicationGateway) map[ownership.ResourceType]map[string]interface{} {
+	names := make(map[ownership.ResourceType]map[string]interface{})
+	add := func(resourceType ownership.ResourceType, name *string) {
+		if name == nil {
+			return
+		}
+		if names[resourceType] == nil {
+			names[resourceType] = make(map[string]interface{})
+		}
+		names[resourceType][*name] = nil
+	}
+	if appGw.ApplicationGatewayPropertiesFormat == nil {
+		return names
+	}
+	if appGw.BackendAddressPools != nil {
+		for _, pool := range *appGw.BackendAddressPools {
+			add(ownership.BackendAddressPools, pool.Name)
+		}
+	}
+	if appGw.BackendHTTPSettingsCollection != nil {
+		for _, settings := range *appGw.BackendHTTPSettingsCollection {
+			add(ownership.BackendHTTPSettings, settings.Name)
+		}
+	}
+	if appGw.FrontendPorts != nil {
+		for _, port := range *appGw.FrontendPorts {
+			add(ownership.FrontendPorts, port.Name)
+		}
+	}
+	if appGw.HTTPListeners != nil {
+		for _, listener := range *appGw.HTTPListeners {
+			add(ownership.HTTPListeners, listener.Name)
+		}
+	}
+	if appGw.Probes != nil {
+		for _, probe := range *appGw.Probes {
+			add(ownership.Probes, probe.Name)
+		}
+	}
+	if appGw.RedirectConfigurations != nil {
+		for _, redirect := range *appGw.RedirectConfigurations {
+			add(ownership.RedirectConfigurations, redirect.Name)
+		}
+	}
+	if appGw.RewriteRuleSets != nil {
+		for _, ruleSet := range *appGw.RewriteRuleSets {
+			add(ownership.RewriteRuleSets, ruleSet.Name)
+		}
+	}
+	if appGw.RequestRoutingRules != nil {
+		for _, rule := range *appGw.RequestRoutingRules {
+			add(ownership.RequestRoutingRules, rule.Name)
+		}
+	}
+	if appGw.SslCertificates != nil {
+		for _, cert := range *appGw.SslCertificates {
+			add(ownership.SslCertificates, cert.Name)
+		}
+	}
+	if appGw.URLPathMaps != nil {
+		for _, pathMap := range *appGw.URLPathMaps {
+			add(ownership.URLPathMaps, pathMap.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,119 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test the ownership manifest", func() {
+	Context("test Ownership() for an Ingress with TLS and ssl-redirect", func() {
+		certs := newCertsFixture()
+		configBuilder := newConfigBuilderFixture(&certs)
+		endpoint := tests.NewEndpointsFixture()
+		service := tests.NewServiceFixture(*tests.NewServicePortsFixture()...)
+		ingress := tests.NewIngressFixture()
+		_ = configBuilder.k8sContext.Caches.Endpoints.Add(endpoint)
+		_ = configBuilder.k8sContext.Caches.Service.Add(service)
+		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingress)
+
+		cbCtx := &ConfigBuilderContext{
+			IngressList:  []*v1beta1.Ingress{ingress},
+			ServiceList:  []*v1.Service{service},
+			EnvVariables: environment.GetFakeEnv(),
+		}
+		appGw, err := configBuilder.Build(cbCtx)
+		manifest := configBuilder.Ownership(cbCtx)
+
+		ingressKey := fmt.Sprintf("%s/%s", tests.Namespace, tests.Name)
+		serviceKey := fmt.Sprintf("%s/%s", tests.Namespace, tests.ServiceName)
+
+		It("should have built the config", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should attribute the listeners and what hangs off them to the Ingress", func() {
+			httpsListenerID := listenerIdentifier{FrontendPort: 443, HostName: tests.Host}
+			httpListenerID := listenerIdentifier{FrontendPort: 80, HostName: tests.Host}
+			for _, listenerID := range []listenerIdentifier{httpsListenerID, httpListenerID} {
+				Expect(manifest.Get(ownership.HTTPListeners, generateListenerName(listenerID)).Ingresses).To(Equal([]string{ingressKey}))
+				Expect(manifest.Get(ownership.RequestRoutingRules, generateRequestRoutingRuleName(listenerID)).Ingresses).To(Equal([]string{ingressKey}))
+				Expect(manifest.Get(ownership.FrontendPorts, generateFrontendPortName(listenerID.FrontendPort)).Ingresses).To(Equal([]string{ingressKey}))
+			}
+			Expect(manifest.Get(ownership.RedirectConfigurations, generateSSLRedirectConfigurationName(httpsListenerID)).Ingresses).To(Equal([]string{ingressKey}))
+			Expect(manifest.Get(ownership.SslCertificates, fmt.Sprintf("%s-%s", tests.Namespace, tests.NameOfSecret)).Ingresses).To(Equal([]string{ingressKey}))
+		})
+
+		It("should attribute the backends to the Ingress and the Service", func() {
+			for _, settings := range *appGw.BackendHTTPSettingsCollection {
+				resource := manifest.Get(ownership.BackendHTTPSettings, *settings.Name)
+				Expect(resource).ToNot(BeNil())
+				if *settings.Name == defaultBackendHTTPSettingsName {
+					continue
+				}
+				Expect(resource.Ingresses).To(Equal([]string{ingressKey}))
+				Expect(resource.Services).To(Equal([]string{serviceKey}))
+			}
+			for _, pool := range *appGw.BackendAddressPools {
+				Expect(manifest.Owns(ownership.BackendAddressPools, *pool.Name)).To(BeTrue())
+			}
+			for _, probe := range *appGw.Probes {
+				Expect(manifest.Owns(ownership.Probes, *probe.Name)).To(BeTrue())
+			}
+		})
+
+		It("should record exactly the sub-resources of the built config", func() {
+			Expect(manifest.Resources).To(HaveLen(len(*appGw.HTTPListeners) + len(*appGw.RequestRoutingRules) + len(*appGw.URLPathMaps) +
+				len(*appGw.FrontendPorts) + len(*appGw.RedirectConfigurations) + len(*appGw.SslCertificates) +
+				len(*appGw.BackendAddressPools) + len(*appGw.BackendHTTPSettingsCollection) + len(*appGw.Probes)))
+		})
+	})
+
+	Context("test Ownership() leaves out sub-resources, which did not make it into the config", func() {
+		configBuilder := newConfigBuilderFixture(nil)
+		configBuilder.appGw.HTTPListeners = &[]n.ApplicationGatewayHTTPListener{}
+		manifest := configBuilder.Ownership(&ConfigBuilderContext{EnvVariables: environment.GetFakeEnv()})
+
+		It("should not own the default listener", func() {
+			Expect(manifest.Owns(ownership.HTTPListeners, generateListenerName(defaultFrontendListenerIdentifier()))).To(BeFalse())
+		})
+	})
+
+	Context("test Ownership() keeps the sub-resources of the previous manifest, which are still on App Gateway", func() {
+		configBuilder := newConfigBuilderFixture(nil)
+		configBuilder.appGw.BackendAddressPools = &[]n.ApplicationGatewayBackendAddressPool{
+			{Name: to.StringPtr("previously-generated")},
+			{Name: to.StringPtr("foreign")},
+		}
+		previous := ownership.NewManifest()
+		previous.Add(ownership.BackendAddressPools, "previously-generated", tests.Namespace+"/"+tests.Name, "")
+		previous.Add(ownership.BackendAddressPools, "deleted", "", "")
+		manifest := configBuilder.Ownership(&ConfigBuilderContext{EnvVariables: environment.GetFakeEnv(), Ownership: previous})
+
+		It("should keep owning what AGIC no longer generates", func() {
+			Expect(manifest.Owns(ownership.BackendAddressPools, "previously-generated")).To(BeTrue())
+		})
+
+		It("should not take over the sub-resources of others", func() {
+			Expect(manifest.Owns(ownership.BackendAddressPools, "foreign")).To(BeFalse())
+		})
+
+		It("should forget the sub-resources, which are gone", func() {
+			Expect(manifest.Owns(ownership.BackendAddressPools, "deleted")).To(BeFalse())
+		})
+	})
+})
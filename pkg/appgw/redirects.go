@@ -20,8 +20,18 @@ import (
 func (c *appGwConfigBuilder) getRedirectConfigurations(cbCtx *ConfigBuilderContext) *[]n.ApplicationGatewayRedirectConfiguration {
 	var redirectConfigs []n.ApplicationGatewayRedirectConfiguration
 
-	// Iterate over all possible Listeners (generated from the K8s Ingress configurations)
-	for listenerID, listenerConfig := range c.getListenerConfigs(cbCtx) {
+	listenerConfigs := c.getListenerConfigs(cbCtx)
+	if cbCtx.EnableIstioIntegration {
+		// HTTPS listeners of Istio Gateway servers, which HTTP servers with TLS.HTTPSRedirect redirect to.
+		for listenerID, listenerConfig := range c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices) {
+			if _, exists := listenerConfigs[listenerID]; !exists {
+				listenerConfigs[listenerID] = listenerConfig
+			}
+		}
+	}
+
+	// Iterate over all possible Listeners (generated from the K8s Ingress and Istio Gateway configurations)
+	for listenerID, listenerConfig := range listenerConfigs {
 		isHTTPS := listenerConfig.Protocol == n.HTTPS
 		// What if multiple namespaces have a redirect configured?
 		hasSslRedirect := listenerConfig.SslRedirectConfigurationName != ""
@@ -34,8 +44,13 @@ func (c *appGwConfigBuilder) getRedirectConfigurations(cbCtx *ConfigBuilderConte
 		}
 	}
 
+	if cbCtx.EnableIstioIntegration {
+		// HTTP routes of Istio VirtualServices, which redirect requests.
+		redirectConfigs = append(redirectConfigs, c.getIstioRedirectConfigurations(cbCtx)...)
+	}
+
 	if cbCtx.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		er := c.newExistingResources(cbCtx, nil)
 
 		// Listeners we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedRedirects()
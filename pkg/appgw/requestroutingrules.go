@@ -20,10 +20,13 @@ import (
 )
 
 func (c *appGwConfigBuilder) RequestRoutingRules(cbCtx *ConfigBuilderContext) error {
+	// Rewrite rule sets are attached to the path rules and request routing rules created below.
+	c.appGw.RewriteRuleSets = c.getRewriteRuleSets(cbCtx)
+
 	requestRoutingRules, pathMaps := c.getRules(cbCtx)
 
 	if cbCtx.EnableBrownfieldDeployment {
-		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		rCtx := c.newExistingResources(cbCtx, nil)
 		{
 			// PathMaps we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 			existingBlacklisted, existingNonBlacklisted := rCtx.GetBlacklistedPathMaps()
@@ -40,7 +43,7 @@ func (c *appGwConfigBuilder) RequestRoutingRules(cbCtx *ConfigBuilderContext) er
 	c.appGw.URLPathMaps = &pathMaps
 
 	if cbCtx.EnableBrownfieldDeployment {
-		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		rCtx := c.newExistingResources(cbCtx, nil)
 		{
 			// RoutingRules we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 			existingBlacklisted, existingNonBlacklisted := rCtx.GetBlacklistedRoutingRules()
@@ -84,6 +87,7 @@ func (c *appGwConfigBuilder) getRules(cbCtx *ConfigBuilderContext) ([]n.Applicat
 			if rule.RedirectConfiguration == nil {
 				rule.BackendAddressPool = urlPathMap.DefaultBackendAddressPool
 				rule.BackendHTTPSettings = urlPathMap.DefaultBackendHTTPSettings
+				rule.RewriteRuleSet = urlPathMap.DefaultRewriteRuleSet
 			}
 		} else {
 			// Path-based Rule
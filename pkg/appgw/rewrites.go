@@ -0,0 +1,54 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"sort"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/golang/glog"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
+)
+
+// getRewriteRuleSets creates the App Gateway rewrite rule sets for the Istio HTTP routes, which rewrite headers. Rewrite
+// rule sets someone else created are kept: AGIC replaces only the ones it generates, and drops the ones the ownership
+// manifest says it created before.
+func (c *appGwConfigBuilder) getRewriteRuleSets(cbCtx *ConfigBuilderContext) *[]n.ApplicationGatewayRewriteRuleSet {
+	var ruleSets []n.ApplicationGatewayRewriteRuleSet
+	if cbCtx.EnableIstioIntegration {
+		ruleSets = c.getIstioRewriteRuleSets(cbCtx)
+	}
+
+	if len(ruleSets) == 0 && c.appGw.RewriteRuleSets == nil {
+		return nil
+	}
+
+	generated := make(map[string]interface{})
+	for _, ruleSet := range ruleSets {
+		generated[*ruleSet.Name] = nil
+	}
+
+	if c.appGw.RewriteRuleSets != nil {
+		for _, ruleSet := range *c.appGw.RewriteRuleSets {
+			if ruleSet.Name == nil {
+				continue
+			}
+			if _, exists := generated[*ruleSet.Name]; exists {
+				continue
+			}
+			if cbCtx.Ownership != nil && cbCtx.Ownership.Owns(ownership.RewriteRuleSets, *ruleSet.Name) {
+				glog.V(5).Infof("Removing rewrite rule set %s, which AGIC created and no longer generates", *ruleSet.Name)
+				continue
+			}
+			ruleSets = append(ruleSets, ruleSet)
+		}
+	}
+
+	sort.Sort(sorter.ByRewriteRuleSetName(ruleSets))
+	return &ruleSets
+}
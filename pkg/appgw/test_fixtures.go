@@ -94,11 +94,13 @@ func newConfigBuilderFixture(certs *map[string]interface{}) appGwConfigBuilder {
 		appGw: n.ApplicationGateway{ApplicationGatewayPropertiesFormat: &appGwConfig},
 		k8sContext: &k8scontext.Context{
 			Caches: &k8scontext.CacheCollection{
-				Endpoints: cache.NewStore(keyFunc),
-				Secret:    cache.NewStore(keyFunc),
-				Service:   cache.NewStore(keyFunc),
-				Pods:      cache.NewStore(keyFunc),
-				Ingress:   cache.NewStore(keyFunc),
+				Endpoints:  cache.NewStore(keyFunc),
+				Secret:     cache.NewStore(keyFunc),
+				Service:    cache.NewStore(keyFunc),
+				Pods:       cache.NewStore(keyFunc),
+				Ingress:    cache.NewStore(keyFunc),
+				Namespaces: cache.NewStore(keyFunc),
+				Nodes:      cache.NewStore(cache.MetaNamespaceKeyFunc),
 			},
 			CertificateSecretStore: newSecretStoreFixture(certs),
 		},
@@ -11,7 +11,10 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/gatewayapi"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 )
 
 // ConfigBuilderContext holds the structs we have fetches from Kubernetes + environment, based on which
@@ -20,10 +23,23 @@ type ConfigBuilderContext struct {
 	IngressList          []*v1beta1.Ingress
 	ServiceList          []*v1.Service
 	ProhibitedTargets    []*ptv1.AzureIngressProhibitedTarget
+	ManagedTargets       []*mtv1.AzureIngressManagedTarget
 	EnvVariables         environment.EnvVariables
 	IstioGateways        []*v1alpha3.Gateway
 	IstioVirtualServices []*v1alpha3.VirtualService
 
+	// IstioDestinationRules set the traffic policies and subsets of the destinations of the Istio VirtualServices.
+	IstioDestinationRules []*v1alpha3.DestinationRule
+
+	// HostnameGrants restricts the hostnames the Ingresses of a namespace may use.
+	HostnameGrants HostnameGrants
+
+	// GatewayAPI tells which Ingresses of the IngressList were translated from HTTPRoutes; nil when the Gateway API is not enabled.
+	GatewayAPI *gatewayapi.Translation
+
+	// Ownership lists the App Gateway sub-resources AGIC created; nil when AGIC did not record them yet.
+	Ownership *ownership.Manifest
+
 	// Feature flag toggling Brownfield Deployment across the entire AGIC code base.
 	EnableBrownfieldDeployment bool
 
@@ -32,6 +48,12 @@ type ConfigBuilderContext struct {
 
 	// Feature flag enabling panic() when put to ARM fails.
 	EnablePanicOnPutError bool
+
+	// Feature flag enabling PUT to ARM with the ETag of the App Gateway config AGIC started from.
+	EnableOptimisticConcurrency bool
+
+	// Feature flag enabling re-applying the last known good App Gateway config when a deployment fails.
+	EnableRollbackOnFailure bool
 }
 
 // InIngressList returns true if an ingress is in the ingress list
@@ -16,6 +16,7 @@ import (
 	"k8s.io/api/extensions/v1beta1"
 	"k8s.io/client-go/tools/record"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 )
@@ -159,3 +160,12 @@ func FatalValidateOnExistingConfig(eventRecorder record.EventRecorder, config *n
 
 	return nil
 }
+
+// ValidateRedirectHasTLS ensures an Ingress annotated with ssl-redirect has a TLS section to redirect to.
+func ValidateRedirectHasTLS(ingress *v1beta1.Ingress) error {
+	hasTLS := ingress.Spec.TLS != nil && len(ingress.Spec.TLS) > 0
+	if sslRedirect, _ := annotations.IsSslRedirect(ingress); sslRedirect && !hasTLS {
+		return ErrRedirectWithNoTLS
+	}
+	return nil
+}
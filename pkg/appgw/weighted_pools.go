@@ -0,0 +1,164 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+// weightedBackend is a Service, or a subset of its pods, which receives a share of the requests to a backend pool.
+type weightedBackend struct {
+	Name      string
+	Weight    int32
+	Addresses []n.ApplicationGatewayBackendAddress
+}
+
+// getWeightedAddresses approximates the split of the requests across the weighted backends. App Gateway has no weights;
+// It balances the requests evenly across the addresses of a pool, so the share of a backend is the share of its addresses
+// in the pool. Picks the number of addresses of each backend, whose shares come closest to the weights, preferring more
+// addresses among equally close splits. Returns the addresses of the pool and the number picked from each backend.
+func getWeightedAddresses(backends []weightedBackend) (*[]n.ApplicationGatewayBackendAddress, []int) {
+	var totalWeight int32
+	available := 0
+	for _, backend := range backends {
+		totalWeight += backend.Weight
+		available += len(backend.Addresses)
+	}
+
+	best := make([]int, len(backends))
+	bestError := math.Inf(1)
+	for size := 1; totalWeight > 0 && size <= available; size++ {
+		counts := make([]int, len(backends))
+		picked := 0
+		for idx, backend := range backends {
+			count := int(math.Round(float64(size) * float64(backend.Weight) / float64(totalWeight)))
+			if count > len(backend.Addresses) {
+				count = len(backend.Addresses)
+			}
+			counts[idx] = count
+			picked += count
+		}
+		if picked == 0 {
+			continue
+		}
+
+		splitError := 0.0
+		for idx, backend := range backends {
+			share := float64(counts[idx]) / float64(picked)
+			splitError = math.Max(splitError, math.Abs(share-float64(backend.Weight)/float64(totalWeight)))
+		}
+		// Larger pools come later; These win among equally close splits.
+		if splitError < bestError+1e-9 {
+			best, bestError = counts, math.Min(splitError, bestError)
+		}
+	}
+
+	addrSet := make(map[n.ApplicationGatewayBackendAddress]interface{})
+	for idx, backend := range backends {
+		for _, address := range backend.Addresses[:best[idx]] {
+			addrSet[address] = nil
+		}
+	}
+	return getBackendAddressMapKeys(&addrSet), best
+}
+
+// describeSplit lists the effective share of each backend, along with the addresses picked for the pool.
+func describeSplit(backends []weightedBackend, counts []int) string {
+	picked := 0
+	for _, count := range counts {
+		picked += count
+	}
+	var shares []string
+	for idx, backend := range backends {
+		share := 0
+		if picked > 0 {
+			share = int(math.Round(100 * float64(counts[idx]) / float64(picked)))
+		}
+		shares = append(shares, fmt.Sprintf("%s %d%% (weight %d, %d of %d addresses)", backend.Name, share, backend.Weight, counts[idx], len(backend.Addresses)))
+	}
+	return strings.Join(shares, ", ")
+}
+
+// getSplitEventType tells the type of the event reporting the split; A warning when the split leaves addresses out of the pool.
+// These receive no requests, so the backends serve with fewer pods than they run.
+func getSplitEventType(backends []weightedBackend, counts []int) string {
+	for idx, backend := range backends {
+		if counts[idx] < len(backend.Addresses) {
+			return v1.EventTypeWarning
+		}
+	}
+	return v1.EventTypeNormal
+}
+
+// getAddresses lists the sorted addresses of the endpoints, which serve the given port.
+func getAddresses(endpoints *v1.Endpoints, backendPort int32) []n.ApplicationGatewayBackendAddress {
+	var addresses []n.ApplicationGatewayBackendAddress
+	for _, subset := range endpoints.Subsets {
+		if _, portExists := getUniqueTCPPorts(subset)[backendPort]; portExists {
+			addresses = append(addresses, *getAddressesForSubset(subset)...)
+		}
+	}
+	addrSet := make(map[n.ApplicationGatewayBackendAddress]interface{})
+	for _, address := range addresses {
+		addrSet[address] = nil
+	}
+	return *getBackendAddressMapKeys(&addrSet)
+}
+
+// getCanary returns the canary Service of the Ingress and the percentage of the requests it receives; False when the Ingress has no canary.
+func getCanary(ingress *v1beta1.Ingress) (string, int32, bool) {
+	service, err := annotations.CanaryService(ingress)
+	if err != nil || service == "" {
+		return "", 0, false
+	}
+	weight, err := annotations.CanaryWeight(ingress)
+	if err != nil {
+		return "", 0, false
+	}
+	return service, weight, true
+}
+
+// getCanaryBackendAddressPool builds the pool, which splits the requests to the backend between the pods of its Service and of
+// the canary Service the Ingress is annotated with. Without pods of the canary Service serving the backend port, the backend
+// keeps the pool of its Service.
+func (c *appGwConfigBuilder) getCanaryBackendAddressPool(backendID backendIdentifier, serviceBackendPair serviceBackendPortPair, subset v1.EndpointSubset, canaryService string, canaryWeight int32, addressPools map[string]*n.ApplicationGatewayBackendAddressPool) *n.ApplicationGatewayBackendAddressPool {
+	canaryID := serviceIdentifier{Namespace: backendID.Namespace, Name: canaryService}
+	poolName := generateAddressPoolName(fmt.Sprintf("%s-canary-%s-%d", backendID.serviceFullName(), canaryService, canaryWeight), backendID.Backend.ServicePort.String(), serviceBackendPair.BackendPort)
+	if pool, ok := addressPools[poolName]; ok {
+		return pool
+	}
+
+	var canaryAddresses []n.ApplicationGatewayBackendAddress
+	if endpoints, err := c.k8sContext.GetEndpointsByService(canaryID.serviceKey()); err == nil {
+		canaryAddresses = getAddresses(endpoints, serviceBackendPair.BackendPort)
+	}
+	if len(canaryAddresses) == 0 {
+		logLine := fmt.Sprintf("Canary service %s has no endpoints serving port %d; Service %s receives all requests", canaryID.serviceKey(), serviceBackendPair.BackendPort, backendID.serviceKey())
+		glog.Warning(logLine)
+		c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonTrafficSplit, logLine)
+		return nil
+	}
+
+	backends := []weightedBackend{
+		{Name: backendID.serviceKey(), Weight: 100 - canaryWeight, Addresses: *getAddressesForSubset(subset)},
+		{Name: canaryID.serviceKey(), Weight: canaryWeight, Addresses: canaryAddresses},
+	}
+	addresses, counts := getWeightedAddresses(backends)
+	logLine := fmt.Sprintf("Backend pool %s splits the requests: %s", poolName, describeSplit(backends, counts))
+	glog.V(3).Info(logLine)
+	c.recorder.Event(backendID.Ingress, getSplitEventType(backends, counts), events.ReasonTrafficSplit, logLine)
+	return c.newPoolWithAddresses(poolName, addresses)
+}
@@ -0,0 +1,203 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+// appgw_suite_test.go launches these Ginkgo tests
+
+var _ = Describe("Weighted backend pools", func() {
+	newAddresses := func(prefix string, count int) []n.ApplicationGatewayBackendAddress {
+		var addresses []n.ApplicationGatewayBackendAddress
+		for idx := 0; idx < count; idx++ {
+			addresses = append(addresses, n.ApplicationGatewayBackendAddress{IPAddress: to.StringPtr(fmt.Sprintf("%s.%d", prefix, idx))})
+		}
+		return addresses
+	}
+
+	newEndpoints := func(name string, count int) *v1.Endpoints {
+		endpoints := &v1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Namespace: tests.Namespace, Name: name},
+			Subsets: []v1.EndpointSubset{
+				{
+					Ports: []v1.EndpointPort{{Protocol: v1.ProtocolTCP, Name: tests.ServiceHTTPPort, Port: tests.ContainerPort}},
+				},
+			},
+		}
+		for idx := 0; idx < count; idx++ {
+			endpoints.Subsets[0].Addresses = append(endpoints.Subsets[0].Addresses, v1.EndpointAddress{IP: fmt.Sprintf("10.0.%d.%d", len(name), idx)})
+		}
+		return endpoints
+	}
+
+	Context("test getWeightedAddresses", func() {
+		It("splits the requests exactly when the addresses allow it", func() {
+			backends := []weightedBackend{
+				{Name: "app-v1", Weight: 90, Addresses: newAddresses("10.0.1", 9)},
+				{Name: "app-v2", Weight: 10, Addresses: newAddresses("10.0.2", 3)},
+			}
+			addresses, counts := getWeightedAddresses(backends)
+			Expect(counts).To(Equal([]int{9, 1}))
+			Expect(*addresses).To(HaveLen(10))
+			Expect(describeSplit(backends, counts)).To(Equal("app-v1 90% (weight 90, 9 of 9 addresses), app-v2 10% (weight 10, 1 of 3 addresses)"))
+			Expect(getSplitEventType(backends, counts)).To(Equal(v1.EventTypeWarning))
+		})
+
+		It("reports a split, which keeps all addresses, as normal", func() {
+			backends := []weightedBackend{
+				{Name: "app-v1", Weight: 75, Addresses: newAddresses("10.0.1", 3)},
+				{Name: "app-v2", Weight: 25, Addresses: newAddresses("10.0.2", 1)},
+			}
+			_, counts := getWeightedAddresses(backends)
+			Expect(counts).To(Equal([]int{3, 1}))
+			Expect(getSplitEventType(backends, counts)).To(Equal(v1.EventTypeNormal))
+		})
+
+		It("picks the closest split when the addresses do not allow the exact one", func() {
+			addresses, counts := getWeightedAddresses([]weightedBackend{
+				{Name: "app-v1", Weight: 90, Addresses: newAddresses("10.0.1", 3)},
+				{Name: "app-v2", Weight: 10, Addresses: newAddresses("10.0.2", 3)},
+			})
+			Expect(counts).To(Equal([]int{3, 0}))
+			Expect(*addresses).To(HaveLen(3))
+		})
+
+		It("prefers more addresses among equally close splits", func() {
+			_, counts := getWeightedAddresses([]weightedBackend{
+				{Name: "app-v1", Weight: 50, Addresses: newAddresses("10.0.1", 4)},
+				{Name: "app-v2", Weight: 50, Addresses: newAddresses("10.0.2", 2)},
+			})
+			Expect(counts).To(Equal([]int{2, 2}))
+		})
+	})
+
+	Context("with an Ingress annotated with a canary Service", func() {
+		var cb appGwConfigBuilder
+		var cbCtx *ConfigBuilderContext
+		BeforeEach(func() {
+			cb = newConfigBuilderFixture(nil)
+			canaryService := tests.NewServiceFixture(*tests.NewServicePortsFixture()...)
+			canaryService.Name = "canary"
+			_ = cb.k8sContext.Caches.Service.Add(tests.NewServiceFixture(*tests.NewServicePortsFixture()...))
+			_ = cb.k8sContext.Caches.Service.Add(canaryService)
+			_ = cb.k8sContext.Caches.Endpoints.Add(newEndpoints(tests.ServiceName, 3))
+			_ = cb.k8sContext.Caches.Endpoints.Add(newEndpoints("canary", 2))
+
+			ingress := &v1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: tests.Namespace,
+					Name:      tests.Name,
+					Annotations: map[string]string{
+						annotations.IngressClassKey:  annotations.ApplicationGatewayIngressClass,
+						annotations.CanaryServiceKey: "canary",
+						annotations.CanaryWeightKey:  "25",
+					},
+				},
+				Spec: v1beta1.IngressSpec{
+					Rules: []v1beta1.IngressRule{
+						tests.NewIngressRuleFixture(tests.Host, tests.URLPath1, *tests.NewIngressBackendFixture(tests.ServiceName, 80)),
+					},
+				},
+			}
+			cbCtx = &ConfigBuilderContext{
+				IngressList: []*v1beta1.Ingress{ingress},
+				ServiceList: []*v1.Service{tests.NewServiceFixture(*tests.NewServicePortsFixture()...), canaryService},
+			}
+		})
+
+		It("splits the requests of the backend through a pool of its own and reports the split", func() {
+			_ = cb.BackendAddressPools(cbCtx)
+			var canaryPool *n.ApplicationGatewayBackendAddressPool
+			for idx, pool := range *cb.appGw.BackendAddressPools {
+				if *pool.Name != defaultBackendAddressPoolName {
+					canaryPool = &(*cb.appGw.BackendAddressPools)[idx]
+				}
+			}
+			Expect(canaryPool).ToNot(BeNil())
+			Expect(*canaryPool.Name).To(ContainSubstring("canary"))
+			Expect(*canaryPool.BackendAddresses).To(HaveLen(4))
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).ToNot(BeEmpty())
+			event := <-recorder.Events
+			Expect(event).To(HavePrefix(v1.EventTypeWarning))
+			Expect(event).To(ContainSubstring(events.ReasonTrafficSplit))
+			Expect(event).To(ContainSubstring(tests.Namespace + "/canary 25% (weight 25, 1 of 2 addresses)"))
+		})
+
+		It("keeps the pool of the Service when the canary Service has no endpoints", func() {
+			_ = cb.k8sContext.Caches.Endpoints.Delete(newEndpoints("canary", 2))
+			_ = cb.BackendAddressPools(cbCtx)
+			Expect(*cb.appGw.BackendAddressPools).To(HaveLen(2))
+			for _, pool := range *cb.appGw.BackendAddressPools {
+				Expect(*pool.Name).ToNot(ContainSubstring("canary"))
+			}
+		})
+	})
+
+	Context("with an Istio HTTP route splitting its requests across subsets", func() {
+		It("routes the requests through a pool with the pods of every weighted destination", func() {
+			cb := newConfigBuilderFixture(nil)
+			otherService := tests.NewServiceFixture(*tests.NewServicePortsFixture()...)
+			otherService.Name = "other"
+			_ = cb.k8sContext.Caches.Service.Add(tests.NewServiceFixture(*tests.NewServicePortsFixture()...))
+			_ = cb.k8sContext.Caches.Service.Add(otherService)
+			_ = cb.k8sContext.Caches.Endpoints.Add(newEndpoints(tests.ServiceName, 4))
+			_ = cb.k8sContext.Caches.Endpoints.Add(newEndpoints("other", 4))
+
+			gateway := &v1alpha3.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: tests.Namespace},
+				Spec: v1alpha3.GatewaySpec{
+					Servers: []v1alpha3.Server{{Port: v1alpha3.Port{Number: 80, Protocol: v1alpha3.ProtocolHTTP}, Hosts: []string{tests.Host}}},
+				},
+			}
+			virtualService := &v1alpha3.VirtualService{
+				ObjectMeta: metav1.ObjectMeta{Name: "virtual-service", Namespace: tests.Namespace},
+				Spec: v1alpha3.VirtualServiceSpec{
+					Hosts:    []string{tests.Host},
+					Gateways: []string{"gateway"},
+					HTTP: []v1alpha3.HTTPRoute{
+						{
+							Route: []v1alpha3.HTTPRouteDestination{
+								{Destination: v1alpha3.Destination{Host: tests.ServiceName, Port: v1alpha3.PortSelector{Number: 80}}, Weight: 75},
+								{Destination: v1alpha3.Destination{Host: "other", Port: v1alpha3.PortSelector{Number: 80}}, Weight: 25},
+							},
+						},
+					},
+				},
+			}
+			cbCtx := &ConfigBuilderContext{
+				IstioGateways:          []*v1alpha3.Gateway{gateway},
+				IstioVirtualServices:   []*v1alpha3.VirtualService{virtualService},
+				EnableIstioIntegration: true,
+			}
+
+			pools := cb.getIstioWeightedPools(cbCtx)
+			Expect(pools).To(HaveLen(1))
+			Expect(*pools[0].BackendAddresses).To(HaveLen(4))
+
+			listenerID := listenerIdentifier{FrontendPort: 80, HostName: tests.Host}
+			pathMap := cb.getIstioPathMaps(cbCtx)[listenerID]
+			Expect(*pathMap.DefaultBackendAddressPool.ID).To(Equal(*pools[0].ID))
+		})
+	})
+})
@@ -6,12 +6,43 @@
 package brownfield
 
 import (
+	"strings"
+
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/golang/glog"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
 type certName string
 type certsByName map[certName]n.ApplicationGatewaySslCertificate
 
+// GetBlacklistedCertificates splits the existing certificates into the ones AGIC must retain, and the ones AGIC created
+// and may remove once no Ingress references them. isAGICCertificate tells the names AGIC generates for certificates apart;
+// Certificates with other names, and certificates used by listeners AGIC is not allowed to mutate, are always retained.
+// The ownership manifest, when there is one, takes the place of isAGICCertificate.
+func (er ExistingResources) GetBlacklistedCertificates(isAGICCertificate func(name string) bool) ([]n.ApplicationGatewaySslCertificate, []n.ApplicationGatewaySslCertificate) {
+	if er.Ownership != nil {
+		isAGICCertificate = func(name string) bool {
+			return er.Ownership.Owns(ownership.SslCertificates, name)
+		}
+	}
+	blacklistedCertsSet := er.getBlacklistedCertsSet()
+	var blacklisted, nonBlacklisted []n.ApplicationGatewaySslCertificate
+	for _, cert := range er.Certificates {
+		_, isBlacklisted := blacklistedCertsSet[certName(*cert.Name)]
+		if isBlacklisted || !isAGICCertificate(*cert.Name) {
+			glog.V(5).Infof("[brownfield] Certificate %s is blacklisted", *cert.Name)
+			blacklisted = append(blacklisted, cert)
+			continue
+		}
+		glog.V(5).Infof("[brownfield] Certificate %s is not blacklisted", *cert.Name)
+		nonBlacklisted = append(nonBlacklisted, cert)
+	}
+	return blacklisted, nonBlacklisted
+}
+
 // MergeCerts merges list of lists of certs into a single list, maintaining uniqueness.
 func MergeCerts(certBuckets ...[]n.ApplicationGatewaySslCertificate) []n.ApplicationGatewaySslCertificate {
 	uniq := make(certsByName)
@@ -26,3 +57,55 @@ func MergeCerts(certBuckets ...[]n.ApplicationGatewaySslCertificate) []n.Applica
 	}
 	return merged
 }
+
+// LogCertificates emits a few log lines detailing what certificates are created, blacklisted, and removed from ARM.
+func LogCertificates(existingBlacklisted []n.ApplicationGatewaySslCertificate, existingNonBlacklisted []n.ApplicationGatewaySslCertificate, managedCerts []n.ApplicationGatewaySslCertificate) {
+	var garbage []n.ApplicationGatewaySslCertificate
+
+	blacklistedSet := indexCertsByName(existingBlacklisted)
+	managedSet := indexCertsByName(managedCerts)
+
+	for certName, cert := range indexCertsByName(existingNonBlacklisted) {
+		_, existsInBlacklist := blacklistedSet[certName]
+		_, existsInNewCerts := managedSet[certName]
+		if !existsInBlacklist && !existsInNewCerts {
+			garbage = append(garbage, cert)
+		}
+	}
+
+	glog.V(3).Info("[brownfield] Certificates AGIC created: ", getCertNames(managedCerts))
+	glog.V(3).Info("[brownfield] Existing Blacklisted Certificates AGIC will retain: ", getCertNames(existingBlacklisted))
+	glog.V(3).Info("[brownfield] Existing Certificates AGIC will remove: ", getCertNames(garbage))
+}
+
+func indexCertsByName(certs []n.ApplicationGatewaySslCertificate) certsByName {
+	indexed := make(certsByName)
+	for _, cert := range certs {
+		indexed[certName(*cert.Name)] = cert
+	}
+	return indexed
+}
+
+func getCertNames(certs []n.ApplicationGatewaySslCertificate) string {
+	var names []string
+	for _, cert := range certs {
+		names = append(names, *cert.Name)
+	}
+	if len(names) == 0 {
+		return "n/a"
+	}
+	return strings.Join(names, ", ")
+}
+
+// getBlacklistedCertsSet collects the certificates of the listeners AGIC is not allowed to mutate.
+func (er ExistingResources) getBlacklistedCertsSet() map[certName]interface{} {
+	blacklistedListeners, _ := er.GetBlacklistedListeners()
+	blacklistedCertsSet := make(map[certName]interface{})
+	for _, listener := range blacklistedListeners {
+		if listener.ApplicationGatewayHTTPListenerPropertiesFormat == nil || listener.SslCertificate == nil || listener.SslCertificate.ID == nil {
+			continue
+		}
+		blacklistedCertsSet[certName(utils.GetLastChunkOfSlashed(*listener.SslCertificate.ID))] = nil
+	}
+	return blacklistedCertsSet
+}
@@ -0,0 +1,88 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("Test blacklisting certificates", func() {
+
+	cert1 := fixtures.GetCertificate1() // used by the Basic listener for tests.OtherHost
+	cert2 := fixtures.GetCertificate2() // used by the PathBased1 listener for tests.Host
+	cert3 := fixtures.GetCertificate3() // used by the PathBased2 listener for tests.OtherHost
+	staleCert := n.ApplicationGatewaySslCertificate{Name: to.StringPtr("default-deleted-secret")}
+	manualCert := n.ApplicationGatewaySslCertificate{Name: to.StringPtr("uploaded-by-hand")}
+
+	appGw := fixtures.GetAppGateway()
+	appGw.SslCertificates = &[]n.ApplicationGatewaySslCertificate{cert1, cert2, cert3, staleCert, manualCert}
+
+	isAGICCertificate := func(name string) bool {
+		return strings.HasPrefix(name, "Certificate-") || strings.HasPrefix(name, "default-")
+	}
+
+	Context("Test GetBlacklistedCertificates()", func() {
+		It("should retain the certificates of blacklisted listeners and the ones AGIC did not create", func() {
+			prohibitedTargets := []*ptv1.AzureIngressProhibitedTarget{
+				{
+					Spec: ptv1.AzureIngressProhibitedTargetSpec{
+						Hostname: tests.Host,
+					},
+				},
+			}
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
+			blacklisted, nonBlacklisted := er.GetBlacklistedCertificates(isAGICCertificate)
+
+			Expect(blacklisted).To(ConsistOf(cert2, manualCert))
+			Expect(nonBlacklisted).To(ConsistOf(cert1, cert3, staleCert))
+		})
+
+		It("should retain all certificates, which AGIC did not create", func() {
+			er := NewExistingResources(appGw, nil, nil, nil)
+			blacklisted, nonBlacklisted := er.GetBlacklistedCertificates(func(string) bool { return false })
+
+			Expect(blacklisted).To(ConsistOf(cert1, cert2, cert3, staleCert, manualCert))
+			Expect(nonBlacklisted).To(BeEmpty())
+		})
+	})
+
+	Context("Test GetBlacklistedCertificates() with an ownership manifest", func() {
+		It("should retain the certificates AGIC did not create, regardless of their names", func() {
+			er := NewExistingResources(appGw, nil, nil, nil)
+			er.Ownership = ownership.NewManifest()
+			for _, listener := range *appGw.HTTPListeners {
+				er.Ownership.Add(ownership.HTTPListeners, *listener.Name, "", "")
+			}
+			for _, rule := range *appGw.RequestRoutingRules {
+				er.Ownership.Add(ownership.RequestRoutingRules, *rule.Name, "", "")
+			}
+			er.Ownership.Add(ownership.SslCertificates, *cert1.Name, "", "")
+			er.Ownership.Add(ownership.SslCertificates, *manualCert.Name, "", "")
+			blacklisted, nonBlacklisted := er.GetBlacklistedCertificates(isAGICCertificate)
+
+			Expect(blacklisted).To(ConsistOf(cert2, cert3, staleCert))
+			Expect(nonBlacklisted).To(ConsistOf(cert1, manualCert))
+		})
+	})
+
+	Context("Test MergeCerts()", func() {
+		It("should merge the retained and the new certificates", func() {
+			newCert := n.ApplicationGatewaySslCertificate{Name: to.StringPtr("default-new-secret")}
+			merged := MergeCerts([]n.ApplicationGatewaySslCertificate{cert1, manualCert}, []n.ApplicationGatewaySslCertificate{cert1, newCert})
+			Expect(merged).To(ConsistOf(cert1, manualCert, newCert))
+		})
+	})
+})
@@ -0,0 +1,59 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	"net"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/golang/glog"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// resolveFrontendIP resolves the IP of a ProhibitedTarget to FrontendIPPublic or FrontendIPPrivate.
+// The IP could be "public", "private", the name of a frontend IP configuration, or an IP address.
+func (er ExistingResources) resolveFrontendIP(ip string) string {
+	if ip == FrontendIPPublic || ip == FrontendIPPrivate {
+		return ip
+	}
+	for _, ipConf := range er.FrontendIPs {
+		if ipConf.Name != nil && strings.ToLower(*ipConf.Name) == ip {
+			return getFrontendIPType(ipConf)
+		}
+		if ipConf.ApplicationGatewayFrontendIPConfigurationPropertiesFormat != nil && ipConf.PrivateIPAddress != nil && *ipConf.PrivateIPAddress == ip {
+			return FrontendIPPrivate
+		}
+	}
+	// App Gateway has at most one public frontend IP; Its address is not part of the App Gateway resource.
+	if net.ParseIP(ip) != nil {
+		return FrontendIPPublic
+	}
+	glog.Warningf("[brownfield] Could not resolve IP %s to a frontend IP configuration of App Gateway", ip)
+	return ip
+}
+
+// getListenerFrontendIP looks up the frontend IP configuration of the given listener; Returns "" when not found.
+func (er ExistingResources) getListenerFrontendIP(listener n.ApplicationGatewayHTTPListener) string {
+	if listener.FrontendIPConfiguration == nil || listener.FrontendIPConfiguration.ID == nil {
+		return ""
+	}
+	name := utils.GetLastChunkOfSlashed(*listener.FrontendIPConfiguration.ID)
+	for _, ipConf := range er.FrontendIPs {
+		if ipConf.Name != nil && *ipConf.Name == name {
+			return getFrontendIPType(ipConf)
+		}
+	}
+	return ""
+}
+
+func getFrontendIPType(ipConf n.ApplicationGatewayFrontendIPConfiguration) string {
+	if ipConf.ApplicationGatewayFrontendIPConfigurationPropertiesFormat != nil && ipConf.PrivateIPAddress != nil {
+		return FrontendIPPrivate
+	}
+	return FrontendIPPublic
+}
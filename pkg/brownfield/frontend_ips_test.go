@@ -0,0 +1,179 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("Test blacklisting frontend IPs and ports", func() {
+
+	// Listeners for tests.Host are on the public frontend; Listeners for tests.OtherHost on the private one.
+	frontends := map[string]struct {
+		ip   string
+		port string
+	}{
+		fixtures.DefaultHTTPListenerName:  {fixtures.PublicIPName, "fp-80"},
+		fixtures.HTTPListenerPathBased1:   {fixtures.PublicIPName, "fp-8080"},
+		fixtures.HTTPListenerNameBasic:    {fixtures.PrivateIPName, "fp-80"},
+		fixtures.HTTPListenerPathBased2:   {fixtures.PrivateIPName, "fp-443"},
+		fixtures.HTTPListenerUnassociated: {fixtures.PrivateIPName, "fp-80"},
+	}
+
+	getAppGw := func() n.ApplicationGateway {
+		appGw := fixtures.GetAppGateway()
+		appGw.FrontendIPConfigurations = &[]n.ApplicationGatewayFrontendIPConfiguration{
+			fixtures.GetPublicIPConfiguration(),
+			fixtures.GetPrivateIPConfiguration(),
+		}
+		appGw.FrontendPorts = &[]n.ApplicationGatewayFrontendPort{}
+		for name, number := range map[string]int32{"fp-80": 80, "fp-443": 443, "fp-8080": 8080, "fp-9090": 9090} {
+			*appGw.FrontendPorts = append(*appGw.FrontendPorts, n.ApplicationGatewayFrontendPort{
+				Name: to.StringPtr(name),
+				ApplicationGatewayFrontendPortPropertiesFormat: &n.ApplicationGatewayFrontendPortPropertiesFormat{
+					Port: to.Int32Ptr(number),
+				},
+			})
+		}
+		for idx := range *appGw.HTTPListeners {
+			listener := &(*appGw.HTTPListeners)[idx]
+			frontend := frontends[*listener.Name]
+			listener.FrontendIPConfiguration = &n.SubResource{ID: to.StringPtr("/x/y/z/" + frontend.ip)}
+			listener.FrontendPort = &n.SubResource{ID: to.StringPtr("/x/y/z/" + frontend.port)}
+		}
+		return appGw
+	}
+
+	getListenerNames := func(listeners []n.ApplicationGatewayHTTPListener) []string {
+		var names []string
+		for _, listener := range listeners {
+			names = append(names, *listener.Name)
+		}
+		return names
+	}
+
+	getPortNames := func(ports []n.ApplicationGatewayFrontendPort) []string {
+		var names []string
+		for _, port := range ports {
+			names = append(names, *port.Name)
+		}
+		return names
+	}
+
+	Context("Test resolveFrontendIP()", func() {
+		It("should resolve the IP of a prohibited target to a frontend IP configuration", func() {
+			er := NewExistingResources(getAppGw(), nil, nil, nil)
+			Expect(er.resolveFrontendIP(FrontendIPPublic)).To(Equal(FrontendIPPublic))
+			Expect(er.resolveFrontendIP(FrontendIPPrivate)).To(Equal(FrontendIPPrivate))
+			Expect(er.resolveFrontendIP("privateip")).To(Equal(FrontendIPPrivate))
+			Expect(er.resolveFrontendIP("publicip")).To(Equal(FrontendIPPublic))
+			Expect(er.resolveFrontendIP("abc")).To(Equal(FrontendIPPrivate))
+			Expect(er.resolveFrontendIP("20.30.40.50")).To(Equal(FrontendIPPublic))
+			Expect(er.resolveFrontendIP("not-an-ip")).To(Equal("not-an-ip"))
+		})
+	})
+
+	Context("Test GetBlacklistedListeners() with a prohibited frontend IP", func() {
+		It("should blacklist all listeners on the private frontend", func() {
+			prohibitedTargets := []*ptv1.AzureIngressProhibitedTarget{
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{IP: "Private"}},
+			}
+			er := NewExistingResources(getAppGw(), prohibitedTargets, nil, nil)
+			blacklisted, nonBlacklisted := er.GetBlacklistedListeners()
+			Expect(getListenerNames(blacklisted)).To(ConsistOf(fixtures.HTTPListenerNameBasic, fixtures.HTTPListenerPathBased2, fixtures.HTTPListenerUnassociated))
+			Expect(getListenerNames(nonBlacklisted)).To(ConsistOf(fixtures.DefaultHTTPListenerName, fixtures.HTTPListenerPathBased1))
+
+			blacklistedPorts, _ := er.GetBlacklistedPorts()
+			Expect(getPortNames(blacklistedPorts)).To(ConsistOf("fp-80", "fp-443"))
+		})
+
+		It("should not blacklist the listeners of a hostname on another frontend", func() {
+			prohibitedTargets := []*ptv1.AzureIngressProhibitedTarget{
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.OtherHost, IP: FrontendIPPublic}},
+			}
+			er := NewExistingResources(getAppGw(), prohibitedTargets, nil, nil)
+			blacklisted, _ := er.GetBlacklistedListeners()
+			Expect(blacklisted).To(BeEmpty())
+
+			blacklistedRules, _ := er.GetBlacklistedRoutingRules()
+			Expect(blacklistedRules).To(BeEmpty())
+		})
+	})
+
+	Context("Test GetBlacklistedPorts() with a prohibited frontend port", func() {
+		It("should blacklist the listeners on the port and the port", func() {
+			prohibitedTargets := []*ptv1.AzureIngressProhibitedTarget{
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{Port: 8080}},
+			}
+			er := NewExistingResources(getAppGw(), prohibitedTargets, nil, nil)
+			blacklisted, _ := er.GetBlacklistedListeners()
+			Expect(getListenerNames(blacklisted)).To(ConsistOf(fixtures.HTTPListenerPathBased1))
+
+			blacklistedPorts, _ := er.GetBlacklistedPorts()
+			Expect(getPortNames(blacklistedPorts)).To(ConsistOf("fp-8080"))
+		})
+
+		It("should blacklist a prohibited port without listeners", func() {
+			prohibitedTargets := []*ptv1.AzureIngressProhibitedTarget{
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{Port: 9090}},
+			}
+			er := NewExistingResources(getAppGw(), prohibitedTargets, nil, nil)
+			blacklisted, _ := er.GetBlacklistedListeners()
+			Expect(blacklisted).To(BeEmpty())
+
+			blacklistedPorts, nonBlacklistedPorts := er.GetBlacklistedPorts()
+			Expect(getPortNames(blacklistedPorts)).To(ConsistOf("fp-9090"))
+			Expect(getPortNames(nonBlacklistedPorts)).To(ConsistOf("fp-80", "fp-443", "fp-8080"))
+		})
+	})
+
+	Context("Test PruneIngressRules() with a prohibited frontend IP", func() {
+		prohibitedTargets := []*ptv1.AzureIngressProhibitedTarget{
+			{Spec: ptv1.AzureIngressProhibitedTargetSpec{IP: FrontendIPPrivate}},
+		}
+		er := NewExistingResources(getAppGw(), prohibitedTargets, nil, nil)
+
+		newIngress := func(usePrivateIP string) *v1beta1.Ingress {
+			return &v1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotations.UsePrivateIPKey: usePrivateIP,
+					},
+				},
+				Spec: v1beta1.IngressSpec{
+					Rules: []v1beta1.IngressRule{
+						{
+							Host: tests.Host,
+							IngressRuleValue: v1beta1.IngressRuleValue{
+								HTTP: &v1beta1.HTTPIngressRuleValue{},
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("should keep the rules of Ingresses on the public frontend", func() {
+			ingress := newIngress("false")
+			Expect(er.PruneIngressRules(ingress, false)).To(Equal(ingress.Spec.Rules))
+		})
+
+		It("should prune the rules of Ingresses on the private frontend", func() {
+			Expect(er.PruneIngressRules(newIngress("true"), false)).To(BeEmpty())
+			Expect(er.PruneIngressRules(newIngress("false"), true)).To(BeEmpty())
+		})
+	})
+})
@@ -11,6 +11,7 @@ import (
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/golang/glog"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
@@ -23,7 +24,7 @@ func (er ExistingResources) GetBlacklistedProbes() ([]n.ApplicationGatewayProbe,
 	var nonBlacklistedProbes []n.ApplicationGatewayProbe
 	var blacklistedProbes []n.ApplicationGatewayProbe
 	for _, probe := range er.Probes {
-		if _, isBlacklisted := blacklistedProbesSet[probeName(*probe.Name)]; isBlacklisted {
+		if _, isBlacklisted := blacklistedProbesSet[probeName(*probe.Name)]; isBlacklisted || er.isForeign(ownership.Probes, *probe.Name) {
 			glog.V(5).Infof("Probe %s is blacklisted", *probe.Name)
 			blacklistedProbes = append(blacklistedProbes, probe)
 			continue
@@ -11,6 +11,7 @@ import (
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/golang/glog"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
@@ -24,7 +25,7 @@ func (er ExistingResources) GetBlacklistedHTTPSettings() ([]n.ApplicationGateway
 	var blacklisted []n.ApplicationGatewayBackendHTTPSettings
 	var nonBlacklisted []n.ApplicationGatewayBackendHTTPSettings
 	for _, setting := range er.HTTPSettings {
-		if _, isBlacklisted := blacklistedSettingsSet[settingName(*setting.Name)]; isBlacklisted {
+		if _, isBlacklisted := blacklistedSettingsSet[settingName(*setting.Name)]; isBlacklisted || er.isForeign(ownership.BackendHTTPSettings, *setting.Name) {
 			blacklisted = append(blacklisted, setting)
 			glog.V(5).Infof("HTTP Setting %s is blacklisted", *setting.Name)
 			continue
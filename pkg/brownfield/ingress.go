@@ -8,33 +8,44 @@ package brownfield
 import (
 	"k8s.io/api/extensions/v1beta1"
 
-	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 )
 
 // PruneIngressRules transforms the given ingress struct to remove targets, which AGIC should not create configuration for.
-func PruneIngressRules(ing *v1beta1.Ingress, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget) []v1beta1.IngressRule {
+// In allow-list mode (managed targets exist) only the targets matching a managed target are retained.
+// usePrivateIP reflects the USE_PRIVATE_IP setting; The use-private-ip annotation of the Ingress is looked up here.
+func (er ExistingResources) PruneIngressRules(ing *v1beta1.Ingress, usePrivateIP bool) []v1beta1.IngressRule {
 
 	if ing.Spec.Rules == nil || len(ing.Spec.Rules) == 0 {
 		return ing.Spec.Rules
 	}
 
-	blacklist := GetTargetBlacklist(prohibitedTargets)
+	blacklist := er.getTargetBlacklist()
+	whitelist := GetTargetWhitelist(er.ManagedTargets)
 
-	if blacklist == nil || len(*blacklist) == 0 {
+	if (blacklist == nil || len(*blacklist) == 0) && whitelist == nil {
 		return ing.Spec.Rules
 	}
 
+	// A rule is prohibited when any of the listeners AGIC would create for it is.
+	isProhibited := func(rule v1beta1.IngressRule, path TargetPath) bool {
+		for _, target := range getIngressRuleTargets(ing, rule, usePrivateIP) {
+			target.Path = path
+			if target.isProhibited(blacklist, whitelist) {
+				return true
+			}
+		}
+		return false
+	}
+
 	var rules []v1beta1.IngressRule
 
 	for _, rule := range ing.Spec.Rules {
 		if rule.HTTP == nil {
 			continue
 		}
-		target := Target{
-			Hostname: rule.Host,
-		}
 		if rule.HTTP.Paths == nil {
-			if target.IsBlacklisted(blacklist) {
+			if isProhibited(rule, "") {
 				continue
 			}
 			rules = append(rules, rule)
@@ -50,8 +61,7 @@ func PruneIngressRules(ing *v1beta1.Ingress, prohibitedTargets []*ptv1.AzureIngr
 			},
 		}
 		for _, path := range rule.HTTP.Paths {
-			target.Path = TargetPath(path.Path)
-			if target.IsBlacklisted(blacklist) {
+			if isProhibited(rule, TargetPath(path.Path)) {
 				continue
 			}
 			newRule.HTTP.Paths = append(newRule.HTTP.Paths, path)
@@ -63,3 +73,97 @@ func PruneIngressRules(ing *v1beta1.Ingress, prohibitedTargets []*ptv1.AzureIngr
 
 	return rules
 }
+
+// PathOverlap describes an Ingress path, which overlaps only partially with the path of a prohibited (or managed) target.
+// Since AGIC cannot split an App Gateway path rule, the entire Ingress path is pruned.
+type PathOverlap struct {
+	Hostname    string
+	IngressPath string
+	TargetPath  string
+
+	// Prohibited is true for a prohibited target, which covers part of the Ingress path;
+	// False for a managed target, which covers only part of the Ingress path.
+	Prohibited bool
+}
+
+// GetPartialPathOverlaps lists the paths of the given Ingress, which PruneIngressRules removes because they overlap only partially
+// with a prohibited or managed target. For instance an Ingress path /api/* and a prohibited target path /api/v1/*.
+func (er ExistingResources) GetPartialPathOverlaps(ing *v1beta1.Ingress, usePrivateIP bool) []PathOverlap {
+	blacklist := er.getTargetBlacklist()
+	whitelist := GetTargetWhitelist(er.ManagedTargets)
+
+	var overlaps []PathOverlap
+	seen := make(map[PathOverlap]interface{})
+	addOverlap := func(overlap PathOverlap) {
+		if _, exists := seen[overlap]; !exists {
+			seen[overlap] = nil
+			overlaps = append(overlaps, overlap)
+		}
+	}
+
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			for _, target := range getIngressRuleTargets(ing, rule, usePrivateIP) {
+				target.Path = TargetPath(path.Path)
+				for _, blTarget := range *blacklist {
+					if !target.IsBlacklisted(&[]Target{blTarget}) || blTarget.Path.contains(target.Path) {
+						continue
+					}
+					addOverlap(PathOverlap{Hostname: rule.Host, IngressPath: path.Path, TargetPath: string(blTarget.Path), Prohibited: true})
+				}
+				if whitelist == nil || target.IsWhitelisted(whitelist) {
+					continue
+				}
+				for _, wlTarget := range *whitelist {
+					if !target.Path.overlaps(wlTarget.Path) {
+						continue
+					}
+					// The Ingress path is not whitelisted, so an overlap with a managed target for the same host and port is partial.
+					hostAndPort := Target{Hostname: wlTarget.Hostname, Port: wlTarget.Port}
+					if target.IsWhitelisted(&[]Target{hostAndPort}) {
+						addOverlap(PathOverlap{Hostname: rule.Host, IngressPath: path.Path, TargetPath: string(wlTarget.Path), Prohibited: false})
+					}
+				}
+			}
+		}
+	}
+	return overlaps
+}
+
+// getIngressRuleTargets creates a Target for each listener AGIC would create for the given Ingress rule.
+// This mirrors processIngressRule in the appgw package: HTTPS when the Ingress has TLS for the host; HTTP when it does not or when
+// it is annotated with ssl-redirect.
+func getIngressRuleTargets(ing *v1beta1.Ingress, rule v1beta1.IngressRule, usePrivateIP bool) []Target {
+	ip := FrontendIPPublic
+	if usePrivateIPFromAnnotation, _ := annotations.UsePrivateIP(ing); usePrivateIPFromAnnotation || usePrivateIP {
+		ip = FrontendIPPrivate
+	}
+
+	hasTLS := false
+	for _, tls := range ing.Spec.TLS {
+		if len(tls.SecretName) == 0 {
+			continue
+		}
+		if len(tls.Hosts) == 0 {
+			hasTLS = true
+		}
+		for _, host := range tls.Hosts {
+			if host == "" || host == rule.Host {
+				hasTLS = true
+			}
+		}
+	}
+	sslRedirect, _ := annotations.IsSslRedirect(ing)
+
+	var targets []Target
+	if hasTLS {
+		targets = append(targets, Target{Hostname: rule.Host, IP: ip, Port: 443})
+	}
+	if sslRedirect || !hasTLS {
+		targets = append(targets, Target{Hostname: rule.Host, IP: ip, Port: 80})
+	}
+	return targets
+}
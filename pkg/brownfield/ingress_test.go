@@ -11,6 +11,7 @@ import (
 	"k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
 )
@@ -64,7 +65,7 @@ var _ = Describe("test pruning Ingress based on white/white lists", func() {
 			},
 		}
 
-		actualRules := PruneIngressRules(&ingress, prohibited)
+		actualRules := NewExistingResources(fixtures.GetAppGateway(), prohibited, nil, nil).PruneIngressRules(&ingress, false)
 
 		expected := v1beta1.Ingress{
 			Spec: v1beta1.IngressSpec{
@@ -96,6 +97,71 @@ var _ = Describe("test pruning Ingress based on white/white lists", func() {
 		It("should have trimmed the ingress rules to what AGIC is allowed to manage", func() {
 			Expect(actualRules).To(Equal(expected.Spec.Rules))
 		})
+
+		It("should have trimmed the ingress rules to the managed targets in allow-list mode", func() {
+			managed := fixtures.GetAzureIngressManagedTargets() // Host: "bye.com"
+			managed[0].Spec.Paths = []string{fixtures.PathFoo}
+			er := NewExistingResources(fixtures.GetAppGateway(), nil, managed, nil)
+			Expect(er.PruneIngressRules(&ingress, false)).To(Equal(expected.Spec.Rules))
+		})
+	})
+
+	Context("Test GetPartialPathOverlaps()", func() {
+		newIngress := func(paths ...string) *v1beta1.Ingress {
+			var ingressPaths []v1beta1.HTTPIngressPath
+			for _, path := range paths {
+				ingressPaths = append(ingressPaths, v1beta1.HTTPIngressPath{Path: path})
+			}
+			return &v1beta1.Ingress{
+				Spec: v1beta1.IngressSpec{
+					Rules: []v1beta1.IngressRule{
+						{
+							Host: tests.Host,
+							IngressRuleValue: v1beta1.IngressRuleValue{
+								HTTP: &v1beta1.HTTPIngressRuleValue{Paths: ingressPaths},
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("should report Ingress paths partially overlapping with a prohibited path", func() {
+			prohibited := []*ptv1.AzureIngressProhibitedTarget{
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host, Paths: []string{"/api/v1/*"}}},
+			}
+			er := NewExistingResources(fixtures.GetAppGateway(), prohibited, nil, nil)
+			ingress := newIngress("/API/*", "/api/v1/users", "/web/*")
+
+			Expect(er.GetPartialPathOverlaps(ingress, false)).To(ConsistOf(PathOverlap{
+				Hostname:    tests.Host,
+				IngressPath: "/API/*",
+				TargetPath:  "/api/v1/*",
+				Prohibited:  true,
+			}))
+
+			rules := er.PruneIngressRules(ingress, false)
+			Expect(len(rules)).To(Equal(1))
+			Expect(rules[0].HTTP.Paths).To(Equal([]v1beta1.HTTPIngressPath{{Path: "/web/*"}}))
+		})
+
+		It("should report Ingress paths only partially covered by a managed path", func() {
+			managed := fixtures.GetAzureIngressManagedTargets() // Host: "bye.com"
+			managed[0].Spec.Paths = []string{"/api/v1/*"}
+			er := NewExistingResources(fixtures.GetAppGateway(), nil, managed, nil)
+			ingress := newIngress("/api/*", "/api/v1/users", "/web/*")
+
+			Expect(er.GetPartialPathOverlaps(ingress, false)).To(ConsistOf(PathOverlap{
+				Hostname:    tests.Host,
+				IngressPath: "/api/*",
+				TargetPath:  "/api/v1/*",
+				Prohibited:  false,
+			}))
+
+			rules := er.PruneIngressRules(ingress, false)
+			Expect(len(rules)).To(Equal(1))
+			Expect(rules[0].HTTP.Paths).To(Equal([]v1beta1.HTTPIngressPath{{Path: "/api/v1/users"}}))
+		})
 	})
 
 })
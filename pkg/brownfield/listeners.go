@@ -11,6 +11,7 @@ import (
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/golang/glog"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
@@ -22,7 +23,7 @@ func (er ExistingResources) GetBlacklistedListeners() ([]n.ApplicationGatewayHTT
 	var blacklisted, nonBlacklisted []n.ApplicationGatewayHTTPListener
 	for _, listener := range er.Listeners {
 		listenerNm := listenerName(*listener.Name)
-		if _, exists := blacklistedListenersSet[listenerNm]; exists {
+		if _, exists := blacklistedListenersSet[listenerNm]; exists || er.isForeign(ownership.HTTPListeners, *listener.Name) {
 			glog.V(5).Infof("[brownfield] Listener %s is blacklisted", listenerNm)
 			blacklisted = append(blacklisted, listener)
 			continue
@@ -129,6 +130,29 @@ func (er ExistingResources) getBlacklistedListenersSet() map[listenerName]interf
 		}
 	}
 
+	// Prohibited targets pinning a frontend IP or port blacklist the listeners on that IP and/or port; for the given hostname, or any
+	for _, blTarget := range *er.getTargetBlacklist() {
+		if blTarget.IP == "" && blTarget.Port == 0 {
+			continue
+		}
+		for _, listener := range er.Listeners {
+			listenerTarget := er.getListenerTarget(listener)
+			hostIsBlacklisted := blTarget.Hostname == "" || strings.ToLower(blTarget.Hostname) == strings.ToLower(listenerTarget.Hostname)
+			if hostIsBlacklisted && listenerTarget.frontendIsBlacklisted(blTarget) {
+				blacklistedListenersSet[listenerName(*listener.Name)] = nil
+			}
+		}
+	}
+
+	// In allow-list mode every listener, which does not match a managed target, is prohibited
+	if whitelist := GetTargetWhitelist(er.ManagedTargets); whitelist != nil {
+		for _, listener := range er.Listeners {
+			if !er.getListenerTarget(listener).IsWhitelisted(whitelist) {
+				blacklistedListenersSet[listenerName(*listener.Name)] = nil
+			}
+		}
+	}
+
 	// Augment the list of prohibited listeners by looking at the rules
 	blacklistedRoutingRules, _ := er.GetBlacklistedRoutingRules()
 	for _, rule := range blacklistedRoutingRules {
@@ -139,3 +163,15 @@ func (er ExistingResources) getBlacklistedListenersSet() map[listenerName]interf
 	}
 	return blacklistedListenersSet
 }
+
+// getListenerTarget creates the Target for the given listener; The Target has the hostname, frontend IP and port of the listener.
+func (er ExistingResources) getListenerTarget(listener n.ApplicationGatewayHTTPListener) Target {
+	target := Target{
+		Port: er.getListenerPort(listener),
+		IP:   er.getListenerFrontendIP(listener),
+	}
+	if listener.HostName != nil {
+		target.Hostname = *listener.HostName
+	}
+	return target
+}
@@ -26,7 +26,7 @@ var _ = Describe("Test blacklist listeners", func() {
 	Context("Test GetBlacklistedListeners() with a blacklist", func() {
 		It("should create a list of blacklisted and non blacklisted listeners", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets() // Host: "bye.com", Paths: [/fox, /bar]
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			blacklisted, nonBlacklisted := er.GetBlacklistedListeners()
 
 			Expect(len(blacklisted)).To(Equal(3))
@@ -49,7 +49,7 @@ var _ = Describe("Test blacklist listeners", func() {
 					},
 				},
 			}
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 			blacklisted, nonBlacklisted := er.GetBlacklistedListeners()
 
@@ -67,7 +67,7 @@ var _ = Describe("Test blacklist listeners", func() {
 		It("should create a list of blacklisted and non blacklisted listeners", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()                    // Host: "bye.com", Paths: [/fox, /bar]
 			prohibitedTargets = append(prohibitedTargets, &ptv1.AzureIngressProhibitedTarget{}) // Host: '', Path: []
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			blacklisted, nonBlacklisted := er.GetBlacklistedListeners()
 
 			Expect(len(blacklisted)).To(Equal(4))
@@ -80,6 +80,33 @@ var _ = Describe("Test blacklist listeners", func() {
 		})
 	})
 
+	Context("Test GetBlacklistedListeners() in allow-list mode", func() {
+		It("should blacklist all listeners, which do not match a managed target", func() {
+			managedTargets := fixtures.GetAzureIngressManagedTargets() // Host: "bye.com"
+			er := NewExistingResources(appGw, nil, managedTargets, nil)
+			blacklisted, nonBlacklisted := er.GetBlacklistedListeners()
+
+			Expect(len(blacklisted)).To(Equal(4))
+			Expect(blacklisted).To(ContainElement(defaultListener))
+			Expect(blacklisted).To(ContainElement(listener1))
+			Expect(blacklisted).To(ContainElement(listener3))
+			Expect(blacklisted).To(ContainElement(listenerUnassociated))
+
+			Expect(len(nonBlacklisted)).To(Equal(1))
+			Expect(nonBlacklisted).To(ContainElement(listener2))
+		})
+
+		It("should blacklist the listener of a managed host with unmanaged paths", func() {
+			managedTargets := fixtures.GetAzureIngressManagedTargets()
+			managedTargets[0].Spec.Paths = []string{fixtures.PathFoo}
+			er := NewExistingResources(appGw, nil, managedTargets, nil)
+			blacklisted, nonBlacklisted := er.GetBlacklistedListeners()
+
+			Expect(len(blacklisted)).To(Equal(5))
+			Expect(len(nonBlacklisted)).To(Equal(0))
+		})
+	})
+
 	Context("Test getBlacklistedListenersSet()", func() {
 		It("should create a set of blacklisted listeners", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()
@@ -89,7 +116,7 @@ var _ = Describe("Test blacklist listeners", func() {
 				},
 			})
 
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			set := er.getBlacklistedListenersSet()
 
 			Expect(len(set)).To(Equal(4))
@@ -107,7 +134,7 @@ var _ = Describe("Test blacklist listeners", func() {
 	Context("Test getListenersByName()", func() {
 		It("should create a set of listeners by name and memoize it", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			er.listenersByName = nil
 			listenersByName := er.getListenersByName()
 			Expect(er.listenersByName).ToNot(BeNil())
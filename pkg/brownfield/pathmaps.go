@@ -10,6 +10,8 @@ import (
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/golang/glog"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 )
 
 type urlPathMapName string
@@ -21,8 +23,9 @@ type pathRulesByName map[pathRuleName]n.ApplicationGatewayPathRule
 // GetBlacklistedPathMaps filters the given list of routing pathMaps to the list pathMaps that AGIC is allowed to manage.
 func (er ExistingResources) GetBlacklistedPathMaps() ([]n.ApplicationGatewayURLPathMap, []n.ApplicationGatewayURLPathMap) {
 
-	blacklist := GetTargetBlacklist(er.ProhibitedTargets)
-	if blacklist == nil {
+	blacklist := er.getTargetBlacklist()
+	whitelist := GetTargetWhitelist(er.ManagedTargets)
+	if blacklist == nil && whitelist == nil {
 		return nil, er.URLPathMaps
 	}
 	_, pathMapToTargets := er.getRuleToTargets()
@@ -31,9 +34,12 @@ func (er ExistingResources) GetBlacklistedPathMaps() ([]n.ApplicationGatewayURLP
 	// Figure out if the given BackendAddressPathMap is blacklisted. It will be if it has a host/path that
 	// has been referenced in a AzureIngressProhibitedTarget CRD (even if it has some other paths that are not)
 	isBlacklisted := func(pathMap n.ApplicationGatewayURLPathMap) bool {
+		if er.isForeign(ownership.URLPathMaps, *pathMap.Name) {
+			return true
+		}
 		targetsForPathMap := pathMapToTargets[urlPathMapName(*pathMap.Name)]
 		for _, target := range targetsForPathMap {
-			if target.IsBlacklisted(blacklist) {
+			if target.isProhibited(blacklist, whitelist) {
 				glog.V(5).Infof("[brownfield] Routing PathMap %s is blacklisted", *pathMap.Name)
 				return true
 			}
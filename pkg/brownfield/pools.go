@@ -11,6 +11,7 @@ import (
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/golang/glog"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
@@ -23,7 +24,7 @@ func (er ExistingResources) GetBlacklistedPools() ([]n.ApplicationGatewayBackend
 	var blacklistedPools []n.ApplicationGatewayBackendAddressPool
 	var nonBlacklistedPools []n.ApplicationGatewayBackendAddressPool
 	for _, pool := range er.BackendPools {
-		if _, isBlacklisted := blacklistedPoolsSet[backendPoolName(*pool.Name)]; isBlacklisted {
+		if _, isBlacklisted := blacklistedPoolsSet[backendPoolName(*pool.Name)]; isBlacklisted || er.isForeign(ownership.BackendAddressPools, *pool.Name) {
 			blacklistedPools = append(blacklistedPools, pool)
 			glog.V(5).Infof("[brownfield] Backend Address Pool %s is blacklisted", *pool.Name)
 			continue
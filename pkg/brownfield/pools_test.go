@@ -11,6 +11,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
 )
 
@@ -57,7 +58,7 @@ var _ = Describe("Test blacklisting backend pools", func() {
 
 	prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()
 
-	brownfieldContext := NewExistingResources(appGw, prohibitedTargets, &defaultPool)
+	brownfieldContext := NewExistingResources(appGw, prohibitedTargets, nil, &defaultPool)
 
 	prohibitWildcard := &ptv1.AzureIngressProhibitedTarget{
 		Spec: ptv1.AzureIngressProhibitedTargetSpec{},
@@ -124,7 +125,7 @@ var _ = Describe("Test blacklisting backend pools", func() {
 
 		It("blacklists everything linked to a listener", func() {
 			prohibitedTargets := append(fixtures.GetAzureIngressProhibitedTargets(), prohibitWildcard)
-			bfCtx := NewExistingResources(appGw, prohibitedTargets, &defaultPool)
+			bfCtx := NewExistingResources(appGw, prohibitedTargets, nil, &defaultPool)
 			blacklisted, notBlacklisted := bfCtx.GetBlacklistedPools()
 
 			Expect(len(blacklisted)).To(Equal(3))
@@ -151,4 +152,25 @@ var _ = Describe("Test blacklisting backend pools", func() {
 			Expect(notBlacklisted).To(ContainElement(pool3))
 		})
 	})
+
+	Context("Test GetBlacklistedPools() with an ownership manifest", func() {
+
+		It("blacklists the pools AGIC did not create", func() {
+			bfCtx := NewExistingResources(appGw, prohibitedTargets, nil, &defaultPool)
+			bfCtx.Ownership = ownership.NewManifest()
+			for _, rule := range routingRules {
+				bfCtx.Ownership.Add(ownership.RequestRoutingRules, *rule.Name, "", "")
+			}
+			for _, pathMap := range paths {
+				bfCtx.Ownership.Add(ownership.URLPathMaps, *pathMap.Name, "", "")
+			}
+			bfCtx.Ownership.Add(ownership.BackendAddressPools, *defaultPool.Name, "", "")
+			bfCtx.Ownership.Add(ownership.BackendAddressPools, *pool1.Name, "", "")
+			blacklisted, notBlacklisted := bfCtx.GetBlacklistedPools()
+
+			// pool1 was created by AGIC, but is prohibited nevertheless; pool3 was not created by AGIC.
+			Expect(blacklisted).To(ConsistOf(pool1, pool2, pool3))
+			Expect(notBlacklisted).To(ConsistOf(defaultPool))
+		})
+	})
 })
@@ -8,6 +8,7 @@ package brownfield
 import (
 	"strings"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
@@ -27,7 +28,7 @@ func (er ExistingResources) GetBlacklistedPorts() ([]n.ApplicationGatewayFronten
 	for _, port := range er.Ports {
 		portJSON, _ := port.MarshalJSON()
 		// Is the port associated with a blacklisted listener?
-		if _, exists := blacklistedPortSet[portName(*port.Name)]; exists {
+		if _, exists := blacklistedPortSet[portName(*port.Name)]; exists || er.isForeign(ownership.FrontendPorts, *port.Name) {
 			glog.V(5).Infof("[brownfield] Port %s is blacklisted: %s", portName(*port.Name), portJSON)
 			blacklistedPorts = append(blacklistedPorts, port)
 			continue
@@ -105,5 +106,35 @@ func (er ExistingResources) getBlacklistedPortsSet() map[portName]interface{} {
 			blacklistedPortSet[portName] = nil
 		}
 	}
+
+	// Ports pinned by prohibited targets are retained even when no listener uses them (yet).
+	prohibitedPorts := make(map[int32]interface{})
+	for _, target := range *er.getTargetBlacklist() {
+		if target.Port != 0 {
+			prohibitedPorts[target.Port] = nil
+		}
+	}
+	for _, port := range er.Ports {
+		if port.Port == nil {
+			continue
+		}
+		if _, exists := prohibitedPorts[*port.Port]; exists {
+			blacklistedPortSet[portName(*port.Name)] = nil
+		}
+	}
 	return blacklistedPortSet
 }
+
+// getListenerPort looks up the port number of the frontend port of the given listener; Returns 0 when not found.
+func (er ExistingResources) getListenerPort(listener n.ApplicationGatewayHTTPListener) int32 {
+	if listener.FrontendPort == nil || listener.FrontendPort.ID == nil {
+		return 0
+	}
+	name := utils.GetLastChunkOfSlashed(*listener.FrontendPort.ID)
+	for _, port := range er.Ports {
+		if port.Name != nil && *port.Name == name && port.Port != nil {
+			return *port.Port
+		}
+	}
+	return 0
+}
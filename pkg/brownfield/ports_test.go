@@ -19,7 +19,7 @@ var _ = Describe("Test blacklisting ports", func() {
 	Context("Test getBlacklistedPortsSet()", func() {
 		It("should create a set of blacklisted ports", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			set := er.getBlacklistedPortsSet()
 			Expect(len(set)).To(Equal(1))
 		})
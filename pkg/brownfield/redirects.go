@@ -11,6 +11,7 @@ import (
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/golang/glog"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
@@ -23,7 +24,7 @@ func (er ExistingResources) GetBlacklistedRedirects() ([]n.ApplicationGatewayRed
 	var blacklistedRedirects []n.ApplicationGatewayRedirectConfiguration
 	var nonBlacklistedRedirects []n.ApplicationGatewayRedirectConfiguration
 	for _, redirect := range er.Redirects {
-		if _, isBlacklisted := blacklisted[redirectName(*redirect.Name)]; isBlacklisted {
+		if _, isBlacklisted := blacklisted[redirectName(*redirect.Name)]; isBlacklisted || er.isForeign(ownership.RedirectConfigurations, *redirect.Name) {
 			blacklistedRedirects = append(blacklistedRedirects, redirect)
 			glog.V(5).Infof("[brownfield] Redirect %s is blacklisted", *redirect.Name)
 			continue
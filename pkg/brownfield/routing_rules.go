@@ -11,6 +11,7 @@ import (
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 	"github.com/golang/glog"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
@@ -22,19 +23,24 @@ type ruleToTargets map[ruleName][]Target
 func (er ExistingResources) GetBlacklistedRoutingRules() ([]n.ApplicationGatewayRequestRoutingRule, []n.ApplicationGatewayRequestRoutingRule) {
 
 	// TODO(draychev): make a method of ExistingResources
-	blacklist := GetTargetBlacklist(er.ProhibitedTargets)
-	if blacklist == nil {
+	blacklist := er.getTargetBlacklist()
+	whitelist := GetTargetWhitelist(er.ManagedTargets)
+	if blacklist == nil && whitelist == nil {
 		return nil, er.RoutingRules
 	}
 	ruleToTargets, _ := er.getRuleToTargets()
 	glog.V(5).Infof("[brownfield] Rule to Targets map: %+v", ruleToTargets)
 
 	// Figure out if the given routing rule is blacklisted. It will be if it has a host/path that
-	// has been referenced in a AzureIngressProhibitedTarget CRD (even if it has some other paths that are not)
+	// has been referenced in a AzureIngressProhibitedTarget CRD (even if it has some other paths that are not),
+	// or in allow-list mode a host/path that has not been referenced in a AzureIngressManagedTarget CRD.
 	isBlacklisted := func(rule n.ApplicationGatewayRequestRoutingRule) bool {
+		if er.isForeign(ownership.RequestRoutingRules, *rule.Name) {
+			return true
+		}
 		targetsForRule := ruleToTargets[ruleName(*rule.Name)]
 		for _, target := range targetsForRule {
-			if target.IsBlacklisted(blacklist) {
+			if target.isProhibited(blacklist, whitelist) {
 				glog.V(5).Infof("[brownfield] Routing Rule %s is blacklisted", *rule.Name)
 				return true
 			}
@@ -149,16 +155,15 @@ func indexRulesByName(rules []n.ApplicationGatewayRequestRoutingRule) rulesByNam
 	return indexed
 }
 
-func (er ExistingResources) getHostNameForRoutingRule(rule n.ApplicationGatewayRequestRoutingRule) (string, error) {
+func (er ExistingResources) getListenerTargetForRoutingRule(rule n.ApplicationGatewayRequestRoutingRule) (Target, error) {
 	listenerName := listenerName(utils.GetLastChunkOfSlashed(*rule.HTTPListener.ID))
-	if listener, found := er.getListenersByName()[listenerName]; !found {
+	listener, found := er.getListenersByName()[listenerName]
+	if !found {
 		glog.Errorf("[brownfield] Could not find listener %s in index", listenerName)
 		// TODO(draychev): move this error into a top-level file
-		return "", ErrListenerLookup
-	} else if listener.HostName != nil {
-		return *listener.HostName, nil
+		return Target{}, ErrListenerLookup
 	}
-	return "", nil
+	return er.getListenerTarget(listener), nil
 }
 
 // getRuleToTargets creates a map from backend pool to targets this backend pool is responsible for.
@@ -171,17 +176,15 @@ func (er ExistingResources) getRuleToTargets() (ruleToTargets, pathmapToTargets)
 		if rule.HTTPListener == nil || rule.HTTPListener.ID == nil {
 			continue
 		}
-		hostName, err := er.getHostNameForRoutingRule(rule)
+		listenerTarget, err := er.getListenerTargetForRoutingRule(rule)
 		if err != nil {
 			glog.Errorf("[brownfield] Could not obtain hostname for rule %s; Skipping rule", ruleName(*rule.Name))
 			continue
 		}
 
 		// Regardless of whether we have a URL PathMap or not. This matches the default backend pool.
-		ruleToTargets[ruleName(*rule.Name)] = append(ruleToTargets[ruleName(*rule.Name)], Target{
-			Hostname: hostName,
-			// Path deliberately omitted
-		})
+		// Path deliberately omitted
+		ruleToTargets[ruleName(*rule.Name)] = append(ruleToTargets[ruleName(*rule.Name)], listenerTarget)
 
 		// SSL Redirects do not have BackendAddressPool
 		if rule.URLPathMap != nil {
@@ -193,7 +196,12 @@ func (er ExistingResources) getRuleToTargets() (ruleToTargets, pathmapToTargets)
 					continue
 				}
 				for _, path := range *pathRule.Paths {
-					target := Target{hostName, TargetPath(path)}
+					target := Target{
+						Hostname: listenerTarget.Hostname,
+						Path:     TargetPath(path),
+						Port:     listenerTarget.Port,
+						IP:       listenerTarget.IP,
+					}
 					ruleToTargets[ruleName(*rule.Name)] = append(ruleToTargets[ruleName(*rule.Name)], target)
 					pathMapToTargets[pathMapName] = append(pathMapToTargets[pathMapName], target)
 				}
@@ -25,7 +25,7 @@ var _ = Describe("Test blacklist request routing rules", func() {
 	Context("Test getRoutingRuleToTargetsMap()", func() {
 		It("should create a map of routing rules to targets", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets() // Host: "bye.com", Paths: [/fox, /bar]
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 			ruleToTargets, pathMapToTargets := er.getRuleToTargets()
 
@@ -56,7 +56,7 @@ var _ = Describe("Test blacklist request routing rules", func() {
 	Context("Test GetBlacklistedRoutingRules() with a blacklist", func() {
 		It("should create a list of blacklisted and non blacklisted request routing rules", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets() // Host: "bye.com", Paths: [/fox, /bar]
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			blacklisted, nonBlacklisted := er.GetBlacklistedRoutingRules()
 
 			Expect(len(blacklisted)).To(Equal(3))
@@ -75,7 +75,7 @@ var _ = Describe("Test blacklist request routing rules", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets() // Host: "bye.com", Paths: [/fox, /bar]
 			wildcard := &ptv1.AzureIngressProhibitedTarget{}
 			prohibitedTargets = append(prohibitedTargets, wildcard)
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 			blacklisted, nonBlacklisted := er.GetBlacklistedRoutingRules()
 
@@ -88,4 +88,20 @@ var _ = Describe("Test blacklist request routing rules", func() {
 			Expect(blacklisted).To(ContainElement(ruleDefault))
 		})
 	})
+	Context("Test GetBlacklistedRoutingRules() in allow-list mode", func() {
+		It("should blacklist all request routing rules, which do not match a managed target", func() {
+			managedTargets := fixtures.GetAzureIngressManagedTargets() // Host: "bye.com"
+			er := NewExistingResources(appGw, nil, managedTargets, nil)
+
+			blacklisted, nonBlacklisted := er.GetBlacklistedRoutingRules()
+
+			Expect(len(blacklisted)).To(Equal(3))
+			Expect(blacklisted).To(ContainElement(ruleDefault))
+			Expect(blacklisted).To(ContainElement(ruleBasic))
+			Expect(blacklisted).To(ContainElement(rulePathBased2))
+
+			Expect(len(nonBlacklisted)).To(Equal(1))
+			Expect(nonBlacklisted).To(ContainElement(rulePathBased1))
+		})
+	})
 })
@@ -11,20 +11,37 @@ import (
 
 	"github.com/golang/glog"
 
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 )
 
 // TargetBlacklist is a list of Targets, which AGIC is not allowed to apply configuration for.
 type TargetBlacklist *[]Target
 
+// TargetWhitelist is a list of Targets, which AGIC is allowed to apply configuration for.
+// A nil whitelist indicates AGIC is not in allow-list mode.
+type TargetWhitelist *[]Target
+
 type TargetPath string
 
 // Target uniquely identifies a subset of App Gateway configuration, which AGIC will manage or be prohibited from managing.
 type Target struct {
 	Hostname string     `json:"Hostname,omitempty"`
 	Path     TargetPath `json:"Path,omitempty"`
+	Port     int32      `json:"Port,omitempty"`
+
+	// IP is the frontend IP configuration of the target: either FrontendIPPublic or FrontendIPPrivate.
+	IP string `json:"IP,omitempty"`
 }
 
+const (
+	// FrontendIPPublic identifies the public frontend IP configuration of App Gateway.
+	FrontendIPPublic = "public"
+
+	// FrontendIPPrivate identifies the private frontend IP configuration of App Gateway.
+	FrontendIPPrivate = "private"
+)
+
 // IsBlacklisted figures out whether a given Target objects in a list of blacklisted targets.
 func (t Target) IsBlacklisted(blacklist TargetBlacklist) bool {
 	jsonTarget, _ := json.Marshal(t)
@@ -35,12 +52,11 @@ func (t Target) IsBlacklisted(blacklist TargetBlacklist) bool {
 		// AGIC is allowed to create and modify App Gwy config for blank host.
 		hostIsBlacklisted := blTarget.Hostname == "" || strings.ToLower(t.Hostname) == strings.ToLower(blTarget.Hostname)
 
-		pathIsBlacklisted := blTarget.Path == "" || blTarget.Path == "/*" || t.Path.lower() == blTarget.Path.lower() || blTarget.Path.contains(t.Path) // TODO(draychev): || t.Path.contains(blTarget.Path)
+		// The target is blacklisted when its URL paths overlap with the blacklisted ones: either path contains the other.
+		// A target without a path stands for the listener and default backend; It does not contain any blacklisted path.
+		pathIsBlacklisted := blTarget.Path.contains(t.Path) || (t.Path != "" && t.Path.contains(blTarget.Path))
 
-		// With this version we keep things as simple as possible: match host and exact path to determine
-		// whether given target is in the blacklist. Ideally this would be URL Path set overlap operation,
-		// which we deliberately leave for a later time.
-		if hostIsBlacklisted && pathIsBlacklisted {
+		if hostIsBlacklisted && pathIsBlacklisted && t.frontendIsBlacklisted(blTarget) {
 			glog.V(5).Infof("[brownfield] Target %s is blacklisted", jsonTarget)
 			return true // Found it
 		}
@@ -49,20 +65,99 @@ func (t Target) IsBlacklisted(blacklist TargetBlacklist) bool {
 	return false // Did not find it
 }
 
+// frontendIsBlacklisted figures out whether the frontend IP and port of the Target match the ones pinned by the blacklisted target.
+// A blacklisted target, which does not pin IP or port, matches any; So does a Target with an unknown IP or port.
+func (t Target) frontendIsBlacklisted(blTarget Target) bool {
+	ipIsBlacklisted := blTarget.IP == "" || t.IP == "" || t.IP == blTarget.IP
+	portIsBlacklisted := blTarget.Port == 0 || t.Port == 0 || t.Port == blTarget.Port
+	return ipIsBlacklisted && portIsBlacklisted
+}
+
+// IsWhitelisted figures out whether a given Target is in a list of whitelisted targets.
+func (t Target) IsWhitelisted(whitelist TargetWhitelist) bool {
+	jsonTarget, _ := json.Marshal(t)
+	for _, wlTarget := range *whitelist {
+
+		// Unlike the blacklist an empty hostname is not a wildcard; It whitelists only the listeners without a hostname.
+		hostIsWhitelisted := strings.ToLower(t.Hostname) == strings.ToLower(wlTarget.Hostname)
+
+		// A zero port is either not restricted by the whitelist, or not known yet (Ingress rules do not carry ports).
+		portIsWhitelisted := wlTarget.Port == 0 || t.Port == 0 || t.Port == wlTarget.Port
+
+		// A target without a path stands for the listener and the default backend of the host; These are whitelisted
+		// along with any path of the host, so that AGIC can attach path rules to the listener.
+		pathIsWhitelisted := t.Path == "" || wlTarget.Path.contains(t.Path)
+
+		if hostIsWhitelisted && portIsWhitelisted && pathIsWhitelisted {
+			glog.V(5).Infof("[brownfield] Target %s is whitelisted", jsonTarget)
+			return true // Found it
+		}
+	}
+	glog.V(5).Infof("[brownfield] Target %s is not whitelisted", jsonTarget)
+	return false // Did not find it
+}
+
+// isProhibited figures out whether AGIC is prohibited from mutating config for the given Target.
+// This is the case for blacklisted targets, and in allow-list mode for the targets, which are not whitelisted.
+func (t Target) isProhibited(blacklist TargetBlacklist, whitelist TargetWhitelist) bool {
+	return t.IsBlacklisted(blacklist) || (whitelist != nil && !t.IsWhitelisted(whitelist))
+}
+
 // GetTargetBlacklist returns the list of Targets given a list ProhibitedTarget CRDs.
+// The IP of the Targets is the IP of the CRD as is; Use ExistingResources.getTargetBlacklist() to resolve it to a frontend IP configuration.
 func GetTargetBlacklist(prohibitedTargets []*ptv1.AzureIngressProhibitedTarget) TargetBlacklist {
-	// TODO(draychev): make this a method of ExistingResources and memoize it.
 	var target []Target
 	for _, prohibitedTarget := range prohibitedTargets {
+		ip := strings.ToLower(strings.TrimSpace(prohibitedTarget.Spec.IP))
 		if len(prohibitedTarget.Spec.Paths) == 0 {
 			target = append(target, Target{
 				Hostname: prohibitedTarget.Spec.Hostname,
+				IP:       ip,
+				Port:     prohibitedTarget.Spec.Port,
 			})
 		}
 		for _, path := range prohibitedTarget.Spec.Paths {
 			target = append(target, Target{
 				Hostname: prohibitedTarget.Spec.Hostname,
 				Path:     TargetPath(strings.ToLower(path)),
+				IP:       ip,
+				Port:     prohibitedTarget.Spec.Port,
+			})
+		}
+	}
+	return &target
+}
+
+// getTargetBlacklist returns the list of Targets given the ProhibitedTarget CRDs, with IPs resolved to frontend IP configurations.
+func (er ExistingResources) getTargetBlacklist() TargetBlacklist {
+	blacklist := GetTargetBlacklist(er.ProhibitedTargets)
+	for idx := range *blacklist {
+		if ip := (*blacklist)[idx].IP; ip != "" {
+			(*blacklist)[idx].IP = er.resolveFrontendIP(ip)
+		}
+	}
+	return blacklist
+}
+
+// GetTargetWhitelist returns the list of Targets given a list of ManagedTarget CRDs.
+// Returns nil when there are no ManagedTarget CRDs, i.e. AGIC is not in allow-list mode.
+func GetTargetWhitelist(managedTargets []*mtv1.AzureIngressManagedTarget) TargetWhitelist {
+	if len(managedTargets) == 0 {
+		return nil
+	}
+	var target []Target
+	for _, managedTarget := range managedTargets {
+		if len(managedTarget.Spec.Paths) == 0 {
+			target = append(target, Target{
+				Hostname: managedTarget.Spec.Hostname,
+				Port:     managedTarget.Spec.Port,
+			})
+		}
+		for _, path := range managedTarget.Spec.Paths {
+			target = append(target, Target{
+				Hostname: managedTarget.Spec.Hostname,
+				Path:     TargetPath(strings.ToLower(path)),
+				Port:     managedTarget.Spec.Port,
 			})
 		}
 	}
@@ -73,34 +168,46 @@ func (p TargetPath) lower() string {
 	return strings.ToLower(string(p))
 }
 
+// matchesAll figures out whether the path matches any URL path.
+func (p TargetPath) matchesAll() bool {
+	return p == "" || p == "*" || p == "/*"
+}
+
+// isWildcard figures out whether the path ends with a *; App Gateway allows a * only at the end of a path.
+func (p TargetPath) isWildcard() bool {
+	return strings.HasSuffix(string(p), "*")
+}
+
+// contains figures out whether every URL path matched by otherPath is also matched by thisPath.
+// Paths are compared case-insensitively. "/x/*" contains "/x", "/x/", "/x/y" and "/x/y/*"; "/x" contains only "/x".
 func (thisPath TargetPath) contains(otherPath TargetPath) bool {
-	if thisPath == "" || thisPath == "*" || thisPath == "/*" {
+	if thisPath.matchesAll() {
 		return true
 	}
 
-	// For strings that do not end with a * - do exact match
-	if !strings.HasSuffix(thisPath.lower(), "*") {
+	// Only a path matching everything contains a path matching everything.
+	if otherPath.matchesAll() {
+		return false
+	}
+
+	// For paths that do not end with a * - do exact match
+	if !thisPath.isWildcard() {
 		return thisPath.lower() == otherPath.lower()
 	}
 
+	prefix := strings.TrimSuffix(thisPath.lower(), "*")
+
 	// "/x/*" contains "/x"
-	if strings.TrimRight(thisPath.lower(), "/*") == strings.TrimRight(otherPath.lower(), "/*") {
+	if otherPath.lower() == strings.TrimSuffix(prefix, "/") {
 		return true
 	}
 
-	if len(thisPath) > len(otherPath) {
-		return false
-	}
+	// "/x/*" contains "/x/y" and "/x/y/*"; The * of the other path (if any) is past the prefix
+	return strings.HasPrefix(otherPath.lower(), prefix)
+}
 
-	thisPathChunks := strings.Split(thisPath.lower(), "/")
-	otherPathChunks := strings.Split(otherPath.lower(), "/")
-	for idx, _ := range thisPathChunks {
-		if thisPathChunks[idx] == "*" {
-			return true
-		}
-		if thisPathChunks[idx] != otherPathChunks[idx] {
-			return false
-		}
-	}
-	return false
+// overlaps figures out whether there is a URL path matched by both thisPath and otherPath.
+// Since App Gateway paths only allow a trailing *, two paths overlap only when one of them contains the other.
+func (thisPath TargetPath) overlaps(otherPath TargetPath) bool {
+	return thisPath.contains(otherPath) || otherPath.contains(thisPath)
 }
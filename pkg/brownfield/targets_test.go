@@ -6,6 +6,7 @@
 package brownfield
 
 import (
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	v1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -88,6 +89,82 @@ var _ = Describe("Test blacklisting targets", func() {
 		})
 	})
 
+	Context("test GetTargetWhitelist", func() {
+		It("should be nil without managed targets", func() {
+			Expect(GetTargetWhitelist(nil)).To(BeNil())
+		})
+
+		It("should have produced correct Managed Targets list", func() {
+			managedTargets := []*mtv1.AzureIngressManagedTarget{
+				{
+					Spec: mtv1.AzureIngressManagedTargetSpec{
+						Hostname: tests.Host,
+						Port:     443,
+						Paths:    []string{"/API/*", fixtures.PathFoo},
+					},
+				},
+				{
+					Spec: mtv1.AzureIngressManagedTargetSpec{
+						Hostname: tests.OtherHost,
+					},
+				},
+			}
+			whitelist := GetTargetWhitelist(managedTargets)
+			Expect(*whitelist).To(ConsistOf(
+				Target{Hostname: tests.Host, Path: "/api/*", Port: 443},
+				Target{Hostname: tests.Host, Path: fixtures.PathFoo, Port: 443},
+				Target{Hostname: tests.OtherHost},
+			))
+		})
+	})
+
+	Context("Test IsWhitelisted", func() {
+		whitelist := []Target{
+			{
+				Hostname: tests.Host,
+				Path:     "/api/*",
+				Port:     443,
+			},
+			{
+				Hostname: tests.OtherHost,
+			},
+		}
+
+		It("should whitelist targets matching host, port and path", func() {
+			Expect(Target{Hostname: tests.Host, Path: "/api/v1", Port: 443}.IsWhitelisted(&whitelist)).To(BeTrue())
+			Expect(Target{Hostname: "BYE.com", Path: "/API", Port: 443}.IsWhitelisted(&whitelist)).To(BeTrue())
+			Expect(Target{Hostname: tests.OtherHost, Path: "/anything", Port: 80}.IsWhitelisted(&whitelist)).To(BeTrue())
+		})
+
+		It("should whitelist the listener of a whitelisted host", func() {
+			Expect(Target{Hostname: tests.Host, Port: 443}.IsWhitelisted(&whitelist)).To(BeTrue())
+		})
+
+		It("should whitelist targets with an unknown port", func() {
+			Expect(Target{Hostname: tests.Host, Path: "/api/v1"}.IsWhitelisted(&whitelist)).To(BeTrue())
+		})
+
+		It("should not whitelist anything else", func() {
+			Expect(Target{Hostname: tests.Host, Path: "/api/v1", Port: 80}.IsWhitelisted(&whitelist)).To(BeFalse())
+			Expect(Target{Hostname: tests.Host, Path: fixtures.PathFoo, Port: 443}.IsWhitelisted(&whitelist)).To(BeFalse())
+			Expect(Target{Hostname: tests.HostUnassociated}.IsWhitelisted(&whitelist)).To(BeFalse())
+			Expect(Target{Path: "/api/v1"}.IsWhitelisted(&whitelist)).To(BeFalse())
+		})
+
+		It("should prohibit targets, which are blacklisted or not whitelisted", func() {
+			blacklist := []Target{
+				{
+					Hostname: tests.OtherHost,
+					Path:     fixtures.PathFox,
+				},
+			}
+			Expect(Target{Hostname: tests.OtherHost, Path: fixtures.PathFox}.isProhibited(&blacklist, &whitelist)).To(BeTrue())
+			Expect(Target{Hostname: tests.HostUnassociated}.isProhibited(&blacklist, &whitelist)).To(BeTrue())
+			Expect(Target{Hostname: tests.HostUnassociated}.isProhibited(&blacklist, nil)).To(BeFalse())
+			Expect(Target{Hostname: tests.OtherHost, Path: fixtures.PathFoo}.isProhibited(&blacklist, &whitelist)).To(BeFalse())
+		})
+	})
+
 	Context("test TargetPath.contains(TargetPath)", func() {
 		It("TargetPath.contains(TargetPath) should work correctly", func() {
 			Expect(TargetPath("/*").contains("/blah")).To(BeTrue())
@@ -119,6 +196,47 @@ var _ = Describe("Test blacklisting targets", func() {
 
 			Expect(TargetPath("/abCD/xyZ/1/*").contains("/AbcD/Xyz/*")).To(BeFalse())
 			Expect(TargetPath("/AbcD/Xyz/*").contains("/abCD/xyZ/1/*")).To(BeTrue())
+
+			Expect(TargetPath("/api/*").contains("/api/v1/*")).To(BeTrue())
+			Expect(TargetPath("/api/v1/*").contains("/api/*")).To(BeFalse())
+			Expect(TargetPath("/api/*").contains("/apiv2")).To(BeFalse())
+			Expect(TargetPath("/api").contains("/api/*")).To(BeFalse())
+			Expect(TargetPath("/API/*").contains("/api/v1")).To(BeTrue())
+		})
+	})
+
+	Context("test TargetPath.overlaps(TargetPath)", func() {
+		It("TargetPath.overlaps(TargetPath) should work in both directions", func() {
+			Expect(TargetPath("/api/*").overlaps("/api/v1/*")).To(BeTrue())
+			Expect(TargetPath("/api/v1/*").overlaps("/api/*")).To(BeTrue())
+
+			Expect(TargetPath("/").overlaps("/*")).To(BeTrue())
+			Expect(TargetPath("/*").overlaps("/")).To(BeTrue())
+
+			Expect(TargetPath("/API/*").overlaps("/api/V1")).To(BeTrue())
+			Expect(TargetPath("/api/V1").overlaps("/API/*")).To(BeTrue())
+
+			Expect(TargetPath("/api/*").overlaps("/web/*")).To(BeFalse())
+			Expect(TargetPath("/api/v1").overlaps("/api/v2")).To(BeFalse())
+			Expect(TargetPath("/api/*").overlaps("/apiv2/*")).To(BeFalse())
+		})
+	})
+
+	Context("test IsBlacklisted() with overlapping paths", func() {
+		blacklist := []Target{{Hostname: tests.Host, Path: "/api/V1/*"}}
+
+		It("should blacklist a path contained in the blacklisted path", func() {
+			Expect(Target{Hostname: tests.Host, Path: "/api/v1/users"}.IsBlacklisted(&blacklist)).To(BeTrue())
+		})
+
+		It("should blacklist a path containing the blacklisted path", func() {
+			Expect(Target{Hostname: tests.Host, Path: "/API/*"}.IsBlacklisted(&blacklist)).To(BeTrue())
+			Expect(Target{Hostname: tests.Host, Path: "/*"}.IsBlacklisted(&blacklist)).To(BeTrue())
+		})
+
+		It("should not blacklist disjoint paths or the listener of the host", func() {
+			Expect(Target{Hostname: tests.Host, Path: "/api/v2/*"}.IsBlacklisted(&blacklist)).To(BeFalse())
+			Expect(Target{Hostname: tests.Host}.IsBlacklisted(&blacklist)).To(BeFalse())
 		})
 	})
 
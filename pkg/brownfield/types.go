@@ -6,7 +6,11 @@
 package brownfield
 
 import (
+	"github.com/golang/glog"
+
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
 )
 
@@ -26,16 +30,23 @@ type ExistingResources struct {
 	Ports              []n.ApplicationGatewayFrontendPort
 	Probes             []n.ApplicationGatewayProbe
 	Redirects          []n.ApplicationGatewayRedirectConfiguration
+	FrontendIPs        []n.ApplicationGatewayFrontendIPConfiguration
 	ProhibitedTargets  []*ptv1.AzureIngressProhibitedTarget
+	ManagedTargets     []*mtv1.AzureIngressManagedTarget
 	DefaultBackendPool *n.ApplicationGatewayBackendAddressPool
 
+	// Ownership lists the sub-resources AGIC created. When set, sub-resources missing from it are retained as they are,
+	// regardless of prohibited and managed targets. When nil AGIC relies on prohibited and managed targets alone.
+	Ownership *ownership.Manifest
+
 	// Cache helper structs
 	listenersByName   map[listenerName]n.ApplicationGatewayHTTPListener
 	urlPathMapsByName pathMapsByName
 }
 
 // NewExistingResources creates a new ExistingResources struct.
-func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, defaultPool *n.ApplicationGatewayBackendAddressPool) ExistingResources {
+// When managedTargets is not empty AGIC is in allow-list mode and may only mutate config for these targets.
+func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, managedTargets []*mtv1.AzureIngressManagedTarget, defaultPool *n.ApplicationGatewayBackendAddressPool) ExistingResources {
 	var allExistingSettings []n.ApplicationGatewayBackendHTTPSettings
 	if appGw.BackendHTTPSettingsCollection != nil {
 		allExistingSettings = *appGw.BackendHTTPSettingsCollection
@@ -81,6 +92,11 @@ func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.
 		allExistingRedirects = *appGw.RedirectConfigurations
 	}
 
+	var allExistingFrontendIPs []n.ApplicationGatewayFrontendIPConfiguration
+	if appGw.FrontendIPConfigurations != nil {
+		allExistingFrontendIPs = *appGw.FrontendIPConfigurations
+	}
+
 	return ExistingResources{
 		BackendPools:       allExistingBackendPools,
 		Certificates:       allExistingCertificates,
@@ -91,7 +107,9 @@ func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.
 		Ports:              allExistingPorts,
 		Probes:             allExistingHealthProbes,
 		Redirects:          allExistingRedirects,
+		FrontendIPs:        allExistingFrontendIPs,
 		ProhibitedTargets:  prohibitedTargets,
+		ManagedTargets:     managedTargets,
 		DefaultBackendPool: defaultPool,
 	}
 }
@@ -99,10 +117,20 @@ func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.
 func (er ExistingResources) getProhibitedHostnames() map[string]interface{} {
 	prohibitedHostnames := make(map[string]interface{})
 	for _, pt := range er.ProhibitedTargets {
-		if len(pt.Spec.Hostname) == 0 {
+		// Targets pinning a frontend IP or port prohibit only some of the listeners of the hostname.
+		if len(pt.Spec.Hostname) == 0 || pt.Spec.IP != "" || pt.Spec.Port != 0 {
 			continue
 		}
 		prohibitedHostnames[pt.Spec.Hostname] = nil
 	}
 	return prohibitedHostnames
 }
+
+// isForeign figures out whether the sub-resource was created by someone other than AGIC, according to the ownership manifest.
+func (er ExistingResources) isForeign(resourceType ownership.ResourceType, name string) bool {
+	if er.Ownership.Owns(resourceType, name) {
+		return false
+	}
+	glog.V(5).Infof("[brownfield] %s %s is not in the ownership manifest", resourceType, name)
+	return true
+}
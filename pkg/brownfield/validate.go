@@ -0,0 +1,40 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	"fmt"
+	"strings"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+)
+
+// ValidateProhibitedTargetSpec returns a list of problems with the spec of the given prohibited target; Empty when the spec is valid.
+func ValidateProhibitedTargetSpec(spec ptv1.AzureIngressProhibitedTargetSpec) []string {
+	var problems []string
+	if strings.ContainsAny(spec.Hostname, "/: ") {
+		problems = append(problems, fmt.Sprintf("hostname %q must not contain a scheme, port, path or spaces", spec.Hostname))
+	}
+	if spec.Port < 0 || spec.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("port %d must be between 1 and 65535", spec.Port))
+	}
+	for _, path := range spec.Paths {
+		problems = append(problems, validateTargetPath(path)...)
+	}
+	return problems
+}
+
+// validateTargetPath checks that the path is one App Gateway would accept in a path rule.
+func validateTargetPath(path string) []string {
+	var problems []string
+	if !strings.HasPrefix(path, "/") {
+		problems = append(problems, fmt.Sprintf("path %q must begin with a /", path))
+	}
+	if idx := strings.Index(path, "*"); idx != -1 && idx != len(path)-1 {
+		problems = append(problems, fmt.Sprintf("path %q may only have a * at the end", path))
+	}
+	return problems
+}
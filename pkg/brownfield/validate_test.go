@@ -0,0 +1,41 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("test validating prohibited targets", func() {
+
+	Context("Test ValidateProhibitedTargetSpec()", func() {
+		It("should accept the prohibited targets of the fixtures", func() {
+			for _, target := range fixtures.GetAzureIngressProhibitedTargets() {
+				Expect(ValidateProhibitedTargetSpec(target.Spec)).To(BeEmpty())
+			}
+		})
+
+		It("should reject paths which do not begin with a /", func() {
+			spec := ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host, Paths: []string{"api/*", "/web/*"}}
+			Expect(ValidateProhibitedTargetSpec(spec)).To(ConsistOf(`path "api/*" must begin with a /`))
+		})
+
+		It("should reject paths with a * other than at the end", func() {
+			spec := ptv1.AzureIngressProhibitedTargetSpec{Paths: []string{"/api/*/v1"}}
+			Expect(ValidateProhibitedTargetSpec(spec)).To(ConsistOf(`path "/api/*/v1" may only have a * at the end`))
+		})
+
+		It("should reject hostnames with a scheme and ports out of range", func() {
+			spec := ptv1.AzureIngressProhibitedTargetSpec{Hostname: "https://" + tests.Host, Port: 70000}
+			Expect(len(ValidateProhibitedTargetSpec(spec))).To(Equal(2))
+		})
+	})
+})
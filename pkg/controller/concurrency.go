@@ -0,0 +1,79 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+)
+
+// maxPreconditionFailedRetries is the number of times AGIC will re-fetch App Gateway and rebuild
+// its config when a PUT is rejected because the gateway was modified by someone else in the meantime.
+const maxPreconditionFailedRetries = 3
+
+const headerIfMatch = "If-Match"
+
+// createOrUpdateIfMatch PUTs the given App Gateway config only if the ETag of the App Gateway in ARM
+// still matches the given one. ARM responds with 412 Precondition Failed otherwise.
+func (c AppGwIngressController) createOrUpdateIfMatch(ctx context.Context, appGw n.ApplicationGateway, etag string) (n.ApplicationGatewaysCreateOrUpdateFuture, error) {
+	req, err := c.appGwClient.CreateOrUpdatePreparer(ctx, c.appGwIdentifier.ResourceGroup, c.appGwIdentifier.AppGwName, appGw)
+	if err != nil {
+		return n.ApplicationGatewaysCreateOrUpdateFuture{}, autorest.NewErrorWithError(err, "network.ApplicationGatewaysClient", "CreateOrUpdate", nil, "Failure preparing request")
+	}
+
+	if req, err = autorest.Prepare(req, autorest.WithHeader(headerIfMatch, etag)); err != nil {
+		return n.ApplicationGatewaysCreateOrUpdateFuture{}, autorest.NewErrorWithError(err, "network.ApplicationGatewaysClient", "CreateOrUpdate", nil, "Failure preparing request")
+	}
+
+	future, err := c.appGwClient.CreateOrUpdateSender(req)
+	if err != nil {
+		return future, autorest.NewErrorWithError(err, "network.ApplicationGatewaysClient", "CreateOrUpdate", future.Response(), "Failure sending request")
+	}
+	return future, nil
+}
+
+// isPreconditionFailed determines whether ARM rejected a PUT because the ETag we sent is stale.
+func isPreconditionFailed(future n.ApplicationGatewaysCreateOrUpdateFuture, err error) bool {
+	if err == nil {
+		return false
+	}
+	if detailedErr, ok := err.(autorest.DetailedError); ok && detailedErr.StatusCode == http.StatusPreconditionFailed {
+		return true
+	}
+	resp := future.Response()
+	return resp != nil && resp.StatusCode == http.StatusPreconditionFailed
+}
+
+// reportConcurrentModificationRetry records an event on the Ingresses for AGIC when an update of App Gateway was rejected,
+// because the gateway was modified by someone else, and AGIC re-fetches the config to rebuild on top of it.
+func (c AppGwIngressController) reportConcurrentModificationRetry(ingresses []*v1beta1.Ingress) {
+	message := fmt.Sprintf("App Gateway %s was modified by another party while AGIC was updating it; Re-fetching its config and rebuilding, up to %d more times", c.appGwIdentifier.AppGwName, maxPreconditionFailedRetries)
+	c.recordOnApplicationGatewayIngresses(ingresses, v1.EventTypeNormal, message)
+}
+
+// reportConcurrentModification records a warning on the Ingresses for AGIC once all attempts to update App Gateway were
+// rejected, because the gateway was modified by someone else every time.
+func (c AppGwIngressController) reportConcurrentModification(ingresses []*v1beta1.Ingress) {
+	message := fmt.Sprintf("App Gateway %s was modified by another party during each of the %d attempts of AGIC to update it; The config will be applied with the next change", c.appGwIdentifier.AppGwName, maxPreconditionFailedRetries+1)
+	c.recordOnApplicationGatewayIngresses(ingresses, v1.EventTypeWarning, message)
+}
+
+func (c AppGwIngressController) recordOnApplicationGatewayIngresses(ingresses []*v1beta1.Ingress, eventType string, message string) {
+	for _, ingress := range ingresses {
+		if k8scontext.IsIngressApplicationGateway(ingress) {
+			c.recorder.Event(ingress, eventType, events.ReasonAppGatewayModifiedConcurrently, message)
+		}
+	}
+}
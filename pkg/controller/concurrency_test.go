@@ -0,0 +1,121 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("optimistic concurrency tests", func() {
+	var controller *AppGwIngressController
+	var requests []*http.Request
+
+	respondWith := func(statusCode int) autorest.SenderFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req)
+			return &http.Response{
+				StatusCode: statusCode,
+				Request:    req,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+			}, nil
+		}
+	}
+
+	BeforeEach(func() {
+		requests = nil
+		controller = &AppGwIngressController{
+			appGwClient: n.NewApplicationGatewaysClient("--subscription--"),
+			appGwIdentifier: appgw.Identifier{
+				SubscriptionID: "--subscription--",
+				ResourceGroup:  "--resource-group--",
+				AppGwName:      "--app-gw-name--",
+			},
+		}
+	})
+
+	Context("ensure createOrUpdateIfMatch sends the ETag", func() {
+		It("sets the If-Match header", func() {
+			controller.appGwClient.Sender = respondWith(http.StatusOK)
+			_, err := controller.createOrUpdateIfMatch(context.Background(), fixtures.GetAppGateway(), "W/\"etag\"")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(requests)).To(Equal(1))
+			Expect(requests[0].Method).To(Equal(http.MethodPut))
+			Expect(requests[0].Header.Get(headerIfMatch)).To(Equal("W/\"etag\""))
+		})
+
+		It("reports a stale ETag as a failed precondition", func() {
+			controller.appGwClient.Sender = respondWith(http.StatusPreconditionFailed)
+			future, err := controller.createOrUpdateIfMatch(context.Background(), fixtures.GetAppGateway(), "W/\"etag\"")
+			Expect(err).To(HaveOccurred())
+			Expect(isPreconditionFailed(future, err)).To(BeTrue())
+		})
+	})
+
+	Context("ensure isPreconditionFailed works as expected", func() {
+		It("ignores successful requests", func() {
+			Expect(isPreconditionFailed(n.ApplicationGatewaysCreateOrUpdateFuture{}, nil)).To(BeFalse())
+		})
+
+		It("ignores other errors", func() {
+			err := autorest.DetailedError{StatusCode: http.StatusBadRequest}
+			Expect(isPreconditionFailed(n.ApplicationGatewaysCreateOrUpdateFuture{}, err)).To(BeFalse())
+		})
+
+		It("detects 412 errors", func() {
+			err := autorest.DetailedError{StatusCode: http.StatusPreconditionFailed}
+			Expect(isPreconditionFailed(n.ApplicationGatewaysCreateOrUpdateFuture{}, err)).To(BeTrue())
+		})
+	})
+
+	Context("ensure reportConcurrentModification works as expected", func() {
+		It("records a single warning on each Ingress for AGIC", func() {
+			controller.recorder = record.NewFakeRecorder(10)
+			otherIngress := tests.NewIngressFixture()
+			otherIngress.Annotations[annotations.IngressClassKey] = "nginx"
+			controller.reportConcurrentModification([]*v1beta1.Ingress{tests.NewIngressFixture(), otherIngress})
+
+			recorded := controller.recorder.(*record.FakeRecorder).Events
+			Expect(recorded).To(HaveLen(1))
+			event := <-recorded
+			Expect(event).To(ContainSubstring(events.ReasonAppGatewayModifiedConcurrently))
+			Expect(event).To(ContainSubstring("4 attempts"))
+		})
+	})
+
+	Context("ensure reportConcurrentModificationRetry works as expected", func() {
+		It("records a single normal event on each Ingress for AGIC", func() {
+			controller.recorder = record.NewFakeRecorder(10)
+			otherIngress := tests.NewIngressFixture()
+			otherIngress.Annotations[annotations.IngressClassKey] = "nginx"
+			controller.reportConcurrentModificationRetry([]*v1beta1.Ingress{tests.NewIngressFixture(), otherIngress})
+
+			recorded := controller.recorder.(*record.FakeRecorder).Events
+			Expect(recorded).To(HaveLen(1))
+			event := <-recorded
+			Expect(event).To(HavePrefix(v1.EventTypeNormal))
+			Expect(event).To(ContainSubstring(events.ReasonAppGatewayModifiedConcurrently))
+			Expect(event).To(ContainSubstring("Re-fetching"))
+		})
+	})
+})
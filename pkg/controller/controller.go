@@ -14,6 +14,8 @@ import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/snapshot"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/worker"
 )
 
@@ -28,21 +30,38 @@ type AppGwIngressController struct {
 
 	configCache *[]byte
 
+	// rejectedConfigHash is the hash of the last generated config, which failed to deploy and was rolled back; Empty when there is none.
+	rejectedConfigHash *string
+
+	// reportedDrift describes the drift found by the last drift check; Empty when there was none.
+	reportedDrift *string
+
+	// snapshots keeps the last known good App Gateway configs; nil when snapshots are disabled.
+	snapshots *snapshot.Store
+
+	// manifests keeps the list of App Gateway sub-resources AGIC created; nil when ownership is not tracked.
+	manifests *ownership.Store
+
 	recorder record.EventRecorder
 
 	stopChannel chan struct{}
 }
 
 // NewAppGwIngressController constructs a controller object.
-func NewAppGwIngressController(appGwClient n.ApplicationGatewaysClient, appGwIdentifier appgw.Identifier, k8sContext *k8scontext.Context, recorder record.EventRecorder) *AppGwIngressController {
+func NewAppGwIngressController(appGwClient n.ApplicationGatewaysClient, appGwIdentifier appgw.Identifier, k8sContext *k8scontext.Context, recorder record.EventRecorder, snapshots *snapshot.Store, manifests *ownership.Store) *AppGwIngressController {
 	controller := &AppGwIngressController{
 		appGwClient:     appGwClient,
 		appGwIdentifier: appGwIdentifier,
 		k8sContext:      k8sContext,
 		recorder:        recorder,
+		snapshots:       snapshots,
+		manifests:       manifests,
 		configCache:     to.ByteSlicePtr([]byte{}),
 		ipAddressMap:    map[string]k8scontext.IPAddress{},
 		stopChannel:     make(chan struct{}),
+
+		rejectedConfigHash: to.StringPtr(""),
+		reportedDrift:      to.StringPtr(""),
 	}
 
 	controller.worker = &worker.Worker{
@@ -56,7 +75,7 @@ func NewAppGwIngressController(appGwClient n.ApplicationGatewaysClient, appGwIde
 func (c *AppGwIngressController) Start(envVariables environment.EnvVariables) error {
 	// Starts k8scontext which contains all the informers
 	// This will start individual go routines for informers
-	if err := c.k8sContext.Run(c.stopChannel, false, envVariables); err != nil {
+	if err := c.k8sContext.Run(c.stopChannel, envVariables); err != nil {
 		glog.Error("Could not start Kubernetes Context: ", err)
 		return err
 	}
@@ -64,6 +83,11 @@ func (c *AppGwIngressController) Start(envVariables environment.EnvVariables) er
 	// Starts Worker processing events from k8sContext
 	go c.worker.Run(c.k8sContext.UpdateChannel, c.stopChannel)
 
+	// Periodically compare App Gateway with the config AGIC applied
+	if envVariables.DriftDetectionMode != "" {
+		go c.scheduleDriftChecks(envVariables.GetDriftDetectionInterval())
+	}
+
 	select {}
 }
 
@@ -0,0 +1,135 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metrics"
+)
+
+const (
+	guardedListeners = "listeners"
+	guardedRules     = "rules"
+	guardedPools     = "pools"
+
+	// MassDeletionOverrideName is the name of the ConfigMap in the namespace of AGIC, which lets AGIC apply the next config
+	// the deletion guard rejects. AGIC deletes the ConfigMap as it applies the config; Operators create it for each override.
+	MassDeletionOverrideName = "agic-allow-mass-deletion"
+)
+
+// guardedResourceTypes are the App Gateway sub-resources protected by the deletion guard.
+var guardedResourceTypes = []string{guardedListeners, guardedRules, guardedPools}
+
+// managedResources is the set of names of the App Gateway sub-resources managed by AGIC; keyed by resource type.
+type managedResources map[string]map[string]interface{}
+
+// getManagedResources lists the listeners, rules and pools of the given App Gateway, which are not blacklisted by prohibited or managed targets.
+func getManagedResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, managedTargets []*mtv1.AzureIngressManagedTarget) managedResources {
+	resources := make(managedResources)
+	for _, resourceType := range guardedResourceTypes {
+		resources[resourceType] = make(map[string]interface{})
+	}
+	if appGw.ApplicationGatewayPropertiesFormat == nil {
+		return resources
+	}
+
+	er := brownfield.NewExistingResources(appGw, prohibitedTargets, managedTargets, nil)
+	_, listeners := er.GetBlacklistedListeners()
+	for _, listener := range listeners {
+		resources[guardedListeners][*listener.Name] = nil
+	}
+	_, rules := er.GetBlacklistedRoutingRules()
+	for _, rule := range rules {
+		resources[guardedRules][*rule.Name] = nil
+	}
+	_, pools := er.GetBlacklistedPools()
+	for _, pool := range pools {
+		resources[guardedPools][*pool.Name] = nil
+	}
+	return resources
+}
+
+// checkDeletionGuard refuses App Gateway configs, which delete more AGIC managed listeners, rules or pools
+// than APPGW_DELETION_GUARD_MAX_PERCENT or APPGW_DELETION_GUARD_MAX_COUNT allow.
+// An empty informer cache, for instance, would otherwise wipe all routes from App Gateway.
+func (c AppGwIngressController) checkDeletionGuard(existing managedResources, generated managedResources, cbCtx *appgw.ConfigBuilderContext) error {
+	maxPercent, percentErr := strconv.Atoi(cbCtx.EnvVariables.DeletionGuardMaxPercent)
+	maxCount, countErr := strconv.Atoi(cbCtx.EnvVariables.DeletionGuardMaxCount)
+	if percentErr != nil && countErr != nil {
+		// Deletion guard is not enabled
+		return nil
+	}
+
+	var violations []string
+	deletions := make(map[string]int)
+	for _, resourceType := range guardedResourceTypes {
+		for name := range existing[resourceType] {
+			if _, exists := generated[resourceType][name]; !exists {
+				deletions[resourceType]++
+			}
+		}
+		deleted, total := deletions[resourceType], len(existing[resourceType])
+		if deleted == 0 {
+			continue
+		}
+		if (countErr == nil && deleted > maxCount) || (percentErr == nil && deleted*100 > maxPercent*total) {
+			violations = append(violations, fmt.Sprintf("%d of %d %s", deleted, total, resourceType))
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if cbCtx.EnvVariables.AllowMassDeletion == "true" {
+		glog.Warningf("Deletion guard bypassed by %s: deleting %s", environment.AllowMassDeletionVarName, strings.Join(violations, ", "))
+		return nil
+	}
+	if c.consumeMassDeletionOverride(cbCtx.EnvVariables.AGICPodNamespace) {
+		glog.Warningf("Deletion guard bypassed once by ConfigMap %s/%s: deleting %s", cbCtx.EnvVariables.AGICPodNamespace, MassDeletionOverrideName, strings.Join(violations, ", "))
+		return nil
+	}
+
+	metrics.DeletionGuardBlocked.Add(1)
+	metrics.DeletionGuardDeletions.Init()
+	for resourceType, deleted := range deletions {
+		metrics.DeletionGuardDeletions.Add(resourceType, int64(deleted))
+	}
+
+	message := fmt.Sprintf("Refusing to update App Gateway %s as it would delete %s; Create ConfigMap %s in the namespace of AGIC to allow this once, or set %s to \"true\"",
+		c.appGwIdentifier.AppGwName, strings.Join(violations, ", "), MassDeletionOverrideName, environment.AllowMassDeletionVarName)
+	glog.Warning(message)
+	for _, ingress := range cbCtx.IngressList {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonMassDeletionBlocked, message)
+	}
+	return ErrMassDeletion
+}
+
+// consumeMassDeletionOverride deletes the one-shot override of the deletion guard; True when the operator had created it.
+func (c AppGwIngressController) consumeMassDeletionOverride(namespace string) bool {
+	if namespace == "" || c.k8sContext == nil {
+		return false
+	}
+	deleted, err := c.k8sContext.DeleteConfigMap(namespace, MassDeletionOverrideName)
+	if err != nil {
+		glog.Error("Unable to consume the override of the deletion guard: ", err)
+		return false
+	}
+	return deleted
+}
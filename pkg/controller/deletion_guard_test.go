@@ -0,0 +1,122 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gateway_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
+	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("deletion guard tests", func() {
+	var controller *AppGwIngressController
+	var recorder *record.FakeRecorder
+	var cbCtx *appgw.ConfigBuilderContext
+	var existing managedResources
+	var generated managedResources
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(100)
+		controller = &AppGwIngressController{
+			recorder: recorder,
+		}
+		cbCtx = &appgw.ConfigBuilderContext{
+			IngressList: []*v1beta1.Ingress{
+				tests.NewIngressFixture(),
+			},
+			EnvVariables: environment.GetFakeEnv(),
+		}
+
+		existing = getManagedResources(fixtures.GetAppGateway(), nil, nil)
+
+		// A config with the default listener and rule only
+		appGw := fixtures.GetAppGateway()
+		appGw.HTTPListeners = &[]n.ApplicationGatewayHTTPListener{*fixtures.GetDefaultListener()}
+		appGw.RequestRoutingRules = &[]n.ApplicationGatewayRequestRoutingRule{*fixtures.GetDefaultRoutingRule()}
+		generated = getManagedResources(appGw, nil, nil)
+	})
+
+	Context("ensure getManagedResources works as expected", func() {
+		It("lists all listeners and rules without prohibited targets", func() {
+			Expect(len(existing[guardedListeners])).To(Equal(5))
+			Expect(len(existing[guardedRules])).To(Equal(3))
+		})
+
+		It("skips resources blacklisted by prohibited targets", func() {
+			managed := getManagedResources(fixtures.GetAppGateway(), fixtures.GetAzureIngressProhibitedTargets(), nil)
+			Expect(len(managed[guardedListeners])).To(BeNumerically("<", 5))
+		})
+	})
+
+	Context("ensure checkDeletionGuard works as expected", func() {
+		It("allows everything when the guard is not configured", func() {
+			Expect(controller.checkDeletionGuard(existing, generated, cbCtx)).ToNot(HaveOccurred())
+		})
+
+		It("allows deletions within the limits", func() {
+			cbCtx.EnvVariables.DeletionGuardMaxPercent = "80"
+			cbCtx.EnvVariables.DeletionGuardMaxCount = "4"
+			Expect(controller.checkDeletionGuard(existing, generated, cbCtx)).ToNot(HaveOccurred())
+		})
+
+		It("refuses deleting more than the allowed percentage", func() {
+			cbCtx.EnvVariables.DeletionGuardMaxPercent = "50"
+			Expect(controller.checkDeletionGuard(existing, generated, cbCtx)).To(Equal(ErrMassDeletion))
+			Expect(len(recorder.Events)).To(Equal(1))
+		})
+
+		It("refuses deleting more than the allowed count", func() {
+			cbCtx.EnvVariables.DeletionGuardMaxCount = "3"
+			Expect(controller.checkDeletionGuard(existing, generated, cbCtx)).To(Equal(ErrMassDeletion))
+		})
+
+		It("can be bypassed with the environment variable", func() {
+			cbCtx.EnvVariables.DeletionGuardMaxCount = "3"
+			cbCtx.EnvVariables.AllowMassDeletion = "true"
+			Expect(controller.checkDeletionGuard(existing, generated, cbCtx)).ToNot(HaveOccurred())
+		})
+
+		It("can be bypassed once with the ConfigMap in the namespace of AGIC", func() {
+			kubeClient := testclient.NewSimpleClientset(&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: tests.Namespace, Name: MassDeletionOverrideName},
+			})
+			controller.k8sContext = k8scontext.NewContext(kubeClient, fake.NewSimpleClientset(), istio_fake.NewSimpleClientset(), gateway_fake.NewSimpleClientset(), []string{tests.Namespace}, nil, 1000*time.Second)
+			cbCtx.EnvVariables.DeletionGuardMaxCount = "3"
+			cbCtx.EnvVariables.AGICPodNamespace = tests.Namespace
+
+			Expect(controller.checkDeletionGuard(existing, generated, cbCtx)).ToNot(HaveOccurred())
+			Expect(recorder.Events).To(BeEmpty())
+			_, err := kubeClient.CoreV1().ConfigMaps(tests.Namespace).Get(MassDeletionOverrideName, metav1.GetOptions{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+			Expect(controller.checkDeletionGuard(existing, generated, cbCtx)).To(Equal(ErrMassDeletion))
+		})
+
+		It("cannot be bypassed by the Ingresses", func() {
+			cbCtx.EnvVariables.DeletionGuardMaxCount = "3"
+			cbCtx.IngressList[0].Annotations[annotations.ApplicationGatewayPrefix+"/allow-mass-deletion"] = "true"
+			Expect(controller.checkDeletionGuard(existing, generated, cbCtx)).To(Equal(ErrMassDeletion))
+		})
+	})
+})
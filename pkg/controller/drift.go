@@ -0,0 +1,344 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metrics"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/snapshot"
+)
+
+// keysToIgnoreForDrift are the JSON keys ARM manages, or never returns, which must not be considered drift.
+var keysToIgnoreForDrift = map[string]interface{}{
+	"etag":              nil,
+	"type":              nil,
+	"provisioningState": nil,
+	"data":              nil,
+	"password":          nil,
+	"publicCertData":    nil,
+}
+
+// resourceDrift lists the sub-resources of a given type, which differ from the config AGIC last applied.
+type resourceDrift struct {
+	collection string
+	missing    []string
+	added      []string
+	modified   []string
+}
+
+func (d resourceDrift) String() string {
+	var changes []string
+	if len(d.missing) > 0 {
+		changes = append(changes, fmt.Sprintf("missing [%s]", strings.Join(d.missing, ", ")))
+	}
+	if len(d.added) > 0 {
+		changes = append(changes, fmt.Sprintf("added [%s]", strings.Join(d.added, ", ")))
+	}
+	if len(d.modified) > 0 {
+		changes = append(changes, fmt.Sprintf("modified [%s]", strings.Join(d.modified, ", ")))
+	}
+	return fmt.Sprintf("%s: %s", d.collection, strings.Join(changes, ", "))
+}
+
+// scheduleDriftChecks periodically enqueues a DriftCheck event; Drift checks are processed by the worker
+// along with Kubernetes events so they never run concurrently with an App Gateway update.
+func (c *AppGwIngressController) scheduleDriftChecks(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.k8sContext.UpdateChannel.In() <- events.Event{
+				Type: events.DriftCheck,
+			}
+		case <-c.stopChannel:
+			return
+		}
+	}
+}
+
+// checkDrift compares App Gateway with the config AGIC last applied and reports the delta.
+// In enforce mode AGIC re-applies its config to revert the changes.
+func (c AppGwIngressController) checkDrift(event events.Event) error {
+	if c.configCache == nil || len(*c.configCache) == 0 {
+		glog.V(5).Info("[drift] AGIC has not applied a config yet; Skipping drift check.")
+		return nil
+	}
+
+	appGw, err := c.appGwClient.Get(context.Background(), c.appGwIdentifier.ResourceGroup, c.appGwIdentifier.AppGwName)
+	if err != nil {
+		glog.Errorf("unable to get specified AppGateway [%v], check AppGateway identifier, error=[%v]", c.appGwIdentifier.AppGwName, err.Error())
+		return ErrFetchingAppGatewayConfig
+	}
+
+	envVars := environment.GetEnv()
+	var prohibitedTargets []*ptv1.AzureIngressProhibitedTarget
+	var managedTargets []*mtv1.AzureIngressManagedTarget
+	if envVars.EnableBrownfieldDeployment == "true" {
+		prohibitedTargets = c.k8sContext.ListAzureProhibitedTargets()
+		managedTargets = c.k8sContext.ListAzureManagedTargets()
+	}
+
+	drift, err := getConfigDrift(*c.configCache, appGw, prohibitedTargets, managedTargets)
+	if err != nil {
+		glog.Error("[drift] Could not compare App Gateway with the last applied config: ", err)
+		return err
+	}
+
+	metrics.DriftChecks.Add(1)
+	metrics.DriftedResources.Init()
+	if len(drift) == 0 {
+		c.isNewDrift("")
+		glog.V(3).Info("[drift] App Gateway matches the last applied config.")
+		return nil
+	}
+
+	metrics.DriftDetected.Add(1)
+	var changes []string
+	for _, d := range drift {
+		metrics.DriftedResources.Add(d.collection+"/missing", int64(len(d.missing)))
+		metrics.DriftedResources.Add(d.collection+"/added", int64(len(d.added)))
+		metrics.DriftedResources.Add(d.collection+"/modified", int64(len(d.modified)))
+		changes = append(changes, d.String())
+	}
+
+	enforce := envVars.DriftDetectionMode == environment.DriftDetectionModeEnforce
+	isNew := c.isNewDrift(strings.Join(changes, "; "))
+	if !isNew && !enforce {
+		// AGIC does not revert the drift in observe mode; Report it again only once it changes.
+		glog.V(3).Infof("[drift] App Gateway %s still differs from the last applied config as reported before", c.appGwIdentifier.AppGwName)
+		return nil
+	}
+
+	message := fmt.Sprintf("App Gateway %s was modified outside of AGIC; %s", c.appGwIdentifier.AppGwName, strings.Join(changes, "; "))
+	if enforce {
+		message += "; AGIC will revert these changes"
+	}
+	glog.Warning(message)
+	for _, ingress := range c.k8sContext.ListHTTPIngresses() {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonAppGatewayConfigDrift, message)
+	}
+
+	if !enforce {
+		return nil
+	}
+
+	// Invalidate the cache so that AGIC PUTs its config even though it has not changed.
+	*c.configCache = []byte{}
+	if err := c.reconcile(event); err != nil {
+		return err
+	}
+	metrics.DriftReverted.Add(1)
+	return nil
+}
+
+// isNewDrift remembers the drift found by a check and determines whether it differs from the one found by the check before.
+func (c AppGwIngressController) isNewDrift(drift string) bool {
+	if c.reportedDrift == nil {
+		return true
+	}
+	isNew := drift != *c.reportedDrift
+	*c.reportedDrift = drift
+	return isNew
+}
+
+// getConfigDrift compares the given App Gateway with the JSON of the config AGIC last applied.
+// A sub-resource is considered modified only when a property AGIC set has a different value; properties populated by ARM are ignored.
+// Sub-resources blacklisted by prohibited or managed targets are not managed by AGIC and are skipped.
+func getConfigDrift(appliedJSON []byte, actual n.ApplicationGateway, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, managedTargets []*mtv1.AzureIngressManagedTarget) ([]resourceDrift, error) {
+	var applied n.ApplicationGateway
+	if err := applied.UnmarshalJSON(appliedJSON); err != nil {
+		return nil, err
+	}
+
+	actualJSON, err := actual.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	appliedProperties, err := getPropertiesJSON(appliedJSON)
+	if err != nil {
+		return nil, err
+	}
+	actualProperties, err := getPropertiesJSON(actualJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	blacklisted := getBlacklistedNames(applied, prohibitedTargets, managedTargets)
+	for collection, names := range getBlacklistedNames(actual, prohibitedTargets, managedTargets) {
+		for name := range names {
+			blacklisted[collection][name] = nil
+		}
+	}
+
+	var drift []resourceDrift
+	for _, collection := range snapshot.Collections {
+		appliedByName := indexJSONByName(appliedProperties[collection])
+		actualByName := indexJSONByName(actualProperties[collection])
+		d := resourceDrift{collection: collection}
+		for name, appliedResource := range appliedByName {
+			if _, exists := blacklisted[collection][name]; exists {
+				continue
+			}
+			actualResource, exists := actualByName[name]
+			if !exists {
+				d.missing = append(d.missing, name)
+			} else if !jsonContains(actualResource, appliedResource, "") {
+				d.modified = append(d.modified, name)
+			}
+		}
+		for name := range actualByName {
+			if _, exists := blacklisted[collection][name]; exists {
+				continue
+			}
+			// AGIC keeps all existing certificates in brownfield deployments.
+			if collection == "sslCertificates" && len(prohibitedTargets)+len(managedTargets) > 0 {
+				continue
+			}
+			if _, exists := appliedByName[name]; !exists {
+				d.added = append(d.added, name)
+			}
+		}
+		if len(d.missing)+len(d.added)+len(d.modified) == 0 {
+			continue
+		}
+		sort.Strings(d.missing)
+		sort.Strings(d.added)
+		sort.Strings(d.modified)
+		drift = append(drift, d)
+	}
+	return drift, nil
+}
+
+func getPropertiesJSON(appGwJSON []byte) (map[string]interface{}, error) {
+	var appGw map[string]interface{}
+	if err := json.Unmarshal(appGwJSON, &appGw); err != nil {
+		return nil, err
+	}
+	properties, _ := appGw["properties"].(map[string]interface{})
+	return properties, nil
+}
+
+func indexJSONByName(collection interface{}) map[string]interface{} {
+	indexed := make(map[string]interface{})
+	resources, _ := collection.([]interface{})
+	for _, resource := range resources {
+		if resourceMap, ok := resource.(map[string]interface{}); ok {
+			if name, ok := resourceMap["name"].(string); ok {
+				indexed[name] = resource
+			}
+		}
+	}
+	return indexed
+}
+
+// jsonContains determines whether every value in the expected JSON exists with the same value in the actual JSON.
+func jsonContains(actual interface{}, expected interface{}, key string) bool {
+	switch expectedValue := expected.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		actualValue, _ := actual.(map[string]interface{})
+		for k, v := range expectedValue {
+			if _, ignore := keysToIgnoreForDrift[k]; ignore {
+				continue
+			}
+			if !jsonContains(actualValue[k], v, k) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		actualValue, _ := actual.([]interface{})
+		if len(actualValue) != len(expectedValue) {
+			return false
+		}
+		for idx := range expectedValue {
+			if !jsonContains(actualValue[idx], expectedValue[idx], key) {
+				return false
+			}
+		}
+		return true
+	case string:
+		actualValue, _ := actual.(string)
+		// ARM does not preserve the casing of resource IDs.
+		if key == "id" {
+			return strings.EqualFold(actualValue, expectedValue)
+		}
+		return actualValue == expectedValue
+	default:
+		return reflect.DeepEqual(actual, expected)
+	}
+}
+
+// getBlacklistedNames returns the names of the sub-resources AGIC must not touch; keyed by the JSON name of the collection.
+func getBlacklistedNames(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, managedTargets []*mtv1.AzureIngressManagedTarget) map[string]map[string]interface{} {
+	blacklisted := make(map[string]map[string]interface{})
+	for _, collection := range snapshot.Collections {
+		blacklisted[collection] = make(map[string]interface{})
+	}
+	if len(prohibitedTargets)+len(managedTargets) == 0 || appGw.ApplicationGatewayPropertiesFormat == nil {
+		return blacklisted
+	}
+
+	add := func(collection string, name *string) {
+		if name != nil {
+			blacklisted[collection][*name] = nil
+		}
+	}
+
+	er := brownfield.NewExistingResources(appGw, prohibitedTargets, managedTargets, nil)
+	pools, _ := er.GetBlacklistedPools()
+	for _, pool := range pools {
+		add("backendAddressPools", pool.Name)
+	}
+	settings, _ := er.GetBlacklistedHTTPSettings()
+	for _, setting := range settings {
+		add("backendHttpSettingsCollection", setting.Name)
+	}
+	probes, _ := er.GetBlacklistedProbes()
+	for _, probe := range probes {
+		add("probes", probe.Name)
+	}
+	ports, _ := er.GetBlacklistedPorts()
+	for _, port := range ports {
+		add("frontendPorts", port.Name)
+	}
+	listeners, _ := er.GetBlacklistedListeners()
+	for _, listener := range listeners {
+		add("httpListeners", listener.Name)
+	}
+	redirects, _ := er.GetBlacklistedRedirects()
+	for _, redirect := range redirects {
+		add("redirectConfigurations", redirect.Name)
+	}
+	pathMaps, _ := er.GetBlacklistedPathMaps()
+	for _, pathMap := range pathMaps {
+		add("urlPathMaps", pathMap.Name)
+	}
+	rules, _ := er.GetBlacklistedRoutingRules()
+	for _, rule := range rules {
+		add("requestRoutingRules", rule.Name)
+	}
+	return blacklisted
+}
@@ -0,0 +1,144 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("drift detection tests", func() {
+	var applied n.ApplicationGateway
+	var appliedJSON []byte
+
+	getAppGateway := func() n.ApplicationGateway {
+		appGw := fixtures.GetAppGateway()
+		appGw.BackendAddressPools = &[]n.ApplicationGatewayBackendAddressPool{
+			fixtures.GetBackendPool1(),
+			fixtures.GetBackendPool2(),
+		}
+		return appGw
+	}
+
+	BeforeEach(func() {
+		applied = getAppGateway()
+		var err error
+		appliedJSON, err = applied.MarshalJSON()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("ensure getConfigDrift works as expected", func() {
+		It("finds no drift when App Gateway matches the applied config", func() {
+			drift, err := getConfigDrift(appliedJSON, getAppGateway(), nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift).To(BeEmpty())
+		})
+
+		It("ignores properties populated by ARM", func() {
+			actual := getAppGateway()
+			(*actual.BackendAddressPools)[0].Etag = to.StringPtr("W/\"etag\"")
+			(*actual.BackendAddressPools)[0].ProvisioningState = to.StringPtr("Succeeded")
+			(*actual.BackendAddressPools)[0].ID = to.StringPtr("/subscriptions/xyz")
+			applied := getAppGateway()
+			(*applied.BackendAddressPools)[0].ID = to.StringPtr("/SUBSCRIPTIONS/XYZ")
+			appliedJSON, _ := applied.MarshalJSON()
+
+			drift, err := getConfigDrift(appliedJSON, actual, nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift).To(BeEmpty())
+		})
+
+		It("reports missing, added and modified sub-resources", func() {
+			actual := getAppGateway()
+			actual.BackendAddressPools = &[]n.ApplicationGatewayBackendAddressPool{
+				fixtures.GetBackendPool1(),
+				fixtures.GetBackendPool3(),
+			}
+			(*actual.Probes)[0].Path = to.StringPtr("/changed")
+
+			drift, err := getConfigDrift(appliedJSON, actual, nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift).To(Equal([]resourceDrift{
+				{
+					collection: "backendAddressPools",
+					missing:    []string{fixtures.BackendAddressPoolName2},
+					added:      []string{fixtures.BackendAddressPoolName3},
+				},
+				{
+					collection: "probes",
+					modified:   []string{*(*actual.Probes)[0].Name},
+				},
+			}))
+			Expect(drift[0].String()).To(Equal("backendAddressPools: missing [BackendAddressPool-2], added [BackendAddressPool-3]"))
+		})
+
+		It("ignores sub-resources blacklisted by prohibited targets", func() {
+			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()
+			blacklisted, _ := brownfield.NewExistingResources(applied, prohibitedTargets, nil, nil).GetBlacklistedListeners()
+			Expect(blacklisted).ToNot(BeEmpty())
+
+			actual := getAppGateway()
+			for idx, listener := range *actual.HTTPListeners {
+				if *listener.Name == *blacklisted[0].Name {
+					(*actual.HTTPListeners)[idx].RequireServerNameIndication = to.BoolPtr(false)
+				}
+			}
+
+			drift, err := getConfigDrift(appliedJSON, actual, prohibitedTargets, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift).To(BeEmpty())
+
+			drift, err = getConfigDrift(appliedJSON, actual, nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift).To(Equal([]resourceDrift{
+				{
+					collection: "httpListeners",
+					modified:   []string{*blacklisted[0].Name},
+				},
+			}))
+		})
+	})
+
+	Context("ensure isNewDrift works as expected", func() {
+		It("reports the same drift once", func() {
+			controller := AppGwIngressController{reportedDrift: to.StringPtr("")}
+			Expect(controller.isNewDrift("probes: modified [probe-1]")).To(BeTrue())
+			Expect(controller.isNewDrift("probes: modified [probe-1]")).To(BeFalse())
+			Expect(controller.isNewDrift("probes: modified [probe-1, probe-2]")).To(BeTrue())
+		})
+
+		It("reports a drift again once App Gateway matched the applied config in between", func() {
+			controller := AppGwIngressController{reportedDrift: to.StringPtr("")}
+			Expect(controller.isNewDrift("probes: modified [probe-1]")).To(BeTrue())
+			Expect(controller.isNewDrift("")).To(BeTrue())
+			Expect(controller.isNewDrift("probes: modified [probe-1]")).To(BeTrue())
+		})
+	})
+
+	Context("ensure checkDrift works as expected", func() {
+		It("skips the check until AGIC applied a config", func() {
+			client := n.NewApplicationGatewaysClient("--subscription--")
+			client.Sender = autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("App Gateway must not be fetched")
+			})
+			controller := &AppGwIngressController{
+				appGwClient: client,
+				configCache: to.ByteSlicePtr([]byte{}),
+			}
+			Expect(controller.Process(events.Event{Type: events.DriftCheck})).ToNot(HaveOccurred())
+		})
+	})
+})
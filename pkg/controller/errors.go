@@ -10,4 +10,8 @@ import "errors"
 var (
 	ErrFetchingAppGatewayConfig  = errors.New("unable to get specified AppGateway")
 	ErrDeployingAppGatewayConfig = errors.New("unable to deploy App Gateway config")
+	ErrPreconditionFailed        = errors.New("App Gateway config was modified since it was fetched")
+	ErrMassDeletion              = errors.New("App Gateway config would delete more resources than the deletion guard allows")
+	ErrSavingOwnership           = errors.New("unable to save the ownership manifest of the applied App Gateway config")
+	ErrLoadingOwnership          = errors.New("unable to load the ownership manifest of App Gateway")
 )
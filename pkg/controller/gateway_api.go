@@ -0,0 +1,89 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"reflect"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/golang/glog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gwv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/gatewayapi"
+)
+
+// translateGatewayAPI translates the Gateways and HTTPRoutes of the Gateway API into the Ingresses AGIC builds the App Gateway config from.
+func (c AppGwIngressController) translateGatewayAPI() *gatewayapi.Translation {
+	return gatewayapi.Translate(gatewayapi.Resources{
+		GatewayClasses: c.k8sContext.ListGatewayClasses(),
+		Gateways:       c.k8sContext.ListGateways(),
+		HTTPRoutes:     c.k8sContext.ListHTTPRoutes(),
+		Services:       c.k8sContext.ListServices(),
+		Namespaces:     c.k8sContext.ListNamespaces(),
+		HasCertificate: func(secretKey string) bool {
+			return c.k8sContext.CertificateSecretStore.GetPfxCertificate(secretKey) != nil
+		},
+	})
+}
+
+// updateGatewayAPIStatus writes the conditions of the translation back onto the GatewayClasses, Gateways and HTTPRoutes; The error is
+// the failure to apply the App Gateway config, if any. Statuses, which did not change, are not written.
+func (c AppGwIngressController) updateGatewayAPIStatus(appGw *n.ApplicationGateway, translation *gatewayapi.Translation, envVariables environment.EnvVariables, programErr error) {
+	if translation == nil {
+		return
+	}
+	now := metav1.Now()
+
+	for _, class := range translation.ClassStatuses(now) {
+		if reflect.DeepEqual(class.Class.Status, class.Status) {
+			continue
+		}
+		glog.V(5).Infof("Updating status of GatewayClass %s", class.Class.Name)
+		if err := c.k8sContext.UpdateGatewayClassStatus(*class.Class, class.Status); err != nil {
+			glog.Error(err)
+		}
+	}
+
+	for _, gateway := range translation.GatewayStatuses(programErr, c.getGatewayAddresses(appGw, envVariables), now) {
+		if reflect.DeepEqual(gateway.Gateway.Status, gateway.Status) {
+			continue
+		}
+		glog.V(5).Infof("Updating status of Gateway %s/%s", gateway.Gateway.Namespace, gateway.Gateway.Name)
+		if err := c.k8sContext.UpdateGatewayStatus(*gateway.Gateway, gateway.Status); err != nil {
+			glog.Error(err)
+		}
+	}
+
+	for _, route := range translation.RouteStatuses(now) {
+		if reflect.DeepEqual(route.Route.Status, route.Status) {
+			continue
+		}
+		glog.V(5).Infof("Updating status of HTTPRoute %s/%s", route.Route.Namespace, route.Route.Name)
+		if err := c.k8sContext.UpdateHTTPRouteStatus(*route.Route, route.Status); err != nil {
+			glog.Error(err)
+		}
+	}
+}
+
+// getGatewayAddresses returns the frontend IP address of App Gateway, which AGIC reports on Ingresses as well.
+func (c AppGwIngressController) getGatewayAddresses(appGw *n.ApplicationGateway, envVariables environment.EnvVariables) []gwv1.GatewayAddress {
+	if appGw == nil || appGw.ApplicationGatewayPropertiesFormat == nil || appGw.FrontendIPConfigurations == nil {
+		return nil
+	}
+	ipConf := appgw.LookupIPConfigurationByType(appGw.FrontendIPConfigurations, envVariables.UsePrivateIP == "true")
+	if ipConf == nil {
+		return nil
+	}
+	ipAddress, exists := c.ipAddressMap[*ipConf.ID]
+	if !exists {
+		return nil
+	}
+	addressType := "IPAddress"
+	return []gwv1.GatewayAddress{{Type: &addressType, Value: string(ipAddress)}}
+}
@@ -7,6 +7,7 @@ package controller
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -24,22 +25,65 @@ var keysToDeleteForCache = []string{
 }
 
 func (c *AppGwIngressController) updateCache(appGw *n.ApplicationGateway) {
+	if c.configCache == nil {
+		glog.Error("Config cache is not initialized; Not caching the App Gwy config.")
+		return
+	}
 	jsonConfig, err := appGw.MarshalJSON()
 	if err != nil {
 		glog.Error("Could not marshal App Gwy to update cache; Wiping cache.", err)
-		c.configCache = nil
+		*c.configCache = []byte{}
 		return
 	}
 	var sanitized []byte
 	if sanitized, err = deleteKeyFromJSON(jsonConfig, keysToDeleteForCache...); err != nil {
 		// Ran into an error; Wipe the existing cache
 		glog.Error("Failed stripping ETag key from App Gwy config. Wiping cache.", err)
-		c.configCache = nil
+		*c.configCache = []byte{}
 		return
 	}
 	*c.configCache = sanitized
 }
 
+// rejectConfig remembers the hash of a generated config, which failed to deploy; A nil config forgets it.
+func (c *AppGwIngressController) rejectConfig(appGw *n.ApplicationGateway) {
+	if c.rejectedConfigHash == nil {
+		return
+	}
+	*c.rejectedConfigHash = ""
+	if appGw == nil {
+		return
+	}
+	hash, err := getConfigHash(appGw)
+	if err != nil {
+		glog.Error("Could not hash the rejected App Gwy config; AGIC may deploy it again.", err)
+		return
+	}
+	*c.rejectedConfigHash = hash
+}
+
+// configIsRejected determines whether the generated config is the one, which failed to deploy last.
+func (c *AppGwIngressController) configIsRejected(appGw *n.ApplicationGateway) bool {
+	if c.rejectedConfigHash == nil || *c.rejectedConfigHash == "" {
+		return false
+	}
+	hash, err := getConfigHash(appGw)
+	return err == nil && hash == *c.rejectedConfigHash
+}
+
+// getConfigHash hashes the App Gwy config without its ETags.
+func getConfigHash(appGw *n.ApplicationGateway) (string, error) {
+	jsonConfig, err := appGw.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	sanitized, err := deleteKeyFromJSON(jsonConfig, keysToDeleteForCache...)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(sanitized)), nil
+}
+
 // configIsSame compares the newly created App Gwy configuration with a cache to determine whether anything has changed.
 func (c *AppGwIngressController) configIsSame(appGw *n.ApplicationGateway) bool {
 	if c.configCache == nil {
@@ -0,0 +1,41 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"github.com/golang/glog"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
+)
+
+// loadOwnership fetches the list of App Gateway sub-resources AGIC created; nil when ownership is not tracked, or there is
+// no manifest yet. Without it AGIC decides which sub-resources it may mutate based on prohibited and managed targets alone.
+// A manifest, which can not be read, is an error: AGIC would otherwise mutate sub-resources the manifest marks as foreign.
+func (c AppGwIngressController) loadOwnership() (*ownership.Manifest, error) {
+	if c.manifests == nil {
+		return nil, nil
+	}
+	manifest, err := c.manifests.Load()
+	if err != nil {
+		glog.Error("[ownership] Could not load the ownership manifest; Not applying App Gateway config: ", err)
+		return nil, ErrLoadingOwnership
+	}
+	return manifest, nil
+}
+
+// saveOwnership records the App Gateway sub-resources AGIC created with the config it applied. App Gateway keeps the config
+// when the manifest can not be saved; The next reconcile finds the config unchanged, and saves the manifest again.
+func (c AppGwIngressController) saveOwnership(manifest *ownership.Manifest) error {
+	if c.manifests == nil || manifest == nil {
+		return nil
+	}
+	if err := c.manifests.Save(manifest); err != nil {
+		glog.Error("[ownership] Could not save the ownership manifest: ", err)
+		return ErrSavingOwnership
+	}
+	glog.V(5).Infof("[ownership] Saved the ownership manifest with %d sub-resources", len(manifest.Resources))
+	return nil
+}
@@ -0,0 +1,76 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/ownership"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("ownership tests", func() {
+	var controller *AppGwIngressController
+
+	BeforeEach(func() {
+		controller = &AppGwIngressController{
+			manifests: ownership.NewStore(testclient.NewSimpleClientset(), tests.Namespace, tests.AppGwName),
+		}
+	})
+
+	Context("ensure loadOwnership and saveOwnership work as expected", func() {
+		It("loads no manifest before one was saved", func() {
+			manifest, err := controller.loadOwnership()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(manifest).To(BeNil())
+		})
+
+		It("loads the saved manifest", func() {
+			manifest := ownership.NewManifest()
+			manifest.Add(ownership.HTTPListeners, "fl-80", "default/ingress", "")
+			Expect(controller.saveOwnership(manifest)).ToNot(HaveOccurred())
+
+			loaded, err := controller.loadOwnership()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loaded).ToNot(BeNil())
+			Expect(loaded.Owns(ownership.HTTPListeners, "fl-80")).To(BeTrue())
+		})
+
+		It("does nothing when ownership is not tracked", func() {
+			controller.manifests = nil
+			Expect(controller.saveOwnership(ownership.NewManifest())).ToNot(HaveOccurred())
+			manifest, err := controller.loadOwnership()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(manifest).To(BeNil())
+		})
+
+		It("reports a manifest, which could not be loaded", func() {
+			kubeClient := testclient.NewSimpleClientset()
+			kubeClient.PrependReactor("get", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.New("etcd is unavailable")
+			})
+			controller.manifests = ownership.NewStore(kubeClient, tests.Namespace, tests.AppGwName)
+			manifest, err := controller.loadOwnership()
+			Expect(err).To(Equal(ErrLoadingOwnership))
+			Expect(manifest).To(BeNil())
+		})
+
+		It("reports a manifest, which could not be saved", func() {
+			kubeClient := testclient.NewSimpleClientset()
+			kubeClient.PrependReactor("create", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.New("etcd is unavailable")
+			})
+			controller.manifests = ownership.NewStore(kubeClient, tests.Namespace, tests.AppGwName)
+			Expect(controller.saveOwnership(ownership.NewManifest())).To(Equal(ErrSavingOwnership))
+		})
+	})
+})
@@ -8,6 +8,7 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -23,12 +24,41 @@ import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/gatewayapi"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 )
 
 // Process is the callback function that will be executed for every event
 // in the EventQueue.
 func (c AppGwIngressController) Process(event events.Event) error {
+	if event.Type == events.DriftCheck {
+		return c.checkDrift(event)
+	}
+	return c.reconcile(event)
+}
+
+// reconcile builds the App Gateway config from the Kubernetes resources and applies it.
+func (c AppGwIngressController) reconcile(event events.Event) error {
+	err := c.process(event)
+
+	// The App Gateway was modified by someone else between our GET and our PUT.
+	// Start over - fetch the latest config and build on top of it.
+	for attempt := 1; err == ErrPreconditionFailed && attempt <= maxPreconditionFailedRetries; attempt++ {
+		glog.Warningf("App Gateway %s was modified while AGIC was updating it; Re-fetching and rebuilding config (attempt %d of %d)", c.appGwIdentifier.AppGwName, attempt, maxPreconditionFailedRetries)
+		if attempt == 1 {
+			// Let users know once per reconcile; The log has each attempt.
+			c.reportConcurrentModificationRetry(c.k8sContext.ListHTTPIngresses())
+		}
+		err = c.process(event)
+	}
+	if err == ErrPreconditionFailed {
+		c.reportConcurrentModification(c.k8sContext.ListHTTPIngresses())
+	}
+
+	return err
+}
+
+func (c AppGwIngressController) process(event events.Event) error {
 	ctx := context.Background()
 
 	// Get current application gateway config
@@ -45,11 +75,22 @@ func (c AppGwIngressController) Process(event events.Event) error {
 
 	envVars := environment.GetEnv()
 
+	ownershipManifest, err := c.loadOwnership()
+	if err != nil {
+		return err
+	}
+
 	cbCtx := &appgw.ConfigBuilderContext{
 		ServiceList:           c.k8sContext.ListServices(),
 		IngressList:           c.k8sContext.ListHTTPIngresses(),
+		HostnameGrants:        appgw.NewHostnameGrants(c.k8sContext.ListNamespaces()),
 		EnvVariables:          envVars,
 		EnablePanicOnPutError: envVars.EnablePanicOnPutError == "true",
+
+		EnableOptimisticConcurrency: envVars.EnableOptimisticConcurrency == "true",
+		EnableRollbackOnFailure:     envVars.EnableRollbackOnFailure == "true",
+
+		Ownership: ownershipManifest,
 	}
 
 	if envVars.EnableBrownfieldDeployment == "true" {
@@ -64,22 +105,50 @@ func (c AppGwIngressController) Process(event events.Event) error {
 			}
 			glog.V(3).Infof("[brownfield] Prohibited targets: %s", strings.Join(prohibitedTargetsList, ", "))
 		}
+
+		// Managed targets switch AGIC to allow-list mode: everything else on App Gateway is prohibited.
+		managedTargets := c.k8sContext.ListAzureManagedTargets()
+		if len(managedTargets) > 0 {
+			cbCtx.ManagedTargets = managedTargets
+			cbCtx.EnableBrownfieldDeployment = true
+			var managedTargetsList []string
+			for _, target := range *brownfield.GetTargetWhitelist(managedTargets) {
+				targetJSON, _ := json.Marshal(target)
+				managedTargetsList = append(managedTargetsList, string(targetJSON))
+			}
+			glog.V(3).Infof("[brownfield] Managed targets: %s", strings.Join(managedTargetsList, ", "))
+		}
 	}
 
 	if cbCtx.EnvVariables.EnableIstioIntegration == "true" {
 		istioServices := c.k8sContext.ListIstioVirtualServices()
-		istioGateways := c.k8sContext.ListIstioGateways()
+		istioGateways := c.k8sContext.GetGateways()
 		if len(istioGateways) > 0 && len(istioServices) > 0 {
 			cbCtx.IstioGateways = istioGateways
 			cbCtx.IstioVirtualServices = istioServices
+			cbCtx.IstioDestinationRules = c.k8sContext.ListIstioDestinationRules()
 			cbCtx.EnableIstioIntegration = true
 		}
 	}
 
+	// Gateway API listeners and routes become Ingresses, which go through the same pruning and config building as the Ingresses of the cluster.
+	var gatewayAPI *gatewayapi.Translation
+	if cbCtx.EnvVariables.EnableGatewayAPI == "true" {
+		gatewayAPI = c.translateGatewayAPI()
+		cbCtx.GatewayAPI = gatewayAPI
+		cbCtx.IngressList = append(cbCtx.IngressList, gatewayAPI.Ingresses...)
+	}
+
+	// Report what each prohibited target matched; This needs the Ingress list before it is pruned.
+	if len(cbCtx.ProhibitedTargets) > 0 {
+		c.updateProhibitedTargetsStatus(appGw, cbCtx)
+	}
+
 	cbCtx.IngressList = c.PruneIngress(&appGw, cbCtx)
 	if len(cbCtx.IngressList) == 0 && !cbCtx.EnableIstioIntegration {
 		errorLine := "no Ingress in the pruned Ingress list. Please check Ingress events to get more information"
 		glog.Error(errorLine)
+		c.updateGatewayAPIStatus(&appGw, gatewayAPI, cbCtx.EnvVariables, errors.New(errorLine))
 		return nil
 	}
 
@@ -97,6 +166,9 @@ func (c AppGwIngressController) Process(event events.Event) error {
 		return err
 	}
 
+	// Build mutates the App Gateway config; Keep track of the resources AGIC managed before.
+	existingResources := getManagedResources(appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets)
+
 	// Create a configbuilder based on current appgw config
 	configBuilder := appgw.NewConfigBuilder(c.k8sContext, &c.appGwIdentifier, &appGw, c.recorder)
 
@@ -109,6 +181,7 @@ func (c AppGwIngressController) Process(event events.Event) error {
 	// Replace the current appgw config with the generated one
 	if generatedAppGw, err = configBuilder.Build(cbCtx); err != nil {
 		glog.Error("ConfigBuilder Build returned error:", err)
+		c.updateGatewayAPIStatus(&appGw, gatewayAPI, cbCtx.EnvVariables, err)
 		return err
 	}
 
@@ -117,14 +190,30 @@ func (c AppGwIngressController) Process(event events.Event) error {
 		glog.Error("ConfigBuilder PostBuildValidate returned error:", err)
 	}
 
+	manifest := configBuilder.Ownership(cbCtx)
+
 	if c.configIsSame(&appGw) {
+		// AGIC may not have recorded the manifest yet when the config did not change since it started.
+		ownershipErr := c.saveOwnership(manifest)
+
 		// update ingresses with appgw gateway ip address
 		c.updateIngressStatus(generatedAppGw, cbCtx, event)
+		c.updateGatewayAPIStatus(generatedAppGw, gatewayAPI, cbCtx.EnvVariables, nil)
 
 		glog.V(3).Info("cache: Config has NOT changed! No need to connect to ARM.")
+		return ownershipErr
+	}
+
+	if c.configIsRejected(generatedAppGw) {
+		// The config failed to deploy and App Gateway was rolled back; Deploying it again would fail again.
+		glog.Warning("Not applying App Gwy config, which failed to deploy and was rolled back before; Waiting for Kubernetes resources to change.")
 		return nil
 	}
 
+	if err := c.checkDeletionGuard(existingResources, getManagedResources(*generatedAppGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets), cbCtx); err != nil {
+		return err
+	}
+
 	glog.V(3).Info("BEGIN AppGateway deployment")
 	defer glog.V(3).Info("END AppGateway deployment")
 
@@ -132,16 +221,27 @@ func (c AppGwIngressController) Process(event events.Event) error {
 
 	deploymentStart := time.Now()
 	// Initiate deployment
-	appGwFuture, err := c.appGwClient.CreateOrUpdate(ctx, c.appGwIdentifier.ResourceGroup, c.appGwIdentifier.AppGwName, *generatedAppGw)
+	var appGwFuture n.ApplicationGatewaysCreateOrUpdateFuture
+	if cbCtx.EnableOptimisticConcurrency && appGw.Etag != nil {
+		appGwFuture, err = c.createOrUpdateIfMatch(ctx, *generatedAppGw, *appGw.Etag)
+	} else {
+		appGwFuture, err = c.appGwClient.CreateOrUpdate(ctx, c.appGwIdentifier.ResourceGroup, c.appGwIdentifier.AppGwName, *generatedAppGw)
+	}
+	if isPreconditionFailed(appGwFuture, err) {
+		*c.configCache = []byte{}
+		glog.Warningf("App Gateway %s was modified by another party since AGIC fetched its config", c.appGwIdentifier.AppGwName)
+		return ErrPreconditionFailed
+	}
 	if err != nil {
 		// Reset cache
-		c.configCache = nil
+		*c.configCache = []byte{}
 		configJSON, _ := dumpSanitizedJSON(&appGw, logToFile, nil)
 		glogIt := glog.Errorf
 		if cbCtx.EnablePanicOnPutError {
 			glogIt = glog.Fatalf
 		}
 		glogIt("Failed applying App Gwy configuration: %s -- %s", err, string(configJSON))
+		c.updateGatewayAPIStatus(generatedAppGw, gatewayAPI, cbCtx.EnvVariables, err)
 		return err
 	}
 	// Wait until deployment finshes and save the error message
@@ -154,18 +254,28 @@ func (c AppGwIngressController) Process(event events.Event) error {
 
 	if err != nil {
 		// Reset cache
-		c.configCache = nil
+		*c.configCache = []byte{}
 		glog.Warning("Unable to deploy App Gateway config.", err)
+		c.updateGatewayAPIStatus(generatedAppGw, gatewayAPI, cbCtx.EnvVariables, err)
+		if cbCtx.EnableRollbackOnFailure {
+			c.rollbackToLastKnownGood(ctx, generatedAppGw, cbCtx)
+		}
 		return ErrDeployingAppGatewayConfig
 	}
 
 	glog.V(3).Info("cache: Updated with latest applied config.")
 	c.updateCache(&appGw)
+	c.rejectConfig(nil)
+
+	c.saveSnapshot(*generatedAppGw)
+
+	ownershipErr := c.saveOwnership(manifest)
 
 	// update ingresses with appgw gateway ip address
 	c.updateIngressStatus(generatedAppGw, cbCtx, event)
+	c.updateGatewayAPIStatus(generatedAppGw, gatewayAPI, cbCtx.EnvVariables, nil)
 
-	return nil
+	return ownershipErr
 }
 
 func (c AppGwIngressController) updateIngressStatus(appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, event events.Event) {
@@ -0,0 +1,144 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+)
+
+// updateProhibitedTargetsStatus reports on each prohibited target the App Gateway config and the Ingress rules it matched.
+// This must run before the Ingress list is pruned, and before Build mutates the App Gateway config.
+func (c AppGwIngressController) updateProhibitedTargetsStatus(appGw n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext) {
+	usePrivateIP := cbCtx.EnvVariables.UsePrivateIP == "true"
+	for _, target := range cbCtx.ProhibitedTargets {
+		status := getProhibitedTargetStatus(appGw, target, cbCtx.IngressList, usePrivateIP)
+		if reflect.DeepEqual(status, target.Status) {
+			continue
+		}
+		glog.V(5).Infof("[brownfield] Updating status of AzureIngressProhibitedTarget %s/%s", target.Namespace, target.Name)
+		if err := c.k8sContext.UpdateAzureProhibitedTargetStatus(*target, status); err != nil {
+			glog.Error(err)
+		}
+	}
+}
+
+// getProhibitedTargetStatus computes the status of a single prohibited target.
+func getProhibitedTargetStatus(appGw n.ApplicationGateway, target *ptv1.AzureIngressProhibitedTarget, ingressList []*v1beta1.Ingress, usePrivateIP bool) ptv1.AzureIngressProhibitedTargetStatus {
+	er := brownfield.NewExistingResources(appGw, []*ptv1.AzureIngressProhibitedTarget{target}, nil, nil)
+
+	var status ptv1.AzureIngressProhibitedTargetStatus
+
+	listeners, _ := er.GetBlacklistedListeners()
+	for _, listener := range listeners {
+		status.Listeners = append(status.Listeners, *listener.Name)
+	}
+
+	rules, _ := er.GetBlacklistedRoutingRules()
+	for _, rule := range rules {
+		status.RoutingRules = append(status.RoutingRules, *rule.Name)
+	}
+
+	pathMaps, _ := er.GetBlacklistedPathMaps()
+	for _, pathMap := range pathMaps {
+		status.URLPathMaps = append(status.URLPathMaps, *pathMap.Name)
+	}
+
+	pools, _ := er.GetBlacklistedPools()
+	for _, pool := range pools {
+		status.BackendAddressPools = append(status.BackendAddressPools, *pool.Name)
+	}
+
+	settings, _ := er.GetBlacklistedHTTPSettings()
+	for _, setting := range settings {
+		status.BackendHTTPSettings = append(status.BackendHTTPSettings, *setting.Name)
+	}
+
+	probes, _ := er.GetBlacklistedProbes()
+	for _, probe := range probes {
+		status.Probes = append(status.Probes, *probe.Name)
+	}
+
+	for _, ingress := range ingressList {
+		status.PrunedIngressRules = append(status.PrunedIngressRules, getPrunedIngressRules(er, ingress, usePrivateIP)...)
+	}
+
+	status.Conditions = []ptv1.AzureIngressProhibitedTargetCondition{
+		getInvalidSpecCondition(target),
+	}
+
+	return status
+}
+
+// getPrunedIngressRules lists the hosts and paths of the given Ingress, which PruneIngressRules removes.
+func getPrunedIngressRules(er brownfield.ExistingResources, ingress *v1beta1.Ingress, usePrivateIP bool) []ptv1.PrunedIngressRule {
+	type hostPath struct{ host, path string }
+	retained := make(map[hostPath]interface{})
+	for _, rule := range er.PruneIngressRules(ingress, usePrivateIP) {
+		if rule.HTTP == nil || len(rule.HTTP.Paths) == 0 {
+			retained[hostPath{host: rule.Host}] = nil
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			retained[hostPath{host: rule.Host, path: path.Path}] = nil
+		}
+	}
+
+	ingressName := fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name)
+	var pruned []ptv1.PrunedIngressRule
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		if len(rule.HTTP.Paths) == 0 {
+			if _, exists := retained[hostPath{host: rule.Host}]; !exists {
+				pruned = append(pruned, ptv1.PrunedIngressRule{Ingress: ingressName, Host: rule.Host})
+			}
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if _, exists := retained[hostPath{host: rule.Host, path: path.Path}]; !exists {
+				pruned = append(pruned, ptv1.PrunedIngressRule{Ingress: ingressName, Host: rule.Host, Path: path.Path})
+			}
+		}
+	}
+	return pruned
+}
+
+// getInvalidSpecCondition validates the spec of the prohibited target; The last transition time is retained while the status does not change.
+func getInvalidSpecCondition(target *ptv1.AzureIngressProhibitedTarget) ptv1.AzureIngressProhibitedTargetCondition {
+	condition := ptv1.AzureIngressProhibitedTargetCondition{
+		Type:    ptv1.InvalidSpec,
+		Status:  v1.ConditionFalse,
+		Reason:  "Valid",
+		Message: "The spec of the prohibited target is valid",
+	}
+	if problems := brownfield.ValidateProhibitedTargetSpec(target.Spec); len(problems) > 0 {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = "Invalid"
+		condition.Message = strings.Join(problems, "; ")
+		glog.Warningf("[brownfield] AzureIngressProhibitedTarget %s/%s has an invalid spec: %s", target.Namespace, target.Name, condition.Message)
+	}
+
+	condition.LastTransitionTime = metav1.Now()
+	for _, existing := range target.Status.Conditions {
+		if existing.Type == condition.Type && existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+	return condition
+}
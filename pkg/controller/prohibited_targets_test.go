@@ -0,0 +1,116 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gateway_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
+	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("prohibited target status tests", func() {
+
+	newTarget := func(spec ptv1.AzureIngressProhibitedTargetSpec) *ptv1.AzureIngressProhibitedTarget {
+		return &ptv1.AzureIngressProhibitedTarget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "prohibited-target",
+				Namespace: tests.Namespace,
+			},
+			Spec: spec,
+		}
+	}
+
+	newIngressList := func() []*v1beta1.Ingress {
+		ingress := tests.NewIngressTestFixture(tests.Namespace, "ingress")
+		ingress.Spec.Rules[0].Host = tests.OtherHost
+		return []*v1beta1.Ingress{&ingress}
+	}
+
+	Context("ensure getProhibitedTargetStatus reports what the target matched", func() {
+		It("lists App Gateway config and Ingress rules for the hostname", func() {
+			target := newTarget(ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.OtherHost})
+			status := getProhibitedTargetStatus(fixtures.GetAppGateway(), target, newIngressList(), false)
+
+			Expect(status.Listeners).To(ConsistOf(fixtures.HTTPListenerNameBasic, fixtures.HTTPListenerPathBased2))
+			Expect(status.RoutingRules).ToNot(BeEmpty())
+			Expect(status.PrunedIngressRules).To(ConsistOf(ptv1.PrunedIngressRule{
+				Ingress: tests.Namespace + "/ingress",
+				Host:    tests.OtherHost,
+				Path:    "/hi",
+			}))
+
+			Expect(len(status.Conditions)).To(Equal(1))
+			Expect(status.Conditions[0].Type).To(Equal(ptv1.InvalidSpec))
+			Expect(status.Conditions[0].Status).To(Equal(v1.ConditionFalse))
+		})
+
+		It("lists nothing for a target which does not match any config", func() {
+			target := newTarget(ptv1.AzureIngressProhibitedTargetSpec{Hostname: "nothing.com"})
+			status := getProhibitedTargetStatus(fixtures.GetAppGateway(), target, newIngressList(), false)
+
+			Expect(status.Listeners).To(BeEmpty())
+			Expect(status.RoutingRules).To(BeEmpty())
+			Expect(status.PrunedIngressRules).To(BeEmpty())
+		})
+
+		It("sets the InvalidSpec condition for paths which do not begin with a /", func() {
+			target := newTarget(ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.OtherHost, Paths: []string{"api/*"}})
+			status := getProhibitedTargetStatus(fixtures.GetAppGateway(), target, newIngressList(), false)
+
+			Expect(status.Conditions[0].Status).To(Equal(v1.ConditionTrue))
+			Expect(status.Conditions[0].Message).To(ContainSubstring(`path "api/*" must begin with a /`))
+		})
+
+		It("retains the last transition time while the condition does not change", func() {
+			lastTransition := metav1.NewTime(time.Now().Add(-time.Hour))
+			target := newTarget(ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.OtherHost})
+			target.Status.Conditions = []ptv1.AzureIngressProhibitedTargetCondition{
+				{Type: ptv1.InvalidSpec, Status: v1.ConditionFalse, LastTransitionTime: lastTransition},
+			}
+			status := getProhibitedTargetStatus(fixtures.GetAppGateway(), target, newIngressList(), false)
+			Expect(status.Conditions[0].LastTransitionTime).To(Equal(lastTransition))
+		})
+	})
+
+	Context("ensure updateProhibitedTargetsStatus updates the status subresource", func() {
+		It("writes the status of the prohibited target", func() {
+			crdClient := fake.NewSimpleClientset()
+			ctxt := k8scontext.NewContext(testclient.NewSimpleClientset(), crdClient, istio_fake.NewSimpleClientset(), gateway_fake.NewSimpleClientset(), []string{tests.Namespace}, nil, 1000*time.Second)
+			controller := AppGwIngressController{k8sContext: ctxt}
+
+			target := newTarget(ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.OtherHost})
+			_, err := crdClient.AzureingressprohibitedtargetsV1().AzureIngressProhibitedTargets(tests.Namespace).Create(target)
+			Expect(err).ToNot(HaveOccurred())
+
+			cbCtx := &appgw.ConfigBuilderContext{
+				IngressList:       newIngressList(),
+				ProhibitedTargets: []*ptv1.AzureIngressProhibitedTarget{target},
+				EnvVariables:      environment.GetFakeEnv(),
+			}
+			controller.updateProhibitedTargetsStatus(fixtures.GetAppGateway(), cbCtx)
+
+			updated, err := crdClient.AzureingressprohibitedtargetsV1().AzureIngressProhibitedTargets(tests.Namespace).Get(target.Name, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.Status.Listeners).To(ConsistOf(fixtures.HTTPListenerNameBasic, fixtures.HTTPListenerPathBased2))
+			Expect(len(updated.Status.PrunedIngressRules)).To(Equal(1))
+		})
+	})
+})
@@ -27,8 +27,10 @@ func (c *AppGwIngressController) PruneIngress(appGw *n.ApplicationGateway, cbCtx
 		if cbCtx.EnvVariables.EnableBrownfieldDeployment == "true" {
 			pruneFuncList = append(pruneFuncList, pruneProhibitedIngress)
 		}
+		pruneFuncList = append(pruneFuncList, pruneHostnamesNotOwned)
 		pruneFuncList = append(pruneFuncList, pruneNoPrivateIP)
 		pruneFuncList = append(pruneFuncList, pruneRedirectWithNoTLS)
+		pruneFuncList = append(pruneFuncList, pruneHostPathConflicts)
 	})
 	prunedIngresses := cbCtx.IngressList
 	for _, prune := range pruneFuncList {
@@ -38,16 +40,70 @@ func (c *AppGwIngressController) PruneIngress(appGw *n.ApplicationGateway, cbCtx
 	return prunedIngresses
 }
 
-// pruneProhibitedIngress filters rules that are specified by prohibited target CRD
+// pruneProhibitedIngress filters rules that are specified by prohibited target CRD, or not specified by managed target CRD in allow-list mode
 func pruneProhibitedIngress(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*v1beta1.Ingress) []*v1beta1.Ingress {
-	// Mutate the list of Ingresses by removing ones that AGIC should not be creating configuration.
+	er := brownfield.NewExistingResources(*appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets, nil)
+	usePrivateIP := cbCtx.EnvVariables.UsePrivateIP == "true"
+
+	var prunedIngresses []*v1beta1.Ingress
 	for idx, ingress := range ingressList {
 		glog.V(5).Infof("Original Ingress[%d] Rules: %+v", idx, ingress.Spec.Rules)
-		ingressList[idx].Spec.Rules = brownfield.PruneIngressRules(ingress, cbCtx.ProhibitedTargets)
-		glog.V(5).Infof("Sanitized Ingress[%d] Rules: %+v", idx, ingress.Spec.Rules)
+		for _, overlap := range er.GetPartialPathOverlaps(ingress, usePrivateIP) {
+			var message string
+			if overlap.Prohibited {
+				message = fmt.Sprintf("ignoring path %s of host %s in %s as it partially overlaps with prohibited path %s", overlap.IngressPath, overlap.Hostname, describeIngress(cbCtx, ingress), overlap.TargetPath)
+			} else {
+				message = fmt.Sprintf("ignoring path %s of host %s in %s as it is only partially covered by managed path %s", overlap.IngressPath, overlap.Hostname, describeIngress(cbCtx, ingress), overlap.TargetPath)
+			}
+			glog.Warning(message)
+			c.reportPruned(cbCtx, ingress, events.ReasonPartialPathOverlap, message)
+		}
+		// Ingresses in the list are shared with the informer cache; Prune a copy.
+		pruned := ingress.DeepCopy()
+		pruned.Spec.Rules = er.PruneIngressRules(ingress, usePrivateIP)
+		glog.V(5).Infof("Sanitized Ingress[%d] Rules: %+v", idx, pruned.Spec.Rules)
+		prunedIngresses = append(prunedIngresses, pruned)
 	}
 
-	return ingressList
+	return prunedIngresses
+}
+
+// pruneHostnamesNotOwned filters rules for hostnames, which are granted to other namespaces, but not to the namespace of the ingress
+func pruneHostnamesNotOwned(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*v1beta1.Ingress) []*v1beta1.Ingress {
+	if len(cbCtx.HostnameGrants) == 0 {
+		return ingressList
+	}
+
+	var prunedIngresses []*v1beta1.Ingress
+	for _, ingress := range ingressList {
+		rules, notOwned := cbCtx.HostnameGrants.PruneIngressRules(ingress)
+		dropBackend := ingress.Spec.Backend != nil && !cbCtx.HostnameGrants.IsOwner(ingress.Namespace, "")
+		if len(notOwned) == 0 && !dropBackend {
+			prunedIngresses = append(prunedIngresses, ingress)
+			continue
+		}
+
+		for _, host := range notOwned {
+			errorLine := fmt.Sprintf("ignoring rules for host %q in %s as namespace %s has not been granted this hostname", host, describeIngress(cbCtx, ingress), ingress.Namespace)
+			glog.Error(errorLine)
+			c.reportPruned(cbCtx, ingress, events.ReasonHostnameNotOwned, errorLine)
+		}
+		if dropBackend {
+			errorLine := fmt.Sprintf("ignoring default backend of %s as namespace %s has not been granted all hostnames", describeIngress(cbCtx, ingress), ingress.Namespace)
+			glog.Error(errorLine)
+			c.reportPruned(cbCtx, ingress, events.ReasonHostnameNotOwned, errorLine)
+		}
+
+		// Ingresses in the list are shared with the informer cache; Prune a copy.
+		pruned := ingress.DeepCopy()
+		pruned.Spec.Rules = rules
+		if dropBackend {
+			pruned.Spec.Backend = nil
+		}
+		prunedIngresses = append(prunedIngresses, pruned)
+	}
+
+	return prunedIngresses
 }
 
 // pruneNoPrivateIP filters ingresses which use private IP annotation when AppGw doesn't have a private IP
@@ -62,9 +118,9 @@ func pruneNoPrivateIP(c *AppGwIngressController, appGw *n.ApplicationGateway, cb
 
 		usePrivateIP = usePrivateIP || cbCtx.EnvVariables.UsePrivateIP == "true"
 		if usePrivateIP && !appGwHasPrivateIP {
-			errorLine := fmt.Sprintf("ignoring Ingress %s/%s as it requires Application Gateway %s has a private IP adress", ingress.Namespace, ingress.Name, c.appGwIdentifier.AppGwName)
+			errorLine := fmt.Sprintf("ignoring %s as it requires Application Gateway %s has a private IP adress", describeIngress(cbCtx, ingress), c.appGwIdentifier.AppGwName)
 			glog.Error(errorLine)
-			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonNoPrivateIPError, errorLine)
+			c.reportPruned(cbCtx, ingress, events.ReasonNoPrivateIPError, errorLine)
 		} else {
 			prunedIngresses = append(prunedIngresses, ingress)
 		}
@@ -77,12 +133,10 @@ func pruneNoPrivateIP(c *AppGwIngressController, appGw *n.ApplicationGateway, cb
 func pruneRedirectWithNoTLS(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*v1beta1.Ingress) []*v1beta1.Ingress {
 	var prunedIngresses []*v1beta1.Ingress
 	for _, ingress := range ingressList {
-		hasTLS := ingress.Spec.TLS != nil && len(ingress.Spec.TLS) > 0
-		sslRedirect, _ := annotations.IsSslRedirect(ingress)
-		if !hasTLS && sslRedirect {
-			errorLine := fmt.Sprintf("ignoring Ingress %s/%s as it has an invalid spec. It is annotated with ssl-redirect: true but is missing a TLS secret. Please add a TLS secret or remove ssl-redirect annotation", ingress.Namespace, ingress.Name)
+		if err := appgw.ValidateRedirectHasTLS(ingress); err != nil {
+			errorLine := fmt.Sprintf("ignoring %s as it has an invalid spec. It is annotated with ssl-redirect: true but is missing a TLS secret. Please add a TLS secret or remove ssl-redirect annotation", describeIngress(cbCtx, ingress))
 			glog.Error(errorLine)
-			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonRedirectWithNoTLS, errorLine)
+			c.reportPruned(cbCtx, ingress, events.ReasonRedirectWithNoTLS, errorLine)
 		} else {
 			prunedIngresses = append(prunedIngresses, ingress)
 		}
@@ -90,3 +144,38 @@ func pruneRedirectWithNoTLS(c *AppGwIngressController, appGw *n.ApplicationGatew
 
 	return prunedIngresses
 }
+
+// pruneHostPathConflicts removes the hosts and paths, which are already claimed with a different backend or TLS secret by an older Ingress
+func pruneHostPathConflicts(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*v1beta1.Ingress) []*v1beta1.Ingress {
+	prunedIngresses, conflicts := appgw.ResolveHostPathConflicts(ingressList)
+	for _, conflict := range conflicts {
+		var message string
+		if conflict.ConflictingTLS {
+			message = fmt.Sprintf("ignoring host %q in %s as %s takes precedence and uses a different TLS secret for it", conflict.Host, describeIngress(cbCtx, conflict.Ingress), describeIngress(cbCtx, conflict.ConflictingIngress))
+		} else {
+			message = fmt.Sprintf("ignoring path %s of host %q in %s as %s takes precedence and uses a different backend for it", conflict.Path, conflict.Host, describeIngress(cbCtx, conflict.Ingress), describeIngress(cbCtx, conflict.ConflictingIngress))
+		}
+		glog.Warning(message)
+		c.reportPruned(cbCtx, conflict.Ingress, events.ReasonHostPathConflict, message)
+	}
+
+	return prunedIngresses
+}
+
+// reportPruned records a warning for the hosts or paths of the Ingress a pruner ignored; For the Ingresses translated from an HTTPRoute,
+// the warning goes to the route, which is then no longer accepted.
+func (c *AppGwIngressController) reportPruned(cbCtx *appgw.ConfigBuilderContext, ingress *v1beta1.Ingress, reason string, message string) {
+	if route := cbCtx.GatewayAPI.Prune(ingress, reason, message); route != nil {
+		c.recorder.Event(route, v1.EventTypeWarning, reason, message)
+		return
+	}
+	c.recorder.Event(ingress, v1.EventTypeWarning, reason, message)
+}
+
+// describeIngress names the Ingress in the messages of the pruners; The Ingresses translated from an HTTPRoute by the route.
+func describeIngress(cbCtx *appgw.ConfigBuilderContext, ingress *v1beta1.Ingress) string {
+	if route := cbCtx.GatewayAPI.RouteOf(ingress); route != nil {
+		return fmt.Sprintf("HTTPRoute %s/%s", route.Namespace, route.Name)
+	}
+	return fmt.Sprintf("Ingress %s/%s", ingress.Namespace, ingress.Name)
+}
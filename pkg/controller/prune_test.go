@@ -7,14 +7,21 @@ package controller
 
 import (
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	v1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	gwv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/gatewayapi"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
 )
@@ -68,6 +75,62 @@ var _ = Describe("prune function tests", func() {
 		})
 	})
 
+	Context("ensure pruneProhibitedIngress reports partial path overlaps", func() {
+		ingress := tests.NewIngressTestFixture(tests.Namespace, "ingress-with-wildcard")
+		ingress.Spec.Rules[0].HTTP.Paths[0].Path = "/*"
+		cbCtx := &appgw.ConfigBuilderContext{
+			IngressList: []*v1beta1.Ingress{&ingress},
+			ProhibitedTargets: []*ptv1.AzureIngressProhibitedTarget{
+				{
+					Spec: ptv1.AzureIngressProhibitedTargetSpec{
+						Hostname: "hello.com",
+						Paths:    []string{"/api/*"},
+					},
+				},
+			},
+			EnvVariables: environment.GetFakeEnv(),
+		}
+		appGw := fixtures.GetAppGateway()
+
+		It("prunes the overlapping path and emits an event", func() {
+			prunedIngresses := pruneProhibitedIngress(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(len(prunedIngresses)).To(Equal(1))
+			Expect(prunedIngresses[0].Spec.Rules).To(BeEmpty())
+
+			recorder := controller.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonPartialPathOverlap))
+		})
+	})
+
+	Context("ensure pruneProhibitedIngress leaves the cached Ingresses alone", func() {
+		ingress := tests.NewIngressTestFixture(tests.Namespace, "ingress")
+		ingress.Spec.Rules[0].Host = tests.OtherHost
+		target := &ptv1.AzureIngressProhibitedTarget{
+			ObjectMeta: metav1.ObjectMeta{Name: "prohibited-target", Namespace: tests.Namespace},
+			Spec:       ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.OtherHost},
+		}
+		cbCtx := &appgw.ConfigBuilderContext{
+			IngressList:       []*v1beta1.Ingress{&ingress},
+			ProhibitedTargets: []*ptv1.AzureIngressProhibitedTarget{target},
+			EnvVariables:      environment.GetFakeEnv(),
+		}
+		appGw := fixtures.GetAppGateway()
+
+		It("reports the same pruned rules on every reconcile", func() {
+			var statuses []ptv1.AzureIngressProhibitedTargetStatus
+			for i := 0; i < 2; i++ {
+				prunedIngresses := pruneProhibitedIngress(controller, &appGw, cbCtx, cbCtx.IngressList)
+				Expect(prunedIngresses[0].Spec.Rules).To(BeEmpty())
+				statuses = append(statuses, getProhibitedTargetStatus(appGw, target, cbCtx.IngressList, false))
+			}
+
+			Expect(ingress.Spec.Rules).ToNot(BeEmpty())
+			Expect(statuses[0].PrunedIngressRules).ToNot(BeEmpty())
+			Expect(statuses[1].PrunedIngressRules).To(Equal(statuses[0].PrunedIngressRules))
+		})
+	})
+
 	Context("ensure pruneRedirectNoTLS prunes ingress", func() {
 		// invalid ingress without https and redirect
 		ingressInvalid := tests.NewIngressFixture()
@@ -121,4 +184,120 @@ var _ = Describe("prune function tests", func() {
 			Expect(prunedIngresses).To(ContainElement(ingressValid2))
 		})
 	})
+
+	Context("ensure pruneHostPathConflicts prunes the newer Ingress", func() {
+		older := tests.NewIngressTestFixture(tests.Namespace, "older")
+		older.UID = "aaa"
+		newer := tests.NewIngressTestFixture("other-namespace", "newer")
+		newer.UID = "bbb"
+		cbCtx := &appgw.ConfigBuilderContext{
+			IngressList: []*v1beta1.Ingress{&newer, &older},
+		}
+		appGw := fixtures.GetAppGateway()
+
+		It("drops the conflicting rule and emits an event naming the winner", func() {
+			prunedIngresses := pruneHostPathConflicts(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(len(prunedIngresses)).To(Equal(2))
+			Expect(prunedIngresses[0].Spec.Rules).To(BeEmpty())
+			Expect(prunedIngresses[1]).To(Equal(&older))
+
+			recorder := controller.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			event := <-recorder.Events
+			Expect(event).To(ContainSubstring(events.ReasonHostPathConflict))
+			Expect(event).To(ContainSubstring(tests.Namespace + "/older"))
+		})
+	})
+
+	Context("ensure pruneHostnamesNotOwned prunes rules for hostnames granted to other namespaces", func() {
+		ingress := tests.NewIngressTestFixture("team-b", "ingress")
+		ingress.Spec.Rules[0].Host = "www.team-a.contoso.com"
+		ingress.Spec.Backend = &v1beta1.IngressBackend{ServiceName: tests.ServiceName}
+		otherIngress := tests.NewIngressTestFixture("team-a", "ingress")
+		otherIngress.Spec.Rules[0].Host = "www.team-a.contoso.com"
+		cbCtx := &appgw.ConfigBuilderContext{
+			IngressList: []*v1beta1.Ingress{&ingress, &otherIngress},
+			HostnameGrants: appgw.HostnameGrants{
+				"team-a": {"*.team-a.contoso.com"},
+			},
+		}
+		appGw := fixtures.GetAppGateway()
+
+		It("drops the rule of the namespace without the grant and emits an event", func() {
+			prunedIngresses := pruneHostnamesNotOwned(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(len(prunedIngresses)).To(Equal(2))
+			Expect(prunedIngresses[0].Spec.Rules).To(BeEmpty())
+			Expect(prunedIngresses[0].Spec.Backend).ToNot(BeNil())
+			Expect(prunedIngresses[1]).To(Equal(&otherIngress))
+
+			// The Ingress in the cache must not be mutated.
+			Expect(ingress.Spec.Rules).To(HaveLen(1))
+
+			recorder := controller.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonHostnameNotOwned))
+		})
+	})
+
+	Context("ensure the pruners report on the HTTPRoutes the Ingresses were translated from", func() {
+		from := gwv1.NamespacesFromAll
+		translation := gatewayapi.Translate(gatewayapi.Resources{
+			GatewayClasses: []*gwv1.GatewayClass{{
+				ObjectMeta: metav1.ObjectMeta{Name: "azure-application-gateway"},
+				Spec:       gwv1.GatewayClassSpec{ControllerName: gatewayapi.ControllerName},
+			}},
+			Gateways: []*gwv1.Gateway{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "gateway"},
+				Spec: gwv1.GatewaySpec{
+					GatewayClassName: "azure-application-gateway",
+					Listeners: []gwv1.Listener{{
+						Name:          "http",
+						Port:          80,
+						Protocol:      gwv1.HTTPProtocolType,
+						AllowedRoutes: &gwv1.AllowedRoutes{Namespaces: &gwv1.RouteNamespaces{From: &from}},
+					}},
+				},
+			}},
+			HTTPRoutes: []*gwv1.HTTPRoute{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "store", UID: "store-uid"},
+				Spec: gwv1.HTTPRouteSpec{
+					ParentRefs: []gwv1.ParentReference{{Namespace: to.StringPtr("infra"), Name: "gateway"}},
+					Hostnames:  []string{"www.team-a.contoso.com"},
+					Rules: []gwv1.HTTPRouteRule{{
+						BackendRefs: []gwv1.HTTPBackendRef{
+							{BackendRef: gwv1.BackendRef{BackendObjectReference: gwv1.BackendObjectReference{Name: "store", Port: to.Int32Ptr(80)}}},
+						},
+					}},
+				},
+			}},
+			Services: []*v1.Service{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "store"}},
+			},
+		})
+		cbCtx := &appgw.ConfigBuilderContext{
+			IngressList: translation.Ingresses,
+			HostnameGrants: appgw.HostnameGrants{
+				"team-a": {"*.team-a.contoso.com"},
+			},
+			GatewayAPI: translation,
+		}
+		appGw := fixtures.GetAppGateway()
+
+		It("names the route in the event and no longer accepts the route", func() {
+			Expect(translation.Ingresses).To(HaveLen(1))
+			prunedIngresses := pruneHostnamesNotOwned(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(prunedIngresses[0].Spec.Rules).To(BeEmpty())
+
+			recorder := controller.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			event := <-recorder.Events
+			Expect(event).To(ContainSubstring(events.ReasonHostnameNotOwned))
+			Expect(event).To(ContainSubstring("HTTPRoute team-b/store"))
+
+			parents := translation.RouteStatuses(metav1.Now())[0].Status.Parents
+			Expect(parents[0].Conditions[0].Type).To(Equal(gatewayapi.ConditionAccepted))
+			Expect(parents[0].Conditions[0].Status).To(Equal(gwv1.ConditionFalse))
+			Expect(parents[0].Conditions[0].Reason).To(Equal(events.ReasonHostnameNotOwned))
+		})
+	})
 })
@@ -0,0 +1,61 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/snapshot"
+)
+
+// saveSnapshot keeps the successfully applied config as the last known good one.
+func (c AppGwIngressController) saveSnapshot(appGw n.ApplicationGateway) {
+	if c.snapshots == nil {
+		return
+	}
+	saved, err := c.snapshots.Save(appGw)
+	if err != nil {
+		glog.Error("[snapshot] Could not save a snapshot of the applied App Gateway config: ", err)
+		return
+	}
+	glog.V(3).Infof("[snapshot] Saved App Gateway config snapshot %s", saved.ID)
+}
+
+// rollbackToLastKnownGood re-applies the most recent snapshot after the given config failed to deploy.
+func (c AppGwIngressController) rollbackToLastKnownGood(ctx context.Context, failedAppGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext) {
+	if c.snapshots == nil {
+		return
+	}
+
+	lastKnownGood, err := c.snapshots.Latest()
+	if err != nil {
+		glog.Error("[snapshot] Unable to roll back App Gateway; Could not get the last known good config: ", err)
+		return
+	}
+
+	glog.Warningf("[snapshot] Rolling back App Gateway %s to the last known good config %s", c.appGwIdentifier.AppGwName, lastKnownGood.ID)
+	message := fmt.Sprintf("Deployment of App Gateway %s failed; Rolled back to the last known good config %s", c.appGwIdentifier.AppGwName, lastKnownGood.ID)
+	if appliedAppGw, err := snapshot.Rollback(ctx, c.appGwClient, c.appGwIdentifier.ResourceGroup, c.appGwIdentifier.AppGwName, *lastKnownGood); err != nil {
+		glog.Error("[snapshot] Unable to roll back App Gateway: ", err)
+		message = fmt.Sprintf("Deployment of App Gateway %s failed; Rollback to the last known good config %s failed: %s", c.appGwIdentifier.AppGwName, lastKnownGood.ID, err)
+	} else {
+		// The cache holds what App Gateway holds, so that drift checks compare against the rolled back config.
+		// The failed config is remembered apart, so that AGIC does not deploy it again until Kubernetes resources change.
+		c.updateCache(&appliedAppGw)
+		c.rejectConfig(failedAppGw)
+	}
+
+	for _, ingress := range cbCtx.IngressList {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonAppGatewayRollback, message)
+	}
+}
@@ -0,0 +1,119 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/extensions/v1beta1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/snapshot"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("snapshot tests", func() {
+	var controller *AppGwIngressController
+	var store *snapshot.Store
+
+	BeforeEach(func() {
+		store = snapshot.NewStore(testclient.NewSimpleClientset(), tests.Namespace, 3)
+		controller = &AppGwIngressController{
+			configCache:        to.ByteSlicePtr([]byte{}),
+			rejectedConfigHash: to.StringPtr(""),
+			recorder:           record.NewFakeRecorder(100),
+			snapshots:          store,
+		}
+	})
+
+	Context("ensure saveSnapshot works as expected", func() {
+		It("saves the applied config as the last known good one", func() {
+			controller.saveSnapshot(fixtures.GetAppGateway())
+			snapshots, err := store.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(snapshots)).To(Equal(1))
+		})
+
+		It("does nothing when snapshots are disabled", func() {
+			controller.snapshots = nil
+			controller.saveSnapshot(fixtures.GetAppGateway())
+		})
+	})
+
+	Context("ensure rollbackToLastKnownGood works as expected", func() {
+		It("does not roll back without a snapshot", func() {
+			failedAppGw := fixtures.GetAppGateway()
+			cbCtx := &appgw.ConfigBuilderContext{
+				IngressList: []*v1beta1.Ingress{tests.NewIngressFixture()},
+			}
+			controller.rollbackToLastKnownGood(context.Background(), &failedAppGw, cbCtx)
+			Expect(*controller.configCache).To(BeEmpty())
+			Expect(controller.recorder.(*record.FakeRecorder).Events).To(BeEmpty())
+		})
+
+		It("re-applies the last known good config, caches it and rejects the failed one", func() {
+			lastKnownGood := fixtures.GetAppGateway()
+			controller.saveSnapshot(lastKnownGood)
+
+			// App Gateway answers the GET and the PUT of the rollback with the applied config.
+			provisioned := fixtures.GetAppGateway()
+			provisioned.ProvisioningState = to.StringPtr("Succeeded")
+			body, err := provisioned.MarshalJSON()
+			Expect(err).ToNot(HaveOccurred())
+			var methods []string
+			controller.appGwClient = n.NewApplicationGatewaysClient(tests.Subscription)
+			controller.appGwClient.Sender = autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+				methods = append(methods, req.Method)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Request:    req,
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(bytes.NewReader(body)),
+				}, nil
+			})
+			controller.appGwIdentifier = appgw.Identifier{
+				SubscriptionID: tests.Subscription,
+				ResourceGroup:  tests.ResourceGroup,
+				AppGwName:      tests.AppGwName,
+			}
+
+			failedAppGw := fixtures.GetAppGateway()
+			failedAppGw.Tags = map[string]*string{"failed": to.StringPtr("true")}
+			cbCtx := &appgw.ConfigBuilderContext{
+				IngressList: []*v1beta1.Ingress{tests.NewIngressFixture()},
+			}
+			controller.rollbackToLastKnownGood(context.Background(), &failedAppGw, cbCtx)
+
+			Expect(methods).To(Equal([]string{http.MethodGet, http.MethodPut}))
+
+			// The cache holds the rolled back config, which App Gateway holds now; So there is no drift.
+			Expect(controller.configIsSame(&failedAppGw)).To(BeFalse())
+			drift, err := getConfigDrift(*controller.configCache, provisioned, nil, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift).To(BeEmpty())
+
+			// Only the failed config is not deployed again.
+			Expect(controller.configIsRejected(&failedAppGw)).To(BeTrue())
+			Expect(controller.configIsRejected(&lastKnownGood)).To(BeFalse())
+
+			event := <-controller.recorder.(*record.FakeRecorder).Events
+			Expect(event).To(ContainSubstring(events.ReasonAppGatewayRollback))
+			Expect(event).To(ContainSubstring("Rolled back"))
+		})
+	})
+})
@@ -34,9 +34,9 @@ type FakeAzureIngressProhibitedTargets struct {
 	ns   string
 }
 
-var azureingressprohibitedtargetsResource = schema.GroupVersionResource{Group: "azureingressprohibitedtargets.appgw.ingress.k8s.io", Version: "v1", Resource: "azureingressprohibitedtargets"}
+var azureingressprohibitedtargetsResource = schema.GroupVersionResource{Group: "appgw.ingress.k8s.io", Version: "v1", Resource: "azureingressprohibitedtargets"}
 
-var azureingressprohibitedtargetsKind = schema.GroupVersionKind{Group: "azureingressprohibitedtargets.appgw.ingress.k8s.io", Version: "v1", Kind: "AzureIngressProhibitedTarget"}
+var azureingressprohibitedtargetsKind = schema.GroupVersionKind{Group: "appgw.ingress.k8s.io", Version: "v1", Kind: "AzureIngressProhibitedTarget"}
 
 // Get takes name of the azureIngressProhibitedTarget, and returns the corresponding azureIngressProhibitedTarget object, and an error if there is any.
 func (c *FakeAzureIngressProhibitedTargets) Get(name string, options v1.GetOptions) (result *azureingressprohibitedtargetv1.AzureIngressProhibitedTarget, err error) {
@@ -100,6 +100,18 @@ func (c *FakeAzureIngressProhibitedTargets) Update(azureIngressProhibitedTarget
 	return obj.(*azureingressprohibitedtargetv1.AzureIngressProhibitedTarget), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeAzureIngressProhibitedTargets) UpdateStatus(azureIngressProhibitedTarget *azureingressprohibitedtargetv1.AzureIngressProhibitedTarget) (*azureingressprohibitedtargetv1.AzureIngressProhibitedTarget, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(azureingressprohibitedtargetsResource, "status", c.ns, azureIngressProhibitedTarget), &azureingressprohibitedtargetv1.AzureIngressProhibitedTarget{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*azureingressprohibitedtargetv1.AzureIngressProhibitedTarget), err
+}
+
 // Delete takes name of the azureIngressProhibitedTarget and deletes it. Returns an error if one occurs.
 func (c *FakeAzureIngressProhibitedTargets) Delete(name string, options *v1.DeleteOptions) error {
 	_, err := c.Fake.
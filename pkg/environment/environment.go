@@ -6,11 +6,19 @@
 package environment
 
 import (
-	"github.com/pkg/errors"
+	"fmt"
 	"os"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/golang/glog"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 )
 
 const (
@@ -29,6 +37,9 @@ const (
 	// WatchNamespaceVarName is the name of the KUBERNETES_WATCHNAMESPACE
 	WatchNamespaceVarName = "KUBERNETES_WATCHNAMESPACE"
 
+	// WatchNamespaceSelectorVarName is a label selector; AGIC observes the namespaces matching it, along with the ones in KUBERNETES_WATCHNAMESPACE.
+	WatchNamespaceSelectorVarName = "KUBERNETES_WATCHNAMESPACE_SELECTOR"
+
 	// UsePrivateIPVarName is the name of the USE_PRIVATE_IP
 	UsePrivateIPVarName = "USE_PRIVATE_IP"
 
@@ -41,42 +52,117 @@ const (
 	// EnableIstioIntegrationVarName is a feature flag enabling observation of Istio specific CRDs
 	EnableIstioIntegrationVarName = "APPGW_ENABLE_ISTIO_INTEGRATION"
 
+	// EnableGatewayAPIVarName is a feature flag enabling observation of the GatewayClasses, Gateways and HTTPRoutes of the Kubernetes Gateway API
+	EnableGatewayAPIVarName = "APPGW_ENABLE_GATEWAY_API"
+
 	// EnableSaveConfigToFileVarName is a feature flag, which enables saving the App Gwy config to disk.
 	EnableSaveConfigToFileVarName = "APPGW_ENABLE_SAVE_CONFIG_TO_FILE"
 
 	// EnablePanicOnPutErrorVarName is a feature flag.
 	EnablePanicOnPutErrorVarName = "APPGW_ENABLE_PANIC_ON_PUT_ERROR"
+
+	// EnableOptimisticConcurrencyVarName is a feature flag, which makes AGIC PUT App Gateway config with the ETag obtained from the GET.
+	EnableOptimisticConcurrencyVarName = "APPGW_ENABLE_OPTIMISTIC_CONCURRENCY"
+
+	// DriftDetectionModeVarName enables periodic detection of out-of-band changes to App Gateway; One of "observe" or "enforce".
+	DriftDetectionModeVarName = "APPGW_DRIFT_DETECTION_MODE"
+
+	// DriftDetectionIntervalVarName is the interval between two drift checks, for instance "5m".
+	DriftDetectionIntervalVarName = "APPGW_DRIFT_DETECTION_INTERVAL"
+
+	// SnapshotCountVarName is the number of successfully applied App Gateway configs AGIC keeps; Snapshots are disabled when not set.
+	SnapshotCountVarName = "APPGW_SNAPSHOT_COUNT"
+
+	// EnableRollbackOnFailureVarName is a feature flag, which makes AGIC re-apply the last known good config when a deployment fails.
+	EnableRollbackOnFailureVarName = "APPGW_ENABLE_ROLLBACK_ON_FAILURE"
+
+	// DeletionGuardMaxPercentVarName is the largest percentage of AGIC managed listeners, rules or pools a single deployment may delete.
+	DeletionGuardMaxPercentVarName = "APPGW_DELETION_GUARD_MAX_PERCENT"
+
+	// DeletionGuardMaxCountVarName is the largest number of AGIC managed listeners, rules or pools a single deployment may delete.
+	DeletionGuardMaxCountVarName = "APPGW_DELETION_GUARD_MAX_COUNT"
+
+	// AllowMassDeletionVarName is a feature flag, which lets AGIC apply configs rejected by the deletion guard.
+	AllowMassDeletionVarName = "APPGW_ALLOW_MASS_DELETION"
+
+	// BackendModeVarName sets the addresses App Gateway sends requests to, for the Ingresses without the backend-mode annotation;
+	// One of "pod", "clusterip", "nodeport" or "loadbalancer". Defaults to "pod".
+	BackendModeVarName = "APPGW_BACKEND_MODE"
+
+	// EnableOwnershipTrackingVarName is a feature flag, which makes AGIC record the App Gateway sub-resources it creates, and
+	// mutate only these along with the ones prohibited and managed targets leave to it.
+	EnableOwnershipTrackingVarName = "APPGW_ENABLE_OWNERSHIP_TRACKING"
+
+	// AGICPodNamespaceVarName is the namespace AGIC runs in; AGIC keeps its own state there.
+	AGICPodNamespaceVarName = "AGIC_POD_NAMESPACE"
+)
+
+const (
+	// DriftDetectionModeObserve reports out-of-band changes to App Gateway without reverting them.
+	DriftDetectionModeObserve = "observe"
+
+	// DriftDetectionModeEnforce reports and reverts out-of-band changes to App Gateway.
+	DriftDetectionModeEnforce = "enforce"
+
+	// DefaultDriftDetectionInterval is used when DriftDetectionIntervalVarName is not set.
+	DefaultDriftDetectionInterval = 5 * time.Minute
 )
 
 // EnvVariables is a struct storing values for environment variables.
 type EnvVariables struct {
-	SubscriptionID             string
-	ResourceGroupName          string
-	AppGwName                  string
-	AuthLocation               string
-	WatchNamespace             string
-	UsePrivateIP               string
-	VerbosityLevel             string
-	EnableBrownfieldDeployment string
-	EnableIstioIntegration     string
-	EnableSaveConfigToFile     string
-	EnablePanicOnPutError      string
+	SubscriptionID              string
+	ResourceGroupName           string
+	AppGwName                   string
+	AuthLocation                string
+	WatchNamespace              string
+	WatchNamespaceSelector      string
+	UsePrivateIP                string
+	VerbosityLevel              string
+	EnableBrownfieldDeployment  string
+	EnableIstioIntegration      string
+	EnableGatewayAPI            string
+	EnableSaveConfigToFile      string
+	EnablePanicOnPutError       string
+	EnableOptimisticConcurrency string
+	DriftDetectionMode          string
+	DriftDetectionInterval      string
+	SnapshotCount               string
+	EnableRollbackOnFailure     string
+	EnableOwnershipTracking     string
+	AGICPodNamespace            string
+	DeletionGuardMaxPercent     string
+	DeletionGuardMaxCount       string
+	AllowMassDeletion           string
+	BackendMode                 string
 }
 
 // GetEnv returns values for defined environment variables for Ingress Controller.
 func GetEnv() EnvVariables {
 	env := EnvVariables{
-		SubscriptionID:             os.Getenv(SubscriptionIDVarName),
-		ResourceGroupName:          os.Getenv(ResourceGroupNameVarName),
-		AppGwName:                  os.Getenv(AppGwNameVarName),
-		AuthLocation:               os.Getenv(AuthLocationVarName),
-		WatchNamespace:             os.Getenv(WatchNamespaceVarName),
-		UsePrivateIP:               os.Getenv(UsePrivateIPVarName),
-		VerbosityLevel:             os.Getenv(VerbosityLevelVarName),
-		EnableBrownfieldDeployment: os.Getenv(EnableBrownfieldDeploymentVarName),
-		EnableIstioIntegration:     os.Getenv(EnableIstioIntegrationVarName),
-		EnableSaveConfigToFile:     os.Getenv(EnableSaveConfigToFileVarName),
-		EnablePanicOnPutError:      os.Getenv(EnablePanicOnPutErrorVarName),
+		SubscriptionID:              os.Getenv(SubscriptionIDVarName),
+		ResourceGroupName:           os.Getenv(ResourceGroupNameVarName),
+		AppGwName:                   os.Getenv(AppGwNameVarName),
+		AuthLocation:                os.Getenv(AuthLocationVarName),
+		WatchNamespace:              os.Getenv(WatchNamespaceVarName),
+		WatchNamespaceSelector:      os.Getenv(WatchNamespaceSelectorVarName),
+		UsePrivateIP:                os.Getenv(UsePrivateIPVarName),
+		VerbosityLevel:              os.Getenv(VerbosityLevelVarName),
+		EnableBrownfieldDeployment:  os.Getenv(EnableBrownfieldDeploymentVarName),
+		EnableIstioIntegration:      os.Getenv(EnableIstioIntegrationVarName),
+		EnableGatewayAPI:            os.Getenv(EnableGatewayAPIVarName),
+		EnableSaveConfigToFile:      os.Getenv(EnableSaveConfigToFileVarName),
+		EnablePanicOnPutError:       os.Getenv(EnablePanicOnPutErrorVarName),
+		EnableOptimisticConcurrency: os.Getenv(EnableOptimisticConcurrencyVarName),
+		DriftDetectionMode:          os.Getenv(DriftDetectionModeVarName),
+		DriftDetectionInterval:      os.Getenv(DriftDetectionIntervalVarName),
+		SnapshotCount:               os.Getenv(SnapshotCountVarName),
+		EnableRollbackOnFailure:     os.Getenv(EnableRollbackOnFailureVarName),
+		EnableOwnershipTracking:     os.Getenv(EnableOwnershipTrackingVarName),
+		AGICPodNamespace:            os.Getenv(AGICPodNamespaceVarName),
+		DeletionGuardMaxPercent:     os.Getenv(DeletionGuardMaxPercentVarName),
+		DeletionGuardMaxCount:       os.Getenv(DeletionGuardMaxCountVarName),
+		AllowMassDeletion:           os.Getenv(AllowMassDeletionVarName),
+		BackendMode:                 os.Getenv(BackendModeVarName),
 	}
 
 	return env
@@ -88,12 +174,79 @@ func ValidateEnv(env EnvVariables) error {
 		return errors.New("environment variables SubscriptionID, ResourceGroupname and AppGwName are required")
 	}
 
-	if env.WatchNamespace == "" {
-		glog.V(1).Infof("%s is not set. Watching all available namespaces.", WatchNamespaceVarName)
+	if env.WatchNamespace == "" && env.WatchNamespaceSelector == "" {
+		glog.V(1).Infof("%s and %s are not set. Watching all available namespaces.", WatchNamespaceVarName, WatchNamespaceSelectorVarName)
+	}
+
+	if env.WatchNamespaceSelector != "" {
+		if _, err := labels.Parse(env.WatchNamespaceSelector); err != nil {
+			return fmt.Errorf("environment variable %s must be a label selector, for instance agic=enabled: %s", WatchNamespaceSelectorVarName, err)
+		}
+	}
+
+	if env.DriftDetectionMode != "" && env.DriftDetectionMode != DriftDetectionModeObserve && env.DriftDetectionMode != DriftDetectionModeEnforce {
+		return fmt.Errorf("environment variable %s must be one of %s or %s", DriftDetectionModeVarName, DriftDetectionModeObserve, DriftDetectionModeEnforce)
+	}
+
+	if env.DriftDetectionInterval != "" {
+		if _, err := time.ParseDuration(env.DriftDetectionInterval); err != nil {
+			return fmt.Errorf("environment variable %s must be a duration, for instance 5m: %s", DriftDetectionIntervalVarName, err)
+		}
+	}
+
+	if env.SnapshotCount != "" {
+		if count, err := strconv.Atoi(env.SnapshotCount); err != nil || count < 0 {
+			return fmt.Errorf("environment variable %s must be a non-negative number", SnapshotCountVarName)
+		}
+	}
+
+	if env.GetSnapshotCount() > 0 && env.AGICPodNamespace == "" {
+		return fmt.Errorf("environment variable %s is required to keep config snapshots", AGICPodNamespaceVarName)
+	}
+
+	if env.EnableRollbackOnFailure == "true" && env.GetSnapshotCount() == 0 {
+		return fmt.Errorf("environment variable %s requires %s to be set", EnableRollbackOnFailureVarName, SnapshotCountVarName)
+	}
+
+	if env.EnableOwnershipTracking == "true" && env.AGICPodNamespace == "" {
+		return fmt.Errorf("environment variable %s is required to track the ownership of App Gateway sub-resources", AGICPodNamespaceVarName)
+	}
+
+	if env.BackendMode != "" && !annotations.IsBackendMode(env.BackendMode) {
+		return fmt.Errorf("environment variable %s must be one of %s", BackendModeVarName, strings.Join(annotations.BackendModes, ", "))
+	}
+
+	if env.DeletionGuardMaxPercent != "" {
+		if percent, err := strconv.Atoi(env.DeletionGuardMaxPercent); err != nil || percent < 0 || percent > 100 {
+			return fmt.Errorf("environment variable %s must be a number between 0 and 100", DeletionGuardMaxPercentVarName)
+		}
+	}
+
+	if env.DeletionGuardMaxCount != "" {
+		if count, err := strconv.Atoi(env.DeletionGuardMaxCount); err != nil || count < 0 {
+			return fmt.Errorf("environment variable %s must be a non-negative number", DeletionGuardMaxCountVarName)
+		}
 	}
 	return nil
 }
 
+// GetSnapshotCount returns the number of App Gateway config snapshots AGIC keeps.
+func (env EnvVariables) GetSnapshotCount() int {
+	count, err := strconv.Atoi(env.SnapshotCount)
+	if err != nil || count < 0 {
+		return 0
+	}
+	return count
+}
+
+// GetDriftDetectionInterval returns the interval between two drift checks.
+func (env EnvVariables) GetDriftDetectionInterval() time.Duration {
+	if interval, err := time.ParseDuration(env.DriftDetectionInterval); err == nil && interval > 0 {
+		return interval
+	}
+	return DefaultDriftDetectionInterval
+}
+
 // GetEnvironmentVariable is an augmentation of os.Getenv, providing it with a default value.
 func GetEnvironmentVariable(environmentVariable, defaultValue string, validator *regexp.Regexp) string {
 	if value, ok := os.LookupEnv(environmentVariable); ok {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"regexp"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -58,30 +59,122 @@ var _ = Describe("Environment", func() {
 				_ = os.Setenv(AppGwNameVarName, "AppGwNameVarName")
 				_ = os.Setenv(AuthLocationVarName, "AuthLocationVarName")
 				_ = os.Setenv(WatchNamespaceVarName, "WatchNamespaceVarName")
+				_ = os.Setenv(WatchNamespaceSelectorVarName, "agic=enabled")
 				_ = os.Setenv(UsePrivateIPVarName, "UsePrivateIPVarName")
 				_ = os.Setenv(VerbosityLevelVarName, "VerbosityLevelVarName")
 				_ = os.Setenv(EnableBrownfieldDeploymentVarName, "EnableBrownfieldDeploymentVarName")
 				_ = os.Setenv(EnableIstioIntegrationVarName, "EnableIstioIntegrationVarName")
 				_ = os.Setenv(EnableSaveConfigToFileVarName, "EnableSaveConfigToFileVarName")
 				_ = os.Setenv(EnablePanicOnPutErrorVarName, "EnablePanicOnPutErrorVarName")
+				_ = os.Setenv(EnableOptimisticConcurrencyVarName, "EnableOptimisticConcurrencyVarName")
+				_ = os.Setenv(DriftDetectionModeVarName, "observe")
+				_ = os.Setenv(DriftDetectionIntervalVarName, "10m")
+				_ = os.Setenv(SnapshotCountVarName, "5")
+				_ = os.Setenv(EnableRollbackOnFailureVarName, "true")
+				_ = os.Setenv(EnableOwnershipTrackingVarName, "true")
+				_ = os.Setenv(AGICPodNamespaceVarName, "AGICPodNamespaceVarName")
+				_ = os.Setenv(DeletionGuardMaxPercentVarName, "50")
+				_ = os.Setenv(DeletionGuardMaxCountVarName, "10")
+				_ = os.Setenv(AllowMassDeletionVarName, "AllowMassDeletionVarName")
+				_ = os.Setenv(BackendModeVarName, "clusterip")
 
 				expected := EnvVariables{
-					SubscriptionID:             "SubscriptionIDVarName",
-					ResourceGroupName:          "ResourceGroupNameVarName",
-					AppGwName:                  "AppGwNameVarName",
-					AuthLocation:               "AuthLocationVarName",
-					WatchNamespace:             "WatchNamespaceVarName",
-					UsePrivateIP:               "UsePrivateIPVarName",
-					VerbosityLevel:             "VerbosityLevelVarName",
-					EnableBrownfieldDeployment: "EnableBrownfieldDeploymentVarName",
-					EnableIstioIntegration:     "EnableIstioIntegrationVarName",
-					EnableSaveConfigToFile:     "EnableSaveConfigToFileVarName",
-					EnablePanicOnPutError:      "EnablePanicOnPutErrorVarName",
+					SubscriptionID:              "SubscriptionIDVarName",
+					ResourceGroupName:           "ResourceGroupNameVarName",
+					AppGwName:                   "AppGwNameVarName",
+					AuthLocation:                "AuthLocationVarName",
+					WatchNamespace:              "WatchNamespaceVarName",
+					WatchNamespaceSelector:      "agic=enabled",
+					UsePrivateIP:                "UsePrivateIPVarName",
+					VerbosityLevel:              "VerbosityLevelVarName",
+					EnableBrownfieldDeployment:  "EnableBrownfieldDeploymentVarName",
+					EnableIstioIntegration:      "EnableIstioIntegrationVarName",
+					EnableSaveConfigToFile:      "EnableSaveConfigToFileVarName",
+					EnablePanicOnPutError:       "EnablePanicOnPutErrorVarName",
+					EnableOptimisticConcurrency: "EnableOptimisticConcurrencyVarName",
+					DriftDetectionMode:          "observe",
+					DriftDetectionInterval:      "10m",
+					SnapshotCount:               "5",
+					EnableRollbackOnFailure:     "true",
+					EnableOwnershipTracking:     "true",
+					AGICPodNamespace:            "AGICPodNamespaceVarName",
+					DeletionGuardMaxPercent:     "50",
+					DeletionGuardMaxCount:       "10",
+					AllowMassDeletion:           "AllowMassDeletionVarName",
+					BackendMode:                 "clusterip",
 				}
 
 				Expect(GetEnv()).To(Equal(expected))
 				err := ValidateEnv(GetEnv())
 				Expect(err).ToNot(HaveOccurred())
+				Expect(GetEnv().GetDriftDetectionInterval()).To(Equal(10 * time.Minute))
+			})
+
+			It("ValidateEnv rejects unknown drift detection modes", func() {
+				env := GetFakeEnv()
+				env.DriftDetectionMode = "revert"
+				Expect(ValidateEnv(env)).To(HaveOccurred())
+
+				env.DriftDetectionMode = DriftDetectionModeEnforce
+				Expect(ValidateEnv(env)).ToNot(HaveOccurred())
+				Expect(env.GetDriftDetectionInterval()).To(Equal(DefaultDriftDetectionInterval))
+			})
+
+			It("ValidateEnv rejects unknown backend modes", func() {
+				env := GetFakeEnv()
+				env.BackendMode = "hostnetwork"
+				Expect(ValidateEnv(env)).To(HaveOccurred())
+
+				env.BackendMode = "nodeport"
+				Expect(ValidateEnv(env)).ToNot(HaveOccurred())
+			})
+
+			It("ValidateEnv checks snapshot settings", func() {
+				env := GetFakeEnv()
+				env.SnapshotCount = "-1"
+				Expect(ValidateEnv(env)).To(HaveOccurred())
+
+				env.SnapshotCount = "3"
+				Expect(ValidateEnv(env)).To(HaveOccurred())
+
+				env.AGICPodNamespace = "agic"
+				Expect(ValidateEnv(env)).ToNot(HaveOccurred())
+				Expect(env.GetSnapshotCount()).To(Equal(3))
+
+				env.SnapshotCount = ""
+				env.EnableRollbackOnFailure = "true"
+				Expect(ValidateEnv(env)).To(HaveOccurred())
+			})
+
+			It("ValidateEnv checks ownership tracking settings", func() {
+				env := GetFakeEnv()
+				env.EnableOwnershipTracking = "true"
+				Expect(ValidateEnv(env)).To(HaveOccurred())
+
+				env.AGICPodNamespace = "agic"
+				Expect(ValidateEnv(env)).ToNot(HaveOccurred())
+			})
+
+			It("ValidateEnv checks deletion guard settings", func() {
+				env := GetFakeEnv()
+				env.DeletionGuardMaxPercent = "101"
+				Expect(ValidateEnv(env)).To(HaveOccurred())
+
+				env.DeletionGuardMaxPercent = "50"
+				env.DeletionGuardMaxCount = "many"
+				Expect(ValidateEnv(env)).To(HaveOccurred())
+
+				env.DeletionGuardMaxCount = "10"
+				Expect(ValidateEnv(env)).ToNot(HaveOccurred())
+			})
+
+			It("ValidateEnv checks the namespace selector", func() {
+				env := GetFakeEnv()
+				env.WatchNamespaceSelector = "agic in (enabled"
+				Expect(ValidateEnv(env)).To(HaveOccurred())
+
+				env.WatchNamespaceSelector = "agic in (enabled),team!=legacy"
+				Expect(ValidateEnv(env)).ToNot(HaveOccurred())
 			})
 		})
 
@@ -17,6 +17,9 @@ const (
 
 	// Delete is a type of a Kubernetes API event.
 	Delete
+
+	// DriftCheck is a periodic event, which makes AGIC compare App Gateway with the config it last applied.
+	DriftCheck
 )
 
 // EventTypeLookup is a reverse map of the EventType enums; used for logging purposes
@@ -24,6 +27,7 @@ var EventTypeLookup = map[EventType]string{
 	1: "Create",
 	2: "Update",
 	3: "Delete",
+	4: "DriftCheck",
 }
 
 // Event is the combined type and actual object we received from Kubernetes
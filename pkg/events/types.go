@@ -32,4 +32,40 @@ const (
 
 	// ReasonUnableToUpdateIngressStatus is a reason for an event to be emitted.
 	ReasonUnableToUpdateIngressStatus = "UnableToUpdateIngressStatus"
+
+	// ReasonAppGatewayModifiedConcurrently is a reason for an event to be emitted.
+	ReasonAppGatewayModifiedConcurrently = "AppGatewayModifiedConcurrently"
+
+	// ReasonAppGatewayConfigDrift is a reason for an event to be emitted.
+	ReasonAppGatewayConfigDrift = "AppGatewayConfigDrift"
+
+	// ReasonAppGatewayRollback is a reason for an event to be emitted.
+	ReasonAppGatewayRollback = "AppGatewayRollback"
+
+	// ReasonMassDeletionBlocked is a reason for an event to be emitted.
+	ReasonMassDeletionBlocked = "MassDeletionBlocked"
+
+	// ReasonPartialPathOverlap is a reason for an event to be emitted.
+	ReasonPartialPathOverlap = "PartialPathOverlap"
+
+	// ReasonHostPathConflict is a reason for an event to be emitted.
+	ReasonHostPathConflict = "HostPathConflict"
+
+	// ReasonHostnameNotOwned is a reason for an event to be emitted.
+	ReasonHostnameNotOwned = "HostnameNotOwned"
+
+	// ReasonUnsupportedMatch is a reason for an event to be emitted.
+	ReasonUnsupportedMatch = "UnsupportedMatch"
+
+	// ReasonUnsupportedRouteFeature is a reason for an event to be emitted.
+	ReasonUnsupportedRouteFeature = "UnsupportedRouteFeature"
+
+	// ReasonShadowedRoute is a reason for an event to be emitted.
+	ReasonShadowedRoute = "ShadowedRoute"
+
+	// ReasonTrafficSplit is a reason for an event to be emitted.
+	ReasonTrafficSplit = "TrafficSplit"
+
+	// ReasonBackendModeUnavailable is a reason for an event to be emitted.
+	ReasonBackendModeUnavailable = "BackendModeUnavailable"
 )
@@ -0,0 +1,73 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package gatewayapi
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gwv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+)
+
+// Condition types AGIC reports on GatewayClasses, Gateways, their listeners and HTTPRoutes.
+const (
+	// ConditionAccepted tells whether AGIC accepted the resource.
+	ConditionAccepted = "Accepted"
+
+	// ConditionResolvedRefs tells whether AGIC resolved the Secrets and Services the resource refers to.
+	ConditionResolvedRefs = "ResolvedRefs"
+
+	// ConditionProgrammed tells whether App Gateway serves the config AGIC translated from the resource.
+	ConditionProgrammed = "Programmed"
+)
+
+// Condition reasons.
+const (
+	ReasonAccepted                   = "Accepted"
+	ReasonResolvedRefs               = "ResolvedRefs"
+	ReasonProgrammed                 = "Programmed"
+	ReasonInvalid                    = "Invalid"
+	ReasonListenersNotValid          = "ListenersNotValid"
+	ReasonUnsupportedProtocol        = "UnsupportedProtocol"
+	ReasonPortUnavailable            = "PortUnavailable"
+	ReasonUnsupportedValue           = "UnsupportedValue"
+	ReasonInvalidCertificateRef      = "InvalidCertificateRef"
+	ReasonRefNotPermitted            = "RefNotPermitted"
+	ReasonInvalidKind                = "InvalidKind"
+	ReasonBackendNotFound            = "BackendNotFound"
+	ReasonNoMatchingParent           = "NoMatchingParent"
+	ReasonNotAllowedByListeners      = "NotAllowedByListeners"
+	ReasonNoMatchingListenerHostname = "NoMatchingListenerHostname"
+)
+
+func newCondition(conditionType string, status gwv1.ConditionStatus, reason string, message string, generation int64) gwv1.Condition {
+	return gwv1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+func isTrue(condition gwv1.Condition) bool {
+	return condition.Status == gwv1.ConditionTrue
+}
+
+// mergeConditions sets the transition time of each condition; Conditions, which did not change their status since the existing
+// ones, keep the existing transition time, so that writing an unchanged status back is a no-op.
+func mergeConditions(existing []gwv1.Condition, conditions []gwv1.Condition, now metav1.Time) []gwv1.Condition {
+	var merged []gwv1.Condition
+	for _, condition := range conditions {
+		condition.LastTransitionTime = now
+		for _, previous := range existing {
+			if previous.Type == condition.Type && previous.Status == condition.Status {
+				condition.LastTransitionTime = previous.LastTransitionTime
+			}
+		}
+		merged = append(merged, condition)
+	}
+	return merged
+}
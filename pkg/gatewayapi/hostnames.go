@@ -0,0 +1,73 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package gatewayapi
+
+import (
+	"strings"
+)
+
+// intersectHostnames returns the hostnames of the route, which the listener accepts. A listener without hostname accepts
+// all hostnames of the route; A route without hostnames takes the hostname of the listener. The empty hostname stands
+// for any hostname. Nil means the listener accepts none of the hostnames of the route.
+func intersectHostnames(listenerHostname *string, routeHostnames []string) []string {
+	if listenerHostname == nil || *listenerHostname == "" {
+		if len(routeHostnames) == 0 {
+			return []string{""}
+		}
+		return dedupeHostnames(routeHostnames)
+	}
+	if len(routeHostnames) == 0 {
+		return []string{*listenerHostname}
+	}
+
+	var hostnames []string
+	for _, routeHostname := range routeHostnames {
+		switch {
+		case strings.EqualFold(routeHostname, *listenerHostname):
+			hostnames = append(hostnames, routeHostname)
+		case matchesWildcard(*listenerHostname, routeHostname):
+			hostnames = append(hostnames, routeHostname)
+		case matchesWildcard(routeHostname, *listenerHostname):
+			hostnames = append(hostnames, *listenerHostname)
+		}
+	}
+	return dedupeHostnames(hostnames)
+}
+
+// getWildcardHostnames returns the wildcard hostnames, like *.example.com, among the given hostnames.
+func getWildcardHostnames(hostnames []string) []string {
+	var wildcards []string
+	for _, hostname := range hostnames {
+		if strings.HasPrefix(hostname, "*") {
+			wildcards = append(wildcards, hostname)
+		}
+	}
+	return wildcards
+}
+
+// matchesWildcard figures out whether the wildcard hostname, like *.example.com, matches the hostname, which has at least one more label.
+func matchesWildcard(wildcard string, hostname string) bool {
+	if !strings.HasPrefix(wildcard, "*.") {
+		return false
+	}
+	suffix := strings.ToLower(wildcard[1:])
+	hostname = strings.ToLower(hostname)
+	return strings.HasSuffix(hostname, suffix) && len(hostname) > len(suffix)
+}
+
+func dedupeHostnames(hostnames []string) []string {
+	seen := make(map[string]interface{})
+	var unique []string
+	for _, hostname := range hostnames {
+		key := strings.ToLower(hostname)
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = nil
+		unique = append(unique, hostname)
+	}
+	return unique
+}
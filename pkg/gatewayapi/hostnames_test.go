@@ -0,0 +1,45 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package gatewayapi
+
+import (
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("test intersecting listener and route hostnames", func() {
+	Context("test intersectHostnames", func() {
+		It("matches any hostname when neither the listener nor the route has one", func() {
+			Expect(intersectHostnames(nil, nil)).To(Equal([]string{""}))
+		})
+
+		It("takes the hostnames of the route when the listener has none", func() {
+			Expect(intersectHostnames(to.StringPtr(""), []string{"a.com", "A.com", "b.com"})).To(Equal([]string{"a.com", "b.com"}))
+		})
+
+		It("takes the hostname of the listener when the route has none", func() {
+			Expect(intersectHostnames(to.StringPtr("a.com"), nil)).To(Equal([]string{"a.com"}))
+		})
+
+		It("keeps the route hostnames the wildcard listener accepts", func() {
+			Expect(intersectHostnames(to.StringPtr("*.example.com"), []string{"www.example.com", "example.com", "other.com"})).To(Equal([]string{"www.example.com"}))
+		})
+
+		It("narrows a wildcard route hostname to the listener hostname", func() {
+			Expect(intersectHostnames(to.StringPtr("www.example.com"), []string{"*.example.com"})).To(Equal([]string{"www.example.com"}))
+		})
+
+		It("keeps the wildcard hostname of the listener when the route has none", func() {
+			hostnames := intersectHostnames(to.StringPtr("*.example.com"), nil)
+			Expect(getWildcardHostnames(hostnames)).To(Equal([]string{"*.example.com"}))
+		})
+
+		It("returns nil when no hostname matches", func() {
+			Expect(intersectHostnames(to.StringPtr("a.com"), []string{"b.com"})).To(BeNil())
+		})
+	})
+})
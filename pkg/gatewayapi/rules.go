@@ -0,0 +1,155 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package gatewayapi
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	gwv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// resolveBackendRefs returns the ResolvedRefs condition of the route; False with the first backend reference AGIC cannot resolve.
+func resolveBackendRefs(route *gwv1.HTTPRoute, services map[string]*v1.Service) gwv1.Condition {
+	for _, rule := range route.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			if reason, message := resolveBackendRef(route, backendRef.BackendObjectReference, services); reason != "" {
+				return newCondition(ConditionResolvedRefs, gwv1.ConditionFalse, reason, message, route.Generation)
+			}
+		}
+	}
+	return newCondition(ConditionResolvedRefs, gwv1.ConditionTrue, ReasonResolvedRefs, "References are resolved", route.Generation)
+}
+
+// resolveBackendRef returns the reason and message why AGIC cannot resolve the backend reference, or an empty reason.
+func resolveBackendRef(route *gwv1.HTTPRoute, ref gwv1.BackendObjectReference, services map[string]*v1.Service) (string, string) {
+	if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != "Service") {
+		return ReasonInvalidKind, fmt.Sprintf("Backend %s is not a Service", ref.Name)
+	}
+	if ref.Namespace != nil && *ref.Namespace != route.Namespace {
+		return ReasonRefNotPermitted, fmt.Sprintf("Service %s/%s is not in the namespace of the route", *ref.Namespace, ref.Name)
+	}
+	serviceKey := utils.GetResourceKey(route.Namespace, ref.Name)
+	if _, exists := services[serviceKey]; !exists {
+		return ReasonBackendNotFound, fmt.Sprintf("Service %s does not exist", serviceKey)
+	}
+	if ref.Port == nil {
+		return ReasonBackendNotFound, fmt.Sprintf("Backend reference to Service %s has no port", serviceKey)
+	}
+	return "", ""
+}
+
+// translateRules translates the matches of the rules of the route into Ingress paths, and lists the features of the route App Gateway does not support.
+func translateRules(route *gwv1.HTTPRoute, services map[string]*v1.Service) ([]v1beta1.HTTPIngressPath, []string) {
+	var paths []v1beta1.HTTPIngressPath
+	var ignored []string
+	seen := make(map[string]interface{})
+	for ruleIdx, rule := range route.Spec.Rules {
+		backend, notes := selectBackend(route, rule, services)
+		for _, note := range notes {
+			ignored = append(ignored, fmt.Sprintf("rule %d: %s", ruleIdx, note))
+		}
+		if len(rule.Filters) > 0 {
+			ignored = append(ignored, fmt.Sprintf("rule %d: filters", ruleIdx))
+		}
+		if backend == nil {
+			continue
+		}
+
+		matches := rule.Matches
+		if len(matches) == 0 {
+			matches = []gwv1.HTTPRouteMatch{{}}
+		}
+		for matchIdx, match := range matches {
+			if len(match.Headers) > 0 || len(match.QueryParams) > 0 || match.Method != nil {
+				ignored = append(ignored, fmt.Sprintf("rule %d match %d: header, query parameter and method matches", ruleIdx, matchIdx))
+				continue
+			}
+			matchPaths, supported := translatePathMatch(match.Path)
+			if !supported {
+				ignored = append(ignored, fmt.Sprintf("rule %d match %d: regular expression paths", ruleIdx, matchIdx))
+				continue
+			}
+			for _, path := range matchPaths {
+				// The first rule matching a path wins, as with the precedence of the Gateway API.
+				if _, exists := seen[path]; exists {
+					continue
+				}
+				seen[path] = nil
+				paths = append(paths, v1beta1.HTTPIngressPath{
+					Path:    path,
+					Backend: *backend,
+				})
+			}
+		}
+	}
+	return paths, ignored
+}
+
+// selectBackend returns the backend of the rule; App Gateway sends all requests of a path rule to one pool, hence the first resolved
+// backend with a weight gets all requests of the rule.
+func selectBackend(route *gwv1.HTTPRoute, rule gwv1.HTTPRouteRule, services map[string]*v1.Service) (*v1beta1.IngressBackend, []string) {
+	var notes []string
+	var backend *v1beta1.IngressBackend
+	for _, backendRef := range rule.BackendRefs {
+		if len(backendRef.Filters) > 0 {
+			notes = append(notes, fmt.Sprintf("filters of backend %s", backendRef.Name))
+		}
+		if backendRef.Weight != nil && *backendRef.Weight == 0 {
+			continue
+		}
+		if reason, _ := resolveBackendRef(route, backendRef.BackendObjectReference, services); reason != "" {
+			continue
+		}
+		if backend != nil {
+			notes = append(notes, fmt.Sprintf("weighted backend %s; Service %s gets all requests", backendRef.Name, backend.ServiceName))
+			continue
+		}
+		backend = &v1beta1.IngressBackend{
+			ServiceName: backendRef.Name,
+			ServicePort: intstr.FromInt(int(*backendRef.Port)),
+		}
+	}
+	return backend, notes
+}
+
+// translatePathMatch translates the path match into Ingress paths; App Gateway matches a path exactly, or as a prefix with a trailing /*.
+// False when App Gateway does not support the match.
+func translatePathMatch(match *gwv1.HTTPPathMatch) ([]string, bool) {
+	matchType := gwv1.PathMatchPathPrefix
+	value := "/"
+	if match != nil {
+		if match.Type != nil {
+			matchType = *match.Type
+		}
+		if match.Value != nil {
+			value = *match.Value
+		}
+	}
+
+	switch matchType {
+	case gwv1.PathMatchExact:
+		return []string{value}, true
+	case gwv1.PathMatchPathPrefix:
+		prefix := strings.TrimRight(value, "/")
+		if prefix == "" {
+			return []string{"/*"}, true
+		}
+		// A prefix matches whole path elements: /foo matches /foo and /foo/bar, but not /foobar.
+		return []string{prefix, prefix + "/*"}, true
+	default:
+		return nil, false
+	}
+}
+
+func joinIgnored(ignored []string) string {
+	return strings.Join(ignored, "; ")
+}
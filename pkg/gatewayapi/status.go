@@ -0,0 +1,154 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package gatewayapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gwv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+)
+
+// ClassStatus pairs a GatewayClass with the status AGIC reports for it.
+type ClassStatus struct {
+	Class  *gwv1.GatewayClass
+	Status gwv1.GatewayClassStatus
+}
+
+// GatewayStatus pairs a Gateway with the status AGIC reports for it.
+type GatewayStatus struct {
+	Gateway *gwv1.Gateway
+	Status  gwv1.GatewayStatus
+}
+
+// RouteStatus pairs an HTTPRoute with the status AGIC reports for it.
+type RouteStatus struct {
+	Route  *gwv1.HTTPRoute
+	Status gwv1.HTTPRouteStatus
+}
+
+// ClassStatuses returns the statuses of the GatewayClasses AGIC implements.
+func (t *Translation) ClassStatuses(now metav1.Time) []ClassStatus {
+	var statuses []ClassStatus
+	for _, class := range t.classes {
+		conditions := []gwv1.Condition{
+			newCondition(ConditionAccepted, gwv1.ConditionTrue, ReasonAccepted, "GatewayClass is accepted", class.Generation),
+		}
+		statuses = append(statuses, ClassStatus{
+			Class: class,
+			Status: gwv1.GatewayClassStatus{
+				Conditions: mergeConditions(class.Status.Conditions, conditions, now),
+			},
+		})
+	}
+	return statuses
+}
+
+// GatewayStatuses returns the statuses of the Gateways of the GatewayClasses AGIC implements. The error is the failure to apply
+// the config to App Gateway, if any; The addresses are the frontend IP addresses of App Gateway.
+func (t *Translation) GatewayStatuses(programErr error, addresses []gwv1.GatewayAddress, now metav1.Time) []GatewayStatus {
+	var statuses []GatewayStatus
+	for _, result := range t.gateways {
+		gateway := result.gateway
+		generation := gateway.Generation
+		status := gwv1.GatewayStatus{
+			Addresses: addresses,
+		}
+
+		for _, listener := range result.listeners {
+			var existing []gwv1.Condition
+			for _, listenerStatus := range gateway.Status.Listeners {
+				if listenerStatus.Name == listener.listener.Name {
+					existing = listenerStatus.Conditions
+				}
+			}
+			conditions := []gwv1.Condition{
+				listener.accepted,
+				listener.resolvedRefs,
+				programmed(listener.isValid(), "Listener is invalid", programErr, generation),
+			}
+			group := gwv1.SchemeGroupVersion.Group
+			status.Listeners = append(status.Listeners, gwv1.ListenerStatus{
+				Name:           listener.listener.Name,
+				SupportedKinds: []gwv1.RouteGroupKind{{Group: &group, Kind: "HTTPRoute"}},
+				AttachedRoutes: listener.attachedRoutes,
+				Conditions:     mergeConditions(existing, conditions, now),
+			})
+		}
+
+		conditions := []gwv1.Condition{
+			result.accepted,
+			programmed(isTrue(result.accepted), "Gateway is not accepted", programErr, generation),
+		}
+		status.Conditions = mergeConditions(gateway.Status.Conditions, conditions, now)
+		statuses = append(statuses, GatewayStatus{
+			Gateway: gateway,
+			Status:  status,
+		})
+	}
+	return statuses
+}
+
+// RouteStatuses returns the statuses of the HTTPRoutes attached to the Gateways AGIC implements. The statuses keep the parents other controllers report.
+func (t *Translation) RouteStatuses(now metav1.Time) []RouteStatus {
+	var statuses []RouteStatus
+	for _, result := range t.routes {
+		route := result.route
+		status := gwv1.HTTPRouteStatus{}
+		for _, parent := range route.Status.Parents {
+			if parent.ControllerName != ControllerName {
+				status.Parents = append(status.Parents, parent)
+			}
+		}
+
+		for _, parent := range result.parents {
+			var existing []gwv1.Condition
+			for _, parentStatus := range route.Status.Parents {
+				if parentStatus.ControllerName == ControllerName && sameParentReference(parentStatus.ParentRef, parent.ref) {
+					existing = parentStatus.Conditions
+				}
+			}
+			accepted := parent.accepted
+			if len(parent.pruned) > 0 {
+				message := fmt.Sprintf("App Gateway does not serve parts of the route: %s", strings.Join(parent.pruned, "; "))
+				accepted = newCondition(ConditionAccepted, gwv1.ConditionFalse, parent.prunedReason, message, route.Generation)
+			}
+			status.Parents = append(status.Parents, gwv1.RouteParentStatus{
+				ParentRef:      parent.ref,
+				ControllerName: ControllerName,
+				Conditions:     mergeConditions(existing, []gwv1.Condition{accepted, parent.resolvedRefs}, now),
+			})
+		}
+
+		statuses = append(statuses, RouteStatus{
+			Route:  route,
+			Status: status,
+		})
+	}
+	return statuses
+}
+
+// programmed returns the Programmed condition of a resource, which App Gateway serves when the resource is valid and AGIC applied the config.
+func programmed(valid bool, invalidMessage string, programErr error, generation int64) gwv1.Condition {
+	switch {
+	case !valid:
+		return newCondition(ConditionProgrammed, gwv1.ConditionFalse, ReasonInvalid, invalidMessage, generation)
+	case programErr != nil:
+		return newCondition(ConditionProgrammed, gwv1.ConditionFalse, ReasonInvalid, fmt.Sprintf("Applying the config to App Gateway failed: %s", programErr), generation)
+	default:
+		return newCondition(ConditionProgrammed, gwv1.ConditionTrue, ReasonProgrammed, "App Gateway serves the config", generation)
+	}
+}
+
+func sameParentReference(a gwv1.ParentReference, b gwv1.ParentReference) bool {
+	return a.Name == b.Name &&
+		to.String(a.Namespace) == to.String(b.Namespace) &&
+		to.String(a.SectionName) == to.String(b.SectionName) &&
+		to.Int32(a.Port) == to.Int32(b.Port)
+}
@@ -0,0 +1,400 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+// Package gatewayapi translates the Gateways and HTTPRoutes of the Kubernetes Gateway API into Ingresses, from which AGIC
+// builds App Gateway listeners, path maps, pools and settings the same way it does for the Ingresses of the cluster.
+package gatewayapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	gwv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// ControllerName is the controller name of the GatewayClasses AGIC implements.
+const ControllerName = annotations.ApplicationGatewayPrefix + "/gateway-controller"
+
+// listenerPorts are the frontend ports of the App Gateway listeners AGIC creates for each protocol.
+var listenerPorts = map[gwv1.ProtocolType]int32{
+	gwv1.HTTPProtocolType:  80,
+	gwv1.HTTPSProtocolType: 443,
+}
+
+// Resources are the Kubernetes resources the translation reads.
+type Resources struct {
+	GatewayClasses []*gwv1.GatewayClass
+	Gateways       []*gwv1.Gateway
+	HTTPRoutes     []*gwv1.HTTPRoute
+	Services       []*v1.Service
+	Namespaces     []*v1.Namespace
+
+	// HasCertificate figures out whether the certificate store holds the Secret with the given namespace/name key.
+	HasCertificate func(secretKey string) bool
+}
+
+// Translation holds the Ingresses translated from the Gateway API resources, along with the conditions of these resources.
+type Translation struct {
+	// Ingresses hold a rule per hostname for each listener an HTTPRoute attaches to.
+	Ingresses []*v1beta1.Ingress
+
+	classes  []*gwv1.GatewayClass
+	gateways []*gatewayResult
+	routes   []*routeResult
+
+	// origins are the route and parent reference each Ingress was translated from, keyed by the UID of the Ingress.
+	origins map[types.UID]ingressOrigin
+}
+
+type ingressOrigin struct {
+	route  *routeResult
+	parent int
+}
+
+type gatewayResult struct {
+	gateway   *gwv1.Gateway
+	accepted  gwv1.Condition
+	listeners []*listenerResult
+}
+
+type listenerResult struct {
+	listener       *gwv1.Listener
+	accepted       gwv1.Condition
+	resolvedRefs   gwv1.Condition
+	attachedRoutes int32
+
+	// secretKey is the namespace/name of the certificate of an HTTPS listener.
+	secretKey string
+}
+
+// isValid figures out whether routes may attach to the listener.
+func (l *listenerResult) isValid() bool {
+	return isTrue(l.accepted) && isTrue(l.resolvedRefs)
+}
+
+type routeResult struct {
+	route   *gwv1.HTTPRoute
+	parents []parentResult
+}
+
+type parentResult struct {
+	ref          gwv1.ParentReference
+	accepted     gwv1.Condition
+	resolvedRefs gwv1.Condition
+
+	// prunedReason and pruned tell why AGIC ignored hosts or paths of the Ingresses of the parent, after the translation.
+	prunedReason string
+	pruned       []string
+}
+
+// Translate translates the Gateways of the GatewayClasses AGIC implements, and the HTTPRoutes attached to them, into Ingresses.
+func Translate(resources Resources) *Translation {
+	t := &Translation{origins: make(map[types.UID]ingressOrigin)}
+
+	classes := make(map[string]interface{})
+	for _, class := range resources.GatewayClasses {
+		if class.Spec.ControllerName == ControllerName {
+			classes[class.Name] = nil
+			t.classes = append(t.classes, class)
+		}
+	}
+
+	gateways := make(map[string]*gatewayResult)
+	for _, gateway := range resources.Gateways {
+		if _, exists := classes[gateway.Spec.GatewayClassName]; !exists {
+			continue
+		}
+		result := newGatewayResult(gateway, resources.HasCertificate)
+		gateways[utils.GetResourceKey(gateway.Namespace, gateway.Name)] = result
+		t.gateways = append(t.gateways, result)
+	}
+
+	services := make(map[string]*v1.Service)
+	for _, service := range resources.Services {
+		services[utils.GetResourceKey(service.Namespace, service.Name)] = service
+	}
+
+	namespaces := make(map[string]*v1.Namespace)
+	for _, namespace := range resources.Namespaces {
+		namespaces[namespace.Name] = namespace
+	}
+
+	for _, route := range resources.HTTPRoutes {
+		result := &routeResult{route: route}
+		resolvedRefs := resolveBackendRefs(route, services)
+		paths, ignored := translateRules(route, services)
+		for _, ref := range route.Spec.ParentRefs {
+			if !isGatewayReference(ref) {
+				continue
+			}
+			gatewayNamespace := route.Namespace
+			if ref.Namespace != nil && *ref.Namespace != "" {
+				gatewayNamespace = *ref.Namespace
+			}
+			gateway, exists := gateways[utils.GetResourceKey(gatewayNamespace, ref.Name)]
+			if !exists {
+				// Not a Gateway of a class AGIC implements; Other controllers report the status.
+				continue
+			}
+			translated := len(t.Ingresses)
+			accepted := t.attach(route, ref, gateway, namespaces, paths, ignored)
+			for _, ingress := range t.Ingresses[translated:] {
+				t.origins[ingress.UID] = ingressOrigin{route: result, parent: len(result.parents)}
+			}
+			result.parents = append(result.parents, parentResult{
+				ref:          ref,
+				accepted:     accepted,
+				resolvedRefs: resolvedRefs,
+			})
+		}
+		if len(result.parents) > 0 {
+			t.routes = append(t.routes, result)
+		}
+	}
+
+	return t
+}
+
+func newGatewayResult(gateway *gwv1.Gateway, hasCertificate func(string) bool) *gatewayResult {
+	result := &gatewayResult{gateway: gateway}
+	valid := 0
+	for idx := range gateway.Spec.Listeners {
+		listener := newListenerResult(gateway, &gateway.Spec.Listeners[idx], hasCertificate)
+		if listener.isValid() {
+			valid++
+		}
+		result.listeners = append(result.listeners, listener)
+	}
+
+	if valid == 0 {
+		result.accepted = newCondition(ConditionAccepted, gwv1.ConditionFalse, ReasonListenersNotValid, "None of the listeners of the Gateway is valid", gateway.Generation)
+	} else {
+		result.accepted = newCondition(ConditionAccepted, gwv1.ConditionTrue, ReasonAccepted, "Gateway is accepted", gateway.Generation)
+	}
+	return result
+}
+
+func newListenerResult(gateway *gwv1.Gateway, listener *gwv1.Listener, hasCertificate func(string) bool) *listenerResult {
+	generation := gateway.Generation
+	result := &listenerResult{
+		listener:     listener,
+		accepted:     newCondition(ConditionAccepted, gwv1.ConditionTrue, ReasonAccepted, "Listener is accepted", generation),
+		resolvedRefs: newCondition(ConditionResolvedRefs, gwv1.ConditionTrue, ReasonResolvedRefs, "References are resolved", generation),
+	}
+
+	port, supported := listenerPorts[listener.Protocol]
+	switch {
+	case !supported:
+		result.accepted = newCondition(ConditionAccepted, gwv1.ConditionFalse, ReasonUnsupportedProtocol, fmt.Sprintf("AGIC supports the protocols HTTP and HTTPS; Not %s", listener.Protocol), generation)
+	case listener.Port != port:
+		result.accepted = newCondition(ConditionAccepted, gwv1.ConditionFalse, ReasonPortUnavailable, fmt.Sprintf("AGIC serves %s on port %d only", listener.Protocol, port), generation)
+	case listener.Protocol == gwv1.HTTPSProtocolType:
+		if listener.TLS != nil && listener.TLS.Mode != nil && *listener.TLS.Mode != gwv1.TLSModeTerminate {
+			result.accepted = newCondition(ConditionAccepted, gwv1.ConditionFalse, ReasonUnsupportedValue, fmt.Sprintf("App Gateway terminates TLS; TLS mode %s is not supported", *listener.TLS.Mode), generation)
+			break
+		}
+		reason, message := resolveCertificateRef(gateway, listener, hasCertificate)
+		if reason != "" {
+			result.resolvedRefs = newCondition(ConditionResolvedRefs, gwv1.ConditionFalse, reason, message, generation)
+			break
+		}
+		result.secretKey = utils.GetResourceKey(gateway.Namespace, listener.TLS.CertificateRefs[0].Name)
+	}
+
+	if !result.isValid() {
+		glog.V(3).Infof("Listener %s of Gateway %s/%s is not valid: %s %s", listener.Name, gateway.Namespace, gateway.Name, result.accepted.Message, result.resolvedRefs.Message)
+	}
+	return result
+}
+
+// resolveCertificateRef checks the first certificate reference of the HTTPS listener, which App Gateway serves; Returns the reason
+// and message of the failure, or an empty reason when the certificate is resolved.
+func resolveCertificateRef(gateway *gwv1.Gateway, listener *gwv1.Listener, hasCertificate func(string) bool) (string, string) {
+	if listener.TLS == nil || len(listener.TLS.CertificateRefs) == 0 {
+		return ReasonInvalidCertificateRef, "HTTPS listeners need a certificate reference"
+	}
+	ref := listener.TLS.CertificateRefs[0]
+	if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != "Secret") {
+		return ReasonInvalidCertificateRef, "Certificate references must refer to Secrets"
+	}
+	if ref.Namespace != nil && *ref.Namespace != gateway.Namespace {
+		return ReasonRefNotPermitted, fmt.Sprintf("Secret %s/%s is not in the namespace of the Gateway", *ref.Namespace, ref.Name)
+	}
+	secretKey := utils.GetResourceKey(gateway.Namespace, ref.Name)
+	if hasCertificate != nil && !hasCertificate(secretKey) {
+		return ReasonInvalidCertificateRef, fmt.Sprintf("Secret %s does not exist or does not hold a TLS certificate", secretKey)
+	}
+	return "", ""
+}
+
+// attach attaches the route to the listeners of the Gateway the parent reference selects, and returns the Accepted condition of the route for the reference.
+func (t *Translation) attach(route *gwv1.HTTPRoute, ref gwv1.ParentReference, gateway *gatewayResult, namespaces map[string]*v1.Namespace, paths []v1beta1.HTTPIngressPath, ignored []string) gwv1.Condition {
+	generation := route.Generation
+	var candidates []*listenerResult
+	for _, listener := range gateway.listeners {
+		if ref.SectionName != nil && *ref.SectionName != listener.listener.Name {
+			continue
+		}
+		if ref.Port != nil && *ref.Port != listener.listener.Port {
+			continue
+		}
+		candidates = append(candidates, listener)
+	}
+	if len(candidates) == 0 {
+		return newCondition(ConditionAccepted, gwv1.ConditionFalse, ReasonNoMatchingParent, fmt.Sprintf("Gateway %s/%s has no listener matching the parent reference", gateway.gateway.Namespace, gateway.gateway.Name), generation)
+	}
+
+	attached := false
+	reason, message := ReasonNotAllowedByListeners, "None of the valid listeners allows routes from the namespace of the route"
+	for _, listener := range candidates {
+		if !listener.isValid() || !isRouteAllowed(gateway.gateway, listener.listener, route.Namespace, namespaces) {
+			continue
+		}
+		hostnames := intersectHostnames(listener.listener.Hostname, route.Spec.Hostnames)
+		if len(hostnames) == 0 {
+			reason, message = ReasonNoMatchingListenerHostname, "None of the hostnames of the route matches the hostname of a listener"
+			continue
+		}
+		// App Gateway listeners serve a single exact hostname; A wildcard would have App Gateway reject the whole config.
+		if wildcards := getWildcardHostnames(hostnames); len(wildcards) > 0 {
+			reason, message = ReasonUnsupportedValue, fmt.Sprintf("App Gateway does not support wildcard hostnames: %s", strings.Join(wildcards, ", "))
+			continue
+		}
+		attached = true
+		listener.attachedRoutes++
+		if len(paths) > 0 {
+			t.Ingresses = append(t.Ingresses, newIngress(route, gateway.gateway, listener, hostnames, paths))
+		}
+	}
+
+	if !attached {
+		return newCondition(ConditionAccepted, gwv1.ConditionFalse, reason, message, generation)
+	}
+	message = "Route is accepted"
+	if len(ignored) > 0 {
+		message = fmt.Sprintf("Route is accepted; App Gateway does not support: %s", joinIgnored(ignored))
+	}
+	return newCondition(ConditionAccepted, gwv1.ConditionTrue, ReasonAccepted, message, generation)
+}
+
+// RouteOf returns the HTTPRoute the Ingress was translated from; nil for the Ingresses of the cluster.
+func (t *Translation) RouteOf(ingress *v1beta1.Ingress) *gwv1.HTTPRoute {
+	if t == nil {
+		return nil
+	}
+	origin, exists := t.origins[ingress.UID]
+	if !exists {
+		return nil
+	}
+	return origin.route.route
+}
+
+// Prune records that AGIC ignored hosts or paths of the Ingress, after the translation, for the given reason; The HTTPRoute the Ingress
+// was translated from is no longer accepted by the parent. Prune returns this HTTPRoute; nil for the Ingresses of the cluster.
+func (t *Translation) Prune(ingress *v1beta1.Ingress, reason string, message string) *gwv1.HTTPRoute {
+	if t == nil {
+		return nil
+	}
+	origin, exists := t.origins[ingress.UID]
+	if !exists {
+		return nil
+	}
+	parent := &origin.route.parents[origin.parent]
+	if parent.prunedReason == "" {
+		parent.prunedReason = reason
+	}
+	parent.pruned = append(parent.pruned, message)
+	return origin.route.route
+}
+
+// isGatewayReference figures out whether the parent reference refers to a Gateway.
+func isGatewayReference(ref gwv1.ParentReference) bool {
+	if ref.Group != nil && *ref.Group != gwv1.SchemeGroupVersion.Group {
+		return false
+	}
+	return ref.Kind == nil || *ref.Kind == "Gateway"
+}
+
+// isRouteAllowed figures out whether the listener allows routes from the given namespace; The namespace of the Gateway only, unless stated otherwise.
+func isRouteAllowed(gateway *gwv1.Gateway, listener *gwv1.Listener, namespace string, namespaces map[string]*v1.Namespace) bool {
+	from := gwv1.NamespacesFromSame
+	var selector *metav1.LabelSelector
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil {
+		if listener.AllowedRoutes.Namespaces.From != nil {
+			from = *listener.AllowedRoutes.Namespaces.From
+		}
+		selector = listener.AllowedRoutes.Namespaces.Selector
+	}
+
+	switch from {
+	case gwv1.NamespacesFromAll:
+		return true
+	case gwv1.NamespacesFromSelector:
+		if selector == nil {
+			return false
+		}
+		namespaceSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			glog.Errorf("Listener %s of Gateway %s/%s has an invalid namespace selector: %s", listener.Name, gateway.Namespace, gateway.Name, err)
+			return false
+		}
+		ns, exists := namespaces[namespace]
+		return exists && namespaceSelector.Matches(labels.Set(ns.Labels))
+	default:
+		return namespace == gateway.Namespace
+	}
+}
+
+// newIngress creates the Ingress serving the paths of the route on the listener for each hostname.
+func newIngress(route *gwv1.HTTPRoute, gateway *gwv1.Gateway, listener *listenerResult, hostnames []string, paths []v1beta1.HTTPIngressPath) *v1beta1.Ingress {
+	listenerKey := fmt.Sprintf("%s/%s/%s", gateway.Namespace, gateway.Name, listener.listener.Name)
+	ingress := &v1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         route.Namespace,
+			Name:              fmt.Sprintf("%s-%s-%s-%s", route.Name, gateway.Namespace, gateway.Name, listener.listener.Name),
+			UID:               types.UID(fmt.Sprintf("%s/%s", route.UID, listenerKey)),
+			CreationTimestamp: route.CreationTimestamp,
+			Annotations: map[string]string{
+				annotations.IngressClassKey:       annotations.ApplicationGatewayIngressClass,
+				annotations.GatewayAPIListenerKey: listenerKey,
+			},
+		},
+	}
+
+	for _, hostname := range hostnames {
+		rulePaths := make([]v1beta1.HTTPIngressPath, len(paths))
+		copy(rulePaths, paths)
+		ingress.Spec.Rules = append(ingress.Spec.Rules, v1beta1.IngressRule{
+			Host: hostname,
+			IngressRuleValue: v1beta1.IngressRuleValue{
+				HTTP: &v1beta1.HTTPIngressRuleValue{
+					Paths: rulePaths,
+				},
+			},
+		})
+	}
+
+	// The certificate lives in the namespace of the Gateway, not of the route; The secret name carries the namespace.
+	if listener.secretKey != "" {
+		tls := v1beta1.IngressTLS{SecretName: listener.secretKey}
+		for _, hostname := range hostnames {
+			if hostname != "" {
+				tls.Hosts = append(tls.Hosts, hostname)
+			}
+		}
+		ingress.Spec.TLS = []v1beta1.IngressTLS{tls}
+	}
+
+	return ingress
+}
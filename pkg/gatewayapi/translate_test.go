@@ -0,0 +1,332 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package gatewayapi
+
+import (
+	"errors"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	gwv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+)
+
+func newTestResources() Resources {
+	from := gwv1.NamespacesFromAll
+	pathPrefix := gwv1.PathMatchPathPrefix
+	exact := gwv1.PathMatchExact
+	return Resources{
+		GatewayClasses: []*gwv1.GatewayClass{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "azure-application-gateway"},
+				Spec:       gwv1.GatewayClassSpec{ControllerName: ControllerName},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+				Spec:       gwv1.GatewayClassSpec{ControllerName: "example.com/other"},
+			},
+		},
+		Gateways: []*gwv1.Gateway{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "gateway", Generation: 2},
+				Spec: gwv1.GatewaySpec{
+					GatewayClassName: "azure-application-gateway",
+					Listeners: []gwv1.Listener{
+						{
+							Name:          "http",
+							Port:          80,
+							Protocol:      gwv1.HTTPProtocolType,
+							AllowedRoutes: &gwv1.AllowedRoutes{Namespaces: &gwv1.RouteNamespaces{From: &from}},
+						},
+						{
+							Name:     "https",
+							Hostname: to.StringPtr("*.contoso.com"),
+							Port:     443,
+							Protocol: gwv1.HTTPSProtocolType,
+							TLS: &gwv1.GatewayTLSConfig{
+								CertificateRefs: []gwv1.SecretObjectReference{{Name: "contoso-cert"}},
+							},
+							AllowedRoutes: &gwv1.AllowedRoutes{Namespaces: &gwv1.RouteNamespaces{From: &from}},
+						},
+						{
+							Name:     "alternate",
+							Port:     8080,
+							Protocol: gwv1.HTTPProtocolType,
+						},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "other-gateway"},
+				Spec:       gwv1.GatewaySpec{GatewayClassName: "other"},
+			},
+		},
+		HTTPRoutes: []*gwv1.HTTPRoute{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "store", UID: "store-uid", Generation: 3},
+				Spec: gwv1.HTTPRouteSpec{
+					ParentRefs: []gwv1.ParentReference{
+						{Namespace: to.StringPtr("infra"), Name: "gateway"},
+						{Namespace: to.StringPtr("infra"), Name: "other-gateway"},
+					},
+					Hostnames: []string{"www.contoso.com"},
+					Rules: []gwv1.HTTPRouteRule{
+						{
+							Matches: []gwv1.HTTPRouteMatch{
+								{Path: &gwv1.HTTPPathMatch{Type: &pathPrefix, Value: to.StringPtr("/api/")}},
+								{Path: &gwv1.HTTPPathMatch{Type: &exact, Value: to.StringPtr("/login")}},
+								{Path: &gwv1.HTTPPathMatch{Type: &exact, Value: to.StringPtr("/admin")}, Method: to.StringPtr("POST")},
+							},
+							BackendRefs: []gwv1.HTTPBackendRef{
+								{BackendRef: gwv1.BackendRef{BackendObjectReference: gwv1.BackendObjectReference{Name: "api", Port: to.Int32Ptr(8080)}}},
+							},
+						},
+					},
+				},
+			},
+		},
+		Services: []*v1.Service{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "api"}},
+		},
+		HasCertificate: func(secretKey string) bool { return secretKey == "infra/contoso-cert" },
+	}
+}
+
+func findCondition(conditions []gwv1.Condition, conditionType string) gwv1.Condition {
+	for _, condition := range conditions {
+		if condition.Type == conditionType {
+			return condition
+		}
+	}
+	return gwv1.Condition{}
+}
+
+var _ = Describe("test translating the Gateway API into Ingresses", func() {
+	now := metav1.Now()
+
+	Context("test Translate with a route attached to HTTP and HTTPS listeners", func() {
+		translation := Translate(newTestResources())
+
+		It("creates an Ingress for each listener the route attaches to", func() {
+			Expect(len(translation.Ingresses)).To(Equal(2))
+
+			httpIngress := translation.Ingresses[0]
+			Expect(httpIngress.Namespace).To(Equal("app"))
+			Expect(httpIngress.Name).To(Equal("store-infra-gateway-http"))
+			Expect(httpIngress.Annotations).To(HaveKeyWithValue(annotations.GatewayAPIListenerKey, "infra/gateway/http"))
+			Expect(httpIngress.Annotations).To(HaveKeyWithValue(annotations.IngressClassKey, annotations.ApplicationGatewayIngressClass))
+			Expect(httpIngress.Spec.TLS).To(BeNil())
+			Expect(len(httpIngress.Spec.Rules)).To(Equal(1))
+			Expect(httpIngress.Spec.Rules[0].Host).To(Equal("www.contoso.com"))
+
+			backend := v1beta1.IngressBackend{ServiceName: "api", ServicePort: intstr.FromInt(8080)}
+			Expect(httpIngress.Spec.Rules[0].HTTP.Paths).To(Equal([]v1beta1.HTTPIngressPath{
+				{Path: "/api", Backend: backend},
+				{Path: "/api/*", Backend: backend},
+				{Path: "/login", Backend: backend},
+			}))
+		})
+
+		It("refers to the certificate in the namespace of the Gateway", func() {
+			httpsIngress := translation.Ingresses[1]
+			Expect(httpsIngress.Name).To(Equal("store-infra-gateway-https"))
+			Expect(httpsIngress.Spec.TLS).To(Equal([]v1beta1.IngressTLS{{Hosts: []string{"www.contoso.com"}, SecretName: "infra/contoso-cert"}}))
+		})
+
+		It("reports the class AGIC implements only", func() {
+			statuses := translation.ClassStatuses(now)
+			Expect(len(statuses)).To(Equal(1))
+			Expect(statuses[0].Class.Name).To(Equal("azure-application-gateway"))
+			Expect(isTrue(findCondition(statuses[0].Status.Conditions, ConditionAccepted))).To(BeTrue())
+		})
+
+		It("reports the listeners and the attached routes", func() {
+			statuses := translation.GatewayStatuses(nil, nil, now)
+			Expect(len(statuses)).To(Equal(1))
+			status := statuses[0].Status
+			Expect(isTrue(findCondition(status.Conditions, ConditionAccepted))).To(BeTrue())
+			Expect(isTrue(findCondition(status.Conditions, ConditionProgrammed))).To(BeTrue())
+			Expect(findCondition(status.Conditions, ConditionAccepted).ObservedGeneration).To(Equal(int64(2)))
+
+			Expect(len(status.Listeners)).To(Equal(3))
+			Expect(status.Listeners[0].AttachedRoutes).To(Equal(int32(1)))
+			Expect(status.Listeners[1].AttachedRoutes).To(Equal(int32(1)))
+			Expect(status.Listeners[2].AttachedRoutes).To(Equal(int32(0)))
+			Expect(findCondition(status.Listeners[2].Conditions, ConditionAccepted).Reason).To(Equal(ReasonPortUnavailable))
+			Expect(findCondition(status.Listeners[2].Conditions, ConditionProgrammed).Status).To(Equal(gwv1.ConditionFalse))
+		})
+
+		It("reports the Gateway as not programmed when applying the config fails", func() {
+			status := translation.GatewayStatuses(errors.New("boom"), nil, now)[0].Status
+			programmed := findCondition(status.Conditions, ConditionProgrammed)
+			Expect(programmed.Status).To(Equal(gwv1.ConditionFalse))
+			Expect(programmed.Message).To(ContainSubstring("boom"))
+		})
+
+		It("reports the route for the Gateways of AGIC only, along with the unsupported matches", func() {
+			statuses := translation.RouteStatuses(now)
+			Expect(len(statuses)).To(Equal(1))
+			parents := statuses[0].Status.Parents
+			Expect(len(parents)).To(Equal(1))
+			Expect(parents[0].ParentRef.Name).To(Equal("gateway"))
+			Expect(parents[0].ControllerName).To(Equal(ControllerName))
+			accepted := findCondition(parents[0].Conditions, ConditionAccepted)
+			Expect(isTrue(accepted)).To(BeTrue())
+			Expect(accepted.Message).To(ContainSubstring("rule 0 match 2: header, query parameter and method matches"))
+			Expect(isTrue(findCondition(parents[0].Conditions, ConditionResolvedRefs))).To(BeTrue())
+		})
+	})
+
+	Context("test Translate with references AGIC cannot resolve", func() {
+		It("does not serve an HTTPS listener without its certificate", func() {
+			resources := newTestResources()
+			resources.HasCertificate = func(string) bool { return false }
+			translation := Translate(resources)
+
+			Expect(len(translation.Ingresses)).To(Equal(1))
+			listener := translation.GatewayStatuses(nil, nil, now)[0].Status.Listeners[1]
+			Expect(findCondition(listener.Conditions, ConditionResolvedRefs).Reason).To(Equal(ReasonInvalidCertificateRef))
+			Expect(listener.AttachedRoutes).To(Equal(int32(0)))
+		})
+
+		It("reports a missing Service", func() {
+			resources := newTestResources()
+			resources.Services = nil
+			translation := Translate(resources)
+
+			Expect(translation.Ingresses).To(BeNil())
+			parents := translation.RouteStatuses(now)[0].Status.Parents
+			Expect(findCondition(parents[0].Conditions, ConditionResolvedRefs).Reason).To(Equal(ReasonBackendNotFound))
+		})
+
+		It("does not attach routes from other namespaces unless the listener allows them", func() {
+			resources := newTestResources()
+			resources.Gateways[0].Spec.Listeners[0].AllowedRoutes = nil
+			resources.HTTPRoutes[0].Spec.ParentRefs[0].SectionName = to.StringPtr("http")
+			translation := Translate(resources)
+
+			Expect(translation.Ingresses).To(BeNil())
+			parents := translation.RouteStatuses(now)[0].Status.Parents
+			Expect(findCondition(parents[0].Conditions, ConditionAccepted).Reason).To(Equal(ReasonNotAllowedByListeners))
+		})
+
+		It("does not serve the wildcard hostname of a listener to a route without hostnames", func() {
+			resources := newTestResources()
+			resources.HTTPRoutes[0].Spec.Hostnames = nil
+			resources.HTTPRoutes[0].Spec.ParentRefs[0].SectionName = to.StringPtr("https")
+			translation := Translate(resources)
+
+			Expect(translation.Ingresses).To(BeNil())
+			parents := translation.RouteStatuses(now)[0].Status.Parents
+			accepted := findCondition(parents[0].Conditions, ConditionAccepted)
+			Expect(accepted.Status).To(Equal(gwv1.ConditionFalse))
+			Expect(accepted.Reason).To(Equal(ReasonUnsupportedValue))
+			Expect(accepted.Message).To(ContainSubstring("*.contoso.com"))
+		})
+
+		It("does not serve a wildcard hostname of a route on a listener without hostname", func() {
+			resources := newTestResources()
+			resources.HTTPRoutes[0].Spec.Hostnames = []string{"*.contoso.com"}
+			resources.HTTPRoutes[0].Spec.ParentRefs[0].SectionName = to.StringPtr("http")
+			translation := Translate(resources)
+
+			Expect(translation.Ingresses).To(BeNil())
+			parents := translation.RouteStatuses(now)[0].Status.Parents
+			accepted := findCondition(parents[0].Conditions, ConditionAccepted)
+			Expect(accepted.Status).To(Equal(gwv1.ConditionFalse))
+			Expect(accepted.Reason).To(Equal(ReasonUnsupportedValue))
+		})
+
+		It("reports a parent reference to a listener which does not exist", func() {
+			resources := newTestResources()
+			resources.HTTPRoutes[0].Spec.ParentRefs[0].SectionName = to.StringPtr("missing")
+			translation := Translate(resources)
+
+			parents := translation.RouteStatuses(now)[0].Status.Parents
+			Expect(findCondition(parents[0].Conditions, ConditionAccepted).Reason).To(Equal(ReasonNoMatchingParent))
+		})
+	})
+
+	Context("test pruning the Ingresses translated from a route", func() {
+		It("maps the Ingresses back to the route", func() {
+			translation := Translate(newTestResources())
+			route := translation.RouteOf(translation.Ingresses[0])
+			Expect(route).ToNot(BeNil())
+			Expect(route.Name).To(Equal("store"))
+
+			ingress := &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "store", UID: "ingress-uid"}}
+			Expect(translation.RouteOf(ingress)).To(BeNil())
+			Expect(translation.Prune(ingress, "HostPathConflict", "ignoring path /")).To(BeNil())
+
+			var disabled *Translation
+			Expect(disabled.RouteOf(translation.Ingresses[0])).To(BeNil())
+		})
+
+		It("reports the route as no longer accepted", func() {
+			translation := Translate(newTestResources())
+			pruned := translation.Ingresses[0].DeepCopy()
+			route := translation.Prune(pruned, "HostPathConflict", "ignoring path /api/ of host \"www.contoso.com\"")
+			Expect(route).ToNot(BeNil())
+			Expect(route.Name).To(Equal("store"))
+
+			parents := translation.RouteStatuses(now)[0].Status.Parents
+			accepted := findCondition(parents[0].Conditions, ConditionAccepted)
+			Expect(accepted.Status).To(Equal(gwv1.ConditionFalse))
+			Expect(accepted.Reason).To(Equal("HostPathConflict"))
+			Expect(accepted.Message).To(ContainSubstring("ignoring path /api/"))
+			Expect(accepted.ObservedGeneration).To(Equal(int64(3)))
+			Expect(isTrue(findCondition(parents[0].Conditions, ConditionResolvedRefs))).To(BeTrue())
+		})
+	})
+
+	Context("test the status of routes shared with other controllers", func() {
+		It("keeps the parents other controllers report", func() {
+			resources := newTestResources()
+			otherParent := gwv1.RouteParentStatus{
+				ParentRef:      gwv1.ParentReference{Namespace: to.StringPtr("infra"), Name: "other-gateway"},
+				ControllerName: "example.com/other",
+			}
+			resources.HTTPRoutes[0].Status.Parents = []gwv1.RouteParentStatus{otherParent}
+			parents := Translate(resources).RouteStatuses(now)[0].Status.Parents
+
+			Expect(len(parents)).To(Equal(2))
+			Expect(parents[0]).To(Equal(otherParent))
+		})
+	})
+
+	Context("test translatePathMatch", func() {
+		It("serves all paths for the prefix /", func() {
+			paths, supported := translatePathMatch(nil)
+			Expect(supported).To(BeTrue())
+			Expect(paths).To(Equal([]string{"/*"}))
+		})
+
+		It("does not support regular expressions", func() {
+			regex := gwv1.PathMatchRegularExpression
+			_, supported := translatePathMatch(&gwv1.HTTPPathMatch{Type: &regex, Value: to.StringPtr("/a.*")})
+			Expect(supported).To(BeFalse())
+		})
+	})
+
+	Context("test mergeConditions", func() {
+		It("keeps the transition time of conditions whose status did not change", func() {
+			earlier := metav1.NewTime(now.Add(-1000000000))
+			existing := []gwv1.Condition{{Type: ConditionAccepted, Status: gwv1.ConditionTrue, LastTransitionTime: earlier}}
+			merged := mergeConditions(existing, []gwv1.Condition{
+				newCondition(ConditionAccepted, gwv1.ConditionTrue, ReasonAccepted, "", 1),
+				newCondition(ConditionProgrammed, gwv1.ConditionTrue, ReasonProgrammed, "", 1),
+			}, now)
+			Expect(merged[0].LastTransitionTime).To(Equal(earlier))
+			Expect(merged[1].LastTransitionTime).To(Equal(now))
+		})
+	})
+})
@@ -18,15 +18,20 @@ import (
 	"github.com/knative/pkg/apis/istio/v1alpha3"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	managedv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	prohibitedv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/informers/externalversions"
+	gateway_versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned"
+	gateway_externalversions "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/informers/externalversions"
 	istio_versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned"
 	istio_externalversions "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/informers/externalversions"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
@@ -35,124 +40,209 @@ import (
 )
 
 // NewContext creates a context based on a Kubernetes client instance.
-func NewContext(kubeClient kubernetes.Interface, crdClient versioned.Interface, istioCrdClient istio_versioned.Interface, namespaces []string, resyncPeriod time.Duration) *Context {
+// AGIC observes the given namespaces along with the ones matching the namespace selector (if not nil); All namespaces when neither is given.
+func NewContext(kubeClient kubernetes.Interface, crdClient versioned.Interface, istioCrdClient istio_versioned.Interface, gatewayCrdClient gateway_versioned.Interface, namespaces []string, namespaceSelector labels.Selector, resyncPeriod time.Duration) *Context {
 	updateChannel := channels.NewRingChannel(1024)
 
-	var options []informers.SharedInformerOption
-	var crdOptions []externalversions.SharedInformerOption
-	for _, namespace := range namespaces {
-		options = append(options, informers.WithNamespace(namespace))
-		crdOptions = append(crdOptions, externalversions.WithNamespace(namespace))
-	}
-	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, resyncPeriod, options...)
-	crdInformerFactory := externalversions.NewSharedInformerFactoryWithOptions(crdClient, resyncPeriod, crdOptions...)
-	istioCrdInformerFactory := istio_externalversions.NewSharedInformerFactoryWithOptions(istioCrdClient, resyncPeriod)
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, resyncPeriod)
 
-	informerCollection := InformerCollection{
-		Endpoints: informerFactory.Core().V1().Endpoints().Informer(),
-		Ingress:   informerFactory.Extensions().V1beta1().Ingresses().Informer(),
-		Pods:      informerFactory.Core().V1().Pods().Informer(),
-		Secret:    informerFactory.Core().V1().Secrets().Informer(),
-		Service:   informerFactory.Core().V1().Services().Informer(),
+	// GatewayClasses are cluster scoped; AGIC observes them regardless of the observed namespaces.
+	gatewayCrdInformerFactory := gateway_externalversions.NewSharedInformerFactoryWithOptions(gatewayCrdClient, resyncPeriod)
 
-		AzureIngressProhibitedTarget: crdInformerFactory.Azureingressprohibitedtargets().V1().AzureIngressProhibitedTargets().Informer(),
+	// Istio Gateways usually live in a namespace of their own, e.g. istio-system; AGIC observes them in all namespaces
+	// and scopes only the VirtualServices and DestinationRules to the observed namespaces.
+	istioCrdInformerFactory := istio_externalversions.NewSharedInformerFactoryWithOptions(istioCrdClient, resyncPeriod)
 
-		IstioGateway:        istioCrdInformerFactory.Networking().V1alpha3().Gateways().Informer(),
-		IstioVirtualService: istioCrdInformerFactory.Networking().V1alpha3().VirtualServices().Informer(),
+	// Nodes are cluster scoped; Backends in the nodeport mode are reached through all of them.
+	informerCollection := InformerCollection{
+		Namespace:    informerFactory.Core().V1().Namespaces().Informer(),
+		Node:         informerFactory.Core().V1().Nodes().Informer(),
+		GatewayClass: gatewayCrdInformerFactory.Gateway().V1beta1().GatewayClasses().Informer(),
+		IstioGateway: istioCrdInformerFactory.Networking().V1alpha3().Gateways().Informer(),
 	}
 
 	cacheCollection := CacheCollection{
-		Endpoints:                    informerCollection.Endpoints.GetStore(),
-		Ingress:                      informerCollection.Ingress.GetStore(),
-		Pods:                         informerCollection.Pods.GetStore(),
-		Secret:                       informerCollection.Secret.GetStore(),
-		Service:                      informerCollection.Service.GetStore(),
-		AzureIngressProhibitedTarget: informerCollection.AzureIngressProhibitedTarget.GetStore(),
-		IstioGateway:                 informerCollection.IstioGateway.GetStore(),
-		IstioVirtualService:          informerCollection.IstioVirtualService.GetStore(),
+		Namespaces:   informerCollection.Namespace.GetStore(),
+		Nodes:        informerCollection.Node.GetStore(),
+		GatewayClass: informerCollection.GatewayClass.GetStore(),
+		IstioGateway: informerCollection.IstioGateway.GetStore(),
 	}
 
 	context := &Context{
-		kubeClient:     kubeClient,
-		crdClient:      crdClient,
-		istioCrdClient: istioCrdClient,
+		kubeClient:       kubeClient,
+		crdClient:        crdClient,
+		istioCrdClient:   istioCrdClient,
+		gatewayCrdClient: gatewayCrdClient,
 
 		informers:              &informerCollection,
 		ingressSecretsMap:      utils.NewThreadsafeMultimap(),
+		istioGatewaySecretsMap: utils.NewThreadsafeMultimap(),
+		gatewaySecretsMap:      utils.NewThreadsafeMultimap(),
 		Caches:                 &cacheCollection,
 		CertificateSecretStore: NewSecretStore(),
 		UpdateChannel:          updateChannel,
 	}
 
 	h := handlers{context}
-
 	resourceHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc:    h.addFunc,
 		UpdateFunc: h.updateFunc,
 		DeleteFunc: h.deleteFunc,
 	}
 
-	ingressResourceHandler := cache.ResourceEventHandlerFuncs{
-		AddFunc:    h.ingressAddFunc,
-		UpdateFunc: h.ingressUpdateFunc,
-		DeleteFunc: h.ingressDeleteFunc,
-	}
-
-	secretResourceHandler := cache.ResourceEventHandlerFuncs{
-		AddFunc:    h.secretAddFunc,
-		UpdateFunc: h.secretUpdateFunc,
-		DeleteFunc: h.secretDeleteFunc,
-	}
+	informerCollection.Node.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.addFunc,
+		UpdateFunc: h.nodeUpdateFunc,
+		DeleteFunc: h.deleteFunc,
+	})
 
-	// Register event handlers.
-	informerCollection.Endpoints.AddEventHandler(resourceHandler)
-	informerCollection.Ingress.AddEventHandler(ingressResourceHandler)
-	informerCollection.Pods.AddEventHandler(resourceHandler)
-	informerCollection.Secret.AddEventHandler(secretResourceHandler)
-	informerCollection.Service.AddEventHandler(resourceHandler)
-	informerCollection.AzureIngressProhibitedTarget.AddEventHandler(resourceHandler)
+	informerCollection.GatewayClass.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.addFunc,
+		UpdateFunc: h.gatewayAPIUpdateFunc,
+		DeleteFunc: h.deleteFunc,
+	})
+
+	informerCollection.IstioGateway.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.istioGatewayAddFunc,
+		UpdateFunc: h.istioGatewayUpdateFunc,
+		DeleteFunc: h.istioGatewayDeleteFunc,
+	})
+
+	if len(namespaces) == 0 && namespaceSelector == nil {
+		// Observe all namespaces with a single set of informers.
+		crdInformerFactory := externalversions.NewSharedInformerFactoryWithOptions(crdClient, resyncPeriod)
+		namespaced := newNamespacedInformers(informerFactory, crdInformerFactory, istioCrdInformerFactory, gatewayCrdInformerFactory)
+		namespaced.registerHandlers(h)
+		informerCollection.Endpoints = namespaced.Endpoints
+		informerCollection.Ingress = namespaced.Ingress
+		informerCollection.Pods = namespaced.Pods
+		informerCollection.Secret = namespaced.Secret
+		informerCollection.Service = namespaced.Service
+		informerCollection.AzureIngressManagedTarget = namespaced.AzureIngressManagedTarget
+		informerCollection.AzureIngressProhibitedTarget = namespaced.AzureIngressProhibitedTarget
+		informerCollection.IstioVirtualService = namespaced.IstioVirtualService
+		informerCollection.IstioDestinationRule = namespaced.IstioDestinationRule
+		informerCollection.Gateway = namespaced.Gateway
+		informerCollection.HTTPRoute = namespaced.HTTPRoute
+		cacheCollection.Endpoints = namespaced.Endpoints.GetStore()
+		cacheCollection.Ingress = namespaced.Ingress.GetStore()
+		cacheCollection.Pods = namespaced.Pods.GetStore()
+		cacheCollection.Secret = namespaced.Secret.GetStore()
+		cacheCollection.Service = namespaced.Service.GetStore()
+		cacheCollection.AzureIngressManagedTarget = namespaced.AzureIngressManagedTarget.GetStore()
+		cacheCollection.AzureIngressProhibitedTarget = namespaced.AzureIngressProhibitedTarget.GetStore()
+		cacheCollection.IstioVirtualService = namespaced.IstioVirtualService.GetStore()
+		cacheCollection.IstioDestinationRule = namespaced.IstioDestinationRule.GetStore()
+		cacheCollection.Gateway = namespaced.Gateway.GetStore()
+		cacheCollection.HTTPRoute = namespaced.HTTPRoute.GetStore()
+		informerCollection.Namespace.AddEventHandler(resourceHandler)
+		return context
+	}
+
+	// Observe the selected namespaces with a set of informers per namespace, started and stopped as namespaces come and go.
+	context.namespaceWatcher = newNamespaceWatcher(kubeClient, crdClient, istioCrdClient, gatewayCrdClient, namespaces, namespaceSelector, resyncPeriod, h)
+	cacheCollection.Endpoints = context.namespaceWatcher.stores.Endpoints
+	cacheCollection.Ingress = context.namespaceWatcher.stores.Ingress
+	cacheCollection.Pods = context.namespaceWatcher.stores.Pods
+	cacheCollection.Secret = context.namespaceWatcher.stores.Secret
+	cacheCollection.Service = context.namespaceWatcher.stores.Service
+	cacheCollection.AzureIngressManagedTarget = context.namespaceWatcher.stores.AzureIngressManagedTarget
+	cacheCollection.AzureIngressProhibitedTarget = context.namespaceWatcher.stores.AzureIngressProhibitedTarget
+	cacheCollection.IstioVirtualService = context.namespaceWatcher.stores.IstioVirtualService
+	cacheCollection.IstioDestinationRule = context.namespaceWatcher.stores.IstioDestinationRule
+	cacheCollection.Gateway = context.namespaceWatcher.stores.Gateway
+	cacheCollection.HTTPRoute = context.namespaceWatcher.stores.HTTPRoute
+	informerCollection.Namespace.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			context.namespaceWatcher.sync(obj.(*v1.Namespace))
+			h.addFunc(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			context.namespaceWatcher.sync(newObj.(*v1.Namespace))
+			h.updateFunc(oldObj, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if namespace, ok := obj.(*v1.Namespace); ok {
+				context.namespaceWatcher.stop(namespace.Name)
+			} else if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				if namespace, ok := tombstone.Obj.(*v1.Namespace); ok {
+					context.namespaceWatcher.stop(namespace.Name)
+				}
+			}
+			h.deleteFunc(obj)
+		},
+	})
 
 	return context
 }
 
 // Run executes informer collection.
-func (c *Context) Run(stopChannel chan struct{}, omitCRDs bool, envVariables environment.EnvVariables) error {
+func (c *Context) Run(stopChannel chan struct{}, envVariables environment.EnvVariables) error {
 	glog.V(1).Infoln("k8s context run started")
 	var hasSynced []cache.InformerSynced
 
 	if c.informers == nil {
 		return errors.New("informers are not initialized")
 	}
-	crds := map[cache.SharedInformer]interface{}{
-		c.informers.AzureIngressProhibitedTarget: nil,
-		c.informers.IstioGateway:                 nil,
-		c.informers.IstioVirtualService:          nil,
-	}
-
-	sharedInformers := []cache.SharedInformer{
-		c.informers.Endpoints,
-		c.informers.Pods,
-		c.informers.Service,
-		c.informers.Secret,
-		c.informers.Ingress,
-	}
 
 	// For AGIC to watch for these CRDs the EnableBrownfieldDeploymentVarName env variable must be set to true
-	if envVariables.EnableBrownfieldDeployment == "true" {
-		sharedInformers = append(sharedInformers, c.informers.AzureIngressProhibitedTarget)
-	}
-
-	if envVariables.EnableIstioIntegration == "true" {
-		sharedInformers = append(sharedInformers, c.informers.IstioGateway, c.informers.IstioVirtualService)
+	withCRDs := envVariables.EnableBrownfieldDeployment == "true"
+	withIstio := envVariables.EnableIstioIntegration == "true"
+	withGatewayAPI := envVariables.EnableGatewayAPI == "true"
+
+	var sharedInformers []cache.SharedInformer
+
+	// Namespaces are cluster scoped too; Without them AGIC observes the listed namespaces only, grants no hostnames,
+	// and Gateway listeners selecting the namespaces of their routes by label select none.
+	withNamespaces := c.mayListNamespaces()
+	if withNamespaces {
+		sharedInformers = append(sharedInformers, c.informers.Namespace)
+	} else if c.namespaceWatcher != nil && c.namespaceWatcher.selector != nil {
+		return fmt.Errorf("%s is set, but AGIC is not allowed to list namespaces", environment.WatchNamespaceSelectorVarName)
+	} else {
+		glog.Warning("AGIC is not allowed to list namespaces; Hostnames granted to namespaces and namespace selectors of Gateway listeners are ignored")
+	}
+
+	// Nodes are cluster scoped, which a Role can not grant; Only the nodeport backend mode needs them.
+	if c.mayListNodes() {
+		sharedInformers = append(sharedInformers, c.informers.Node)
+	} else if envVariables.BackendMode == annotations.BackendModeNodePort {
+		return fmt.Errorf("%s is %s, but AGIC is not allowed to list nodes", environment.BackendModeVarName, annotations.BackendModeNodePort)
+	} else {
+		glog.Warning("AGIC is not allowed to list nodes; Ingresses in the nodeport backend mode will not reach their Services")
+	}
+	if withGatewayAPI {
+		sharedInformers = append(sharedInformers, c.informers.GatewayClass)
+	}
+	if withIstio {
+		sharedInformers = append(sharedInformers, c.informers.IstioGateway)
+	}
+
+	if c.namespaceWatcher == nil {
+		sharedInformers = append(sharedInformers,
+			c.informers.Endpoints,
+			c.informers.Pods,
+			c.informers.Service,
+			c.informers.Secret,
+			c.informers.Ingress,
+		)
+		if withCRDs {
+			sharedInformers = append(sharedInformers, c.informers.AzureIngressProhibitedTarget, c.informers.AzureIngressManagedTarget)
+		}
+		if withIstio {
+			sharedInformers = append(sharedInformers, c.informers.IstioVirtualService, c.informers.IstioDestinationRule)
+		}
+		if withGatewayAPI {
+			sharedInformers = append(sharedInformers, c.informers.Gateway, c.informers.HTTPRoute)
+		}
+	} else {
+		c.namespaceWatcher.run(stopChannel, withCRDs, withIstio, withGatewayAPI)
 	}
 
+	// The CRD informers must sync too: reconciling before AGIC knows all prohibited targets
+	// could delete brownfield config AGIC must protect.
 	for _, informer := range sharedInformers {
 		go informer.Run(stopChannel)
-		// NOTE: Delyan could not figure out how to make informer.HasSynced == true for the CRDs in unit tests
-		// so until we do that - we omit WaitForCacheSync for CRDs in unit testing
-		if _, isCRD := crds[informer]; isCRD {
-			continue
-		}
 		hasSynced = append(hasSynced, informer.HasSynced)
 	}
 
@@ -161,11 +251,37 @@ func (c *Context) Run(stopChannel chan struct{}, omitCRDs bool, envVariables env
 		return errors.New("failed initial sync of resources required for ingress")
 	}
 
+	if c.namespaceWatcher != nil {
+		// The namespace event handlers may still be catching up with the namespaces listed by the initial sync.
+		for _, namespace := range c.ListNamespaces() {
+			c.namespaceWatcher.sync(namespace)
+		}
+		if !withNamespaces {
+			c.namespaceWatcher.startListed()
+		}
+		glog.V(1).Infof("Waiting for initial cache sync of namespaces %s", strings.Join(c.namespaceWatcher.watched(), ","))
+		if !cache.WaitForCacheSync(stopChannel, c.namespaceWatcher.hasSynced()...) {
+			return errors.New("failed initial sync of resources required for ingress")
+		}
+	}
+
 	glog.V(1).Infoln("initial cache sync done")
 	glog.V(1).Infoln("k8s context run finished")
 	return nil
 }
 
+// mayListNodes figures out whether AGIC is allowed to list the nodes of the cluster; Informers of forbidden resources never sync.
+func (c *Context) mayListNodes() bool {
+	_, err := c.kubeClient.CoreV1().Nodes().List(metav1.ListOptions{Limit: 1})
+	return !apierrors.IsForbidden(err)
+}
+
+// mayListNamespaces figures out whether AGIC is allowed to list the namespaces of the cluster.
+func (c *Context) mayListNamespaces() bool {
+	_, err := c.kubeClient.CoreV1().Namespaces().List(metav1.ListOptions{Limit: 1})
+	return !apierrors.IsForbidden(err)
+}
+
 // ListServices returns a list of all the Services from cache.
 func (c *Context) ListServices() []*v1.Service {
 	var serviceList []*v1.Service
@@ -243,7 +359,7 @@ func (c *Context) ListHTTPIngresses() []*v1beta1.Ingress {
 	var ingressList []*v1beta1.Ingress
 	for _, ingressInterface := range c.Caches.Ingress.List() {
 		ingress := ingressInterface.(*v1beta1.Ingress)
-		if hasHTTPRule(ingress) && IsIngressApplicationGateway(ingress) {
+		if hasHTTPRule(ingress) && IsIngressApplicationGateway(ingress) && !isGatewayAPIIngress(ingress) {
 			ingressList = append(ingressList, ingress)
 		}
 	}
@@ -271,6 +387,54 @@ func (c *Context) ListAzureProhibitedTargets() []*prohibitedv1.AzureIngressProhi
 	return targets
 }
 
+// ListAzureManagedTargets returns a list of App Gwy configs, for which AGIC is allowed to modify config.
+// When this list is not empty AGIC is not allowed to modify any other config.
+func (c *Context) ListAzureManagedTargets() []*managedv1.AzureIngressManagedTarget {
+	var targets []*managedv1.AzureIngressManagedTarget
+	for _, obj := range c.Caches.AzureIngressManagedTarget.List() {
+		targets = append(targets, obj.(*managedv1.AzureIngressManagedTarget))
+	}
+
+	var managedTargets []string
+	for _, target := range targets {
+		managedTargets = append(managedTargets, fmt.Sprintf("%s/%s", target.Namespace, target.Name))
+	}
+
+	glog.V(5).Infof("AzureIngressManagedTargets: %+v", strings.Join(managedTargets, ","))
+
+	return targets
+}
+
+// ListNamespaces returns a list of all the Namespaces from cache.
+func (c *Context) ListNamespaces() []*v1.Namespace {
+	var namespaces []*v1.Namespace
+	for _, obj := range c.Caches.Namespaces.List() {
+		namespaces = append(namespaces, obj.(*v1.Namespace))
+	}
+	return namespaces
+}
+
+// ListObservedNamespaces returns the names of the namespaces AGIC observes the resources of.
+func (c *Context) ListObservedNamespaces() []string {
+	if c.namespaceWatcher != nil {
+		return c.namespaceWatcher.watched()
+	}
+	var namespaces []string
+	for _, namespace := range c.ListNamespaces() {
+		namespaces = append(namespaces, namespace.Name)
+	}
+	return namespaces
+}
+
+// ListNodes returns a list of all the Nodes from cache.
+func (c *Context) ListNodes() []*v1.Node {
+	var nodes []*v1.Node
+	for _, obj := range c.Caches.Nodes.List() {
+		nodes = append(nodes, obj.(*v1.Node))
+	}
+	return nodes
+}
+
 // GetService returns the service identified by the key.
 func (c *Context) GetService(serviceKey string) *v1.Service {
 	serviceInterface, exist, err := c.Caches.Service.GetByKey(serviceKey)
@@ -307,19 +471,17 @@ func (c *Context) GetSecret(secretKey string) *v1.Secret {
 	return secret
 }
 
+// isSecretReferenced figures out whether an Ingress, an Istio Gateway or a Gateway API Gateway references the secret with the given key.
+func (c *Context) isSecretReferenced(secretKey string) bool {
+	return c.ingressSecretsMap.ContainsValue(secretKey) || c.istioGatewaySecretsMap.ContainsValue(secretKey) || c.gatewaySecretsMap.ContainsValue(secretKey)
+}
+
 // GetVirtualServicesForGateway returns the VirtualServices for the provided gateway
 func (c *Context) GetVirtualServicesForGateway(gateway v1alpha3.Gateway) []*v1alpha3.VirtualService {
 	virtualServices := make([]*v1alpha3.VirtualService, 0)
 	allVirtualServices := c.ListIstioVirtualServices()
-	gatewayName := gateway.Name
 	for _, service := range allVirtualServices {
-		hasGateway := false
-		for _, serviceGateway := range service.Spec.Gateways {
-			if gatewayName == serviceGateway {
-				hasGateway = true
-			}
-		}
-		if hasGateway {
+		if IsIstioGatewayBound(service, &gateway) {
 			virtualServices = append(virtualServices, service)
 		}
 	}
@@ -385,12 +547,52 @@ func (c *Context) UpdateIngressStatus(ingressToUpdate v1beta1.Ingress, address I
 	return nil
 }
 
+// DeleteConfigMap deletes the ConfigMap with the given name; False when there is no such ConfigMap.
+func (c *Context) DeleteConfigMap(namespace string, name string) (bool, error) {
+	err := c.kubeClient.CoreV1().ConfigMaps(namespace).Delete(name, &metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("Unable to delete ConfigMap %s/%s: %s", namespace, name, err)
+	}
+	return true, nil
+}
+
+// UpdateAzureProhibitedTargetStatus replaces the status of the given prohibited target.
+func (c *Context) UpdateAzureProhibitedTargetStatus(targetToUpdate prohibitedv1.AzureIngressProhibitedTarget, status prohibitedv1.AzureIngressProhibitedTargetStatus) error {
+	targetClient := c.crdClient.AzureingressprohibitedtargetsV1().AzureIngressProhibitedTargets(targetToUpdate.Namespace)
+	target, err := targetClient.Get(targetToUpdate.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Unable to get AzureIngressProhibitedTarget %s/%s", targetToUpdate.Namespace, targetToUpdate.Name)
+	}
+
+	target.Status = status
+
+	if _, err := targetClient.UpdateStatus(target); err != nil {
+		errorLine := fmt.Sprintf("Unable to update AzureIngressProhibitedTarget %s/%s status: error %s", target.Namespace, target.Name, err.Error())
+		glog.Error(errorLine)
+		return errors.New(errorLine)
+	}
+
+	return nil
+}
+
 // IsIngressApplicationGateway checks if applicaiton gateway annotation is present on the ingress
 func IsIngressApplicationGateway(ingress *v1beta1.Ingress) bool {
 	val, _ := annotations.IsApplicationGatewayIngress(ingress)
 	return val
 }
 
+// IsNodeReady checks if the kubelet of the Node reports it ready to run pods.
+func IsNodeReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func hasHTTPRule(ingress *v1beta1.Ingress) bool {
 	for _, rule := range ingress.Spec.Rules {
 		if rule.HTTP != nil {
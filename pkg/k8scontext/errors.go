@@ -10,3 +10,5 @@ import "errors"
 var (
 	ErrFetchingEnpdoints = errors.New("FetchingEndpoints")
 )
+
+var errNamespacedStoreReadOnly = errors.New("the stores of observed namespaces are populated by their informers only")
@@ -0,0 +1,120 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/golang/glog"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// ListGatewayClasses returns a list of discovered Gateway API GatewayClasses, sorted by name.
+func (c *Context) ListGatewayClasses() []*gatewayv1beta1.GatewayClass {
+	var classes []*gatewayv1beta1.GatewayClass
+	for _, class := range c.Caches.GatewayClass.List() {
+		classes = append(classes, class.(*gatewayv1beta1.GatewayClass))
+	}
+	sort.Slice(classes, func(i, j int) bool { return classes[i].Name < classes[j].Name })
+	return classes
+}
+
+// ListGateways returns a list of discovered Gateway API Gateways, sorted by namespace and name.
+func (c *Context) ListGateways() []*gatewayv1beta1.Gateway {
+	var gateways []*gatewayv1beta1.Gateway
+	for _, gateway := range c.Caches.Gateway.List() {
+		gateways = append(gateways, gateway.(*gatewayv1beta1.Gateway))
+	}
+	sort.Slice(gateways, func(i, j int) bool {
+		return utils.GetResourceKey(gateways[i].Namespace, gateways[i].Name) < utils.GetResourceKey(gateways[j].Namespace, gateways[j].Name)
+	})
+	return gateways
+}
+
+// ListHTTPRoutes returns a list of discovered Gateway API HTTPRoutes, sorted by namespace and name.
+func (c *Context) ListHTTPRoutes() []*gatewayv1beta1.HTTPRoute {
+	var routes []*gatewayv1beta1.HTTPRoute
+	for _, route := range c.Caches.HTTPRoute.List() {
+		routes = append(routes, route.(*gatewayv1beta1.HTTPRoute))
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return utils.GetResourceKey(routes[i].Namespace, routes[i].Name) < utils.GetResourceKey(routes[j].Namespace, routes[j].Name)
+	})
+	return routes
+}
+
+// UpdateGatewayClassStatus replaces the status of the given GatewayClass.
+func (c *Context) UpdateGatewayClassStatus(classToUpdate gatewayv1beta1.GatewayClass, status gatewayv1beta1.GatewayClassStatus) error {
+	classClient := c.gatewayCrdClient.GatewayV1beta1().GatewayClasses()
+	class, err := classClient.Get(classToUpdate.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Unable to get GatewayClass %s", classToUpdate.Name)
+	}
+
+	class.Status = status
+
+	if _, err := classClient.UpdateStatus(class); err != nil {
+		errorLine := fmt.Sprintf("Unable to update GatewayClass %s status: error %s", class.Name, err.Error())
+		glog.Error(errorLine)
+		return errors.New(errorLine)
+	}
+
+	return nil
+}
+
+// UpdateGatewayStatus replaces the status of the given Gateway.
+func (c *Context) UpdateGatewayStatus(gatewayToUpdate gatewayv1beta1.Gateway, status gatewayv1beta1.GatewayStatus) error {
+	gatewayClient := c.gatewayCrdClient.GatewayV1beta1().Gateways(gatewayToUpdate.Namespace)
+	gateway, err := gatewayClient.Get(gatewayToUpdate.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Unable to get Gateway %s/%s", gatewayToUpdate.Namespace, gatewayToUpdate.Name)
+	}
+
+	gateway.Status = status
+
+	if _, err := gatewayClient.UpdateStatus(gateway); err != nil {
+		errorLine := fmt.Sprintf("Unable to update Gateway %s/%s status: error %s", gateway.Namespace, gateway.Name, err.Error())
+		glog.Error(errorLine)
+		return errors.New(errorLine)
+	}
+
+	return nil
+}
+
+// UpdateHTTPRouteStatus replaces the status of the given HTTPRoute.
+func (c *Context) UpdateHTTPRouteStatus(routeToUpdate gatewayv1beta1.HTTPRoute, status gatewayv1beta1.HTTPRouteStatus) error {
+	routeClient := c.gatewayCrdClient.GatewayV1beta1().HTTPRoutes(routeToUpdate.Namespace)
+	route, err := routeClient.Get(routeToUpdate.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Unable to get HTTPRoute %s/%s", routeToUpdate.Namespace, routeToUpdate.Name)
+	}
+
+	route.Status = status
+
+	if _, err := routeClient.UpdateStatus(route); err != nil {
+		errorLine := fmt.Sprintf("Unable to update HTTPRoute %s/%s status: error %s", route.Namespace, route.Name, err.Error())
+		glog.Error(errorLine)
+		return errors.New(errorLine)
+	}
+
+	return nil
+}
+
+// isGatewayAPIIngress figures out whether the Ingress carries the annotation reserved for the Ingresses AGIC translates from Gateway listeners.
+func isGatewayAPIIngress(ingress *v1beta1.Ingress) bool {
+	if _, err := annotations.GatewayAPIListener(ingress); err == nil {
+		glog.V(3).Infof("Ignoring Ingress %s/%s; The annotation %s is reserved for AGIC", ingress.Namespace, ingress.Name, annotations.GatewayAPIListenerKey)
+		return true
+	}
+	return false
+}
@@ -3,10 +3,14 @@ package k8scontext
 import (
 	"reflect"
 
+	"github.com/knative/pkg/apis/istio/v1alpha3"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/tools/cache"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
@@ -33,7 +37,7 @@ func (h handlers) ingressAddFunc(obj interface{}) {
 			}
 
 			if secret, exists, err := h.context.Caches.Secret.GetByKey(secKey); exists && err == nil {
-				if !h.context.ingressSecretsMap.ContainsValue(secKey) {
+				if !h.context.isSecretReferenced(secKey) {
 					done := h.context.CertificateSecretStore.convertSecret(secKey, secret.(*v1.Secret))
 					if !done {
 						continue
@@ -95,7 +99,7 @@ func (h handlers) ingressUpdateFunc(oldObj, newObj interface{}) {
 			}
 
 			if secret, exists, err := h.context.Caches.Secret.GetByKey(secKey); exists && err == nil {
-				if !h.context.ingressSecretsMap.ContainsValue(secKey) {
+				if !h.context.isSecretReferenced(secKey) {
 					done := h.context.CertificateSecretStore.convertSecret(secKey, secret.(*v1.Secret))
 					if !done {
 						continue
@@ -117,7 +121,7 @@ func (h handlers) ingressUpdateFunc(oldObj, newObj interface{}) {
 func (h handlers) secretAddFunc(obj interface{}) {
 	sec := obj.(*v1.Secret)
 	secKey := utils.GetResourceKey(sec.Namespace, sec.Name)
-	if h.context.ingressSecretsMap.ContainsValue(secKey) {
+	if h.context.isSecretReferenced(secKey) {
 		// find if this secKey exists in the map[string]UnorderedSets
 		done := h.context.CertificateSecretStore.convertSecret(secKey, sec)
 		if done {
@@ -136,7 +140,7 @@ func (h handlers) secretUpdateFunc(oldObj, newObj interface{}) {
 
 	sec := newObj.(*v1.Secret)
 	secKey := utils.GetResourceKey(sec.Namespace, sec.Name)
-	if h.context.ingressSecretsMap.ContainsValue(secKey) {
+	if h.context.isSecretReferenced(secKey) {
 		done := h.context.CertificateSecretStore.convertSecret(secKey, sec)
 		if done {
 			h.context.UpdateChannel.In() <- events.Event{
@@ -163,7 +167,7 @@ func (h handlers) secretDeleteFunc(obj interface{}) {
 
 	secKey := utils.GetResourceKey(sec.Namespace, sec.Name)
 	h.context.CertificateSecretStore.eraseSecret(secKey)
-	if h.context.ingressSecretsMap.ContainsValue(secKey) {
+	if h.context.isSecretReferenced(secKey) {
 		h.context.UpdateChannel.In() <- events.Event{
 			Type:  events.Delete,
 			Value: obj,
@@ -171,6 +175,169 @@ func (h handlers) secretDeleteFunc(obj interface{}) {
 	}
 }
 
+// istio gateway resource handlers
+func (h handlers) istioGatewayAddFunc(obj interface{}) {
+	gateway := obj.(*v1alpha3.Gateway)
+	h.trackIstioGatewaySecrets(gateway)
+	// Istio Gateways are observed in all namespaces; Only the ones meant for AGIC trigger a reconcile.
+	if annotated, _ := annotations.IsIstioGatewayIngress(gateway); !annotated {
+		return
+	}
+	h.addFunc(obj)
+}
+
+func (h handlers) istioGatewayUpdateFunc(oldObj, newObj interface{}) {
+	if reflect.DeepEqual(oldObj, newObj) {
+		return
+	}
+	gateway := newObj.(*v1alpha3.Gateway)
+	h.trackIstioGatewaySecrets(gateway)
+	wasAnnotated, _ := annotations.IsIstioGatewayIngress(oldObj.(*v1alpha3.Gateway))
+	if annotated, _ := annotations.IsIstioGatewayIngress(gateway); !annotated && !wasAnnotated {
+		return
+	}
+	h.updateFunc(oldObj, newObj)
+}
+
+func (h handlers) istioGatewayDeleteFunc(obj interface{}) {
+	gateway, ok := obj.(*v1alpha3.Gateway)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			// unable to get from tombstone
+			return
+		}
+		gateway, ok = tombstone.Obj.(*v1alpha3.Gateway)
+	}
+	if gateway == nil {
+		return
+	}
+	h.context.istioGatewaySecretsMap.Erase(utils.GetResourceKey(gateway.Namespace, gateway.Name))
+	if annotated, _ := annotations.IsIstioGatewayIngress(gateway); !annotated {
+		return
+	}
+	h.deleteFunc(obj)
+}
+
+// trackIstioGatewaySecrets loads the secrets referenced by TLS.CredentialName of the servers of the Gateway in the certificate store.
+func (h handlers) trackIstioGatewaySecrets(gateway *v1alpha3.Gateway) {
+	gatewayKey := utils.GetResourceKey(gateway.Namespace, gateway.Name)
+	h.context.istioGatewaySecretsMap.Clear(gatewayKey)
+	if annotated, _ := annotations.IsIstioGatewayIngress(gateway); !annotated {
+		return
+	}
+	for _, server := range gateway.Spec.Servers {
+		if server.TLS == nil || server.TLS.CredentialName == "" {
+			continue
+		}
+		secKey := utils.GetResourceKey(gateway.Namespace, server.TLS.CredentialName)
+		if secret, exists, err := h.context.Caches.Secret.GetByKey(secKey); exists && err == nil {
+			if !h.context.isSecretReferenced(secKey) {
+				if done := h.context.CertificateSecretStore.convertSecret(secKey, secret.(*v1.Secret)); !done {
+					continue
+				}
+			}
+		}
+		h.context.istioGatewaySecretsMap.Insert(gatewayKey, secKey)
+	}
+}
+
+// gateway api gateway resource handlers
+func (h handlers) gatewayAddFunc(obj interface{}) {
+	gateway := obj.(*gatewayv1beta1.Gateway)
+	h.trackGatewaySecrets(gateway)
+	h.addFunc(obj)
+}
+
+func (h handlers) gatewayUpdateFunc(oldObj, newObj interface{}) {
+	if isStatusUpdate(oldObj, newObj) {
+		return
+	}
+	gateway := newObj.(*gatewayv1beta1.Gateway)
+	h.trackGatewaySecrets(gateway)
+	h.updateFunc(oldObj, newObj)
+}
+
+func (h handlers) gatewayDeleteFunc(obj interface{}) {
+	gateway, ok := obj.(*gatewayv1beta1.Gateway)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			// unable to get from tombstone
+			return
+		}
+		gateway, ok = tombstone.Obj.(*gatewayv1beta1.Gateway)
+	}
+	if gateway == nil {
+		return
+	}
+	h.context.gatewaySecretsMap.Erase(utils.GetResourceKey(gateway.Namespace, gateway.Name))
+	h.deleteFunc(obj)
+}
+
+// trackGatewaySecrets loads the secrets referenced by the certificateRefs of the listeners of the Gateway in the certificate store.
+// The GatewayClass may not be known yet, so the secrets of the Gateways of all classes are tracked; References to other namespaces are not permitted.
+func (h handlers) trackGatewaySecrets(gateway *gatewayv1beta1.Gateway) {
+	gatewayKey := utils.GetResourceKey(gateway.Namespace, gateway.Name)
+	h.context.gatewaySecretsMap.Clear(gatewayKey)
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, ref := range listener.TLS.CertificateRefs {
+			if ref.Namespace != nil && *ref.Namespace != gateway.Namespace {
+				continue
+			}
+			secKey := utils.GetResourceKey(gateway.Namespace, ref.Name)
+			if secret, exists, err := h.context.Caches.Secret.GetByKey(secKey); exists && err == nil {
+				if !h.context.isSecretReferenced(secKey) {
+					if done := h.context.CertificateSecretStore.convertSecret(secKey, secret.(*v1.Secret)); !done {
+						continue
+					}
+				}
+			}
+			h.context.gatewaySecretsMap.Insert(gatewayKey, secKey)
+		}
+	}
+}
+
+// gatewayAPIUpdateFunc reconciles on changes to the spec of Gateway API resources; Not on the status AGIC writes itself.
+func (h handlers) gatewayAPIUpdateFunc(oldObj, newObj interface{}) {
+	if isStatusUpdate(oldObj, newObj) {
+		return
+	}
+	h.updateFunc(oldObj, newObj)
+}
+
+// isStatusUpdate figures out whether the update left the generation of the resource unchanged, i.e. changed its status or metadata only.
+func isStatusUpdate(oldObj, newObj interface{}) bool {
+	oldMeta, err := meta.Accessor(oldObj)
+	if err != nil {
+		return false
+	}
+	newMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return false
+	}
+	return newMeta.GetGeneration() != 0 && oldMeta.GetGeneration() == newMeta.GetGeneration()
+}
+
+// nodeUpdateFunc reconciles on changes to the addresses and readiness of a Node; Not on the heartbeats of the kubelet.
+func (h handlers) nodeUpdateFunc(oldObj, newObj interface{}) {
+	oldNode, ok := oldObj.(*v1.Node)
+	if !ok {
+		return
+	}
+	node, ok := newObj.(*v1.Node)
+	if !ok {
+		return
+	}
+	if reflect.DeepEqual(oldNode.Status.Addresses, node.Status.Addresses) && IsNodeReady(oldNode) == IsNodeReady(node) {
+		return
+	}
+	h.updateFunc(oldObj, newObj)
+}
+
 // general resource handlers
 func (h handlers) addFunc(obj interface{}) {
 	h.context.UpdateChannel.In() <- events.Event{
@@ -5,7 +5,14 @@
 
 package k8scontext
 
-import "github.com/knative/pkg/apis/istio/v1alpha3"
+import (
+	"strings"
+
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+)
+
+// IstioMeshGateway is the reserved gateway reference to the sidecars of the mesh; AGIC does not configure these.
+const IstioMeshGateway = "mesh"
 
 // ListIstioGateways returns a list of discovered Istio Gateways
 func (c *Context) ListIstioGateways() []*v1alpha3.Gateway {
@@ -24,3 +31,37 @@ func (c *Context) ListIstioVirtualServices() []*v1alpha3.VirtualService {
 	}
 	return virtualServices
 }
+
+// ListIstioDestinationRules returns a list of discovered Istio Destination Rules
+func (c *Context) ListIstioDestinationRules() []*v1alpha3.DestinationRule {
+	var destinationRules []*v1alpha3.DestinationRule
+	for _, destinationRule := range c.Caches.IstioDestinationRule.List() {
+		destinationRules = append(destinationRules, destinationRule.(*v1alpha3.DestinationRule))
+	}
+	return destinationRules
+}
+
+// IsIstioGatewayReferenced figures out whether the gateway references, given by a VirtualService in the given namespace,
+// refer to the Gateway. A reference is either "namespace/name", or the bare name of a Gateway in the namespace of the
+// VirtualService.
+func IsIstioGatewayReferenced(references []string, namespace string, gateway *v1alpha3.Gateway) bool {
+	for _, reference := range references {
+		if reference == IstioMeshGateway {
+			continue
+		}
+		gatewayNamespace, gatewayName := namespace, reference
+		if chunks := strings.SplitN(reference, "/", 2); len(chunks) == 2 {
+			gatewayNamespace, gatewayName = chunks[0], chunks[1]
+		}
+		if gatewayNamespace == gateway.Namespace && gatewayName == gateway.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIstioGatewayBound figures out whether the VirtualService applies to the Gateway. A VirtualService, which references
+// no gateways, applies to the sidecars of the mesh only.
+func IsIstioGatewayBound(virtualService *v1alpha3.VirtualService, gateway *v1alpha3.Gateway) bool {
+	return IsIstioGatewayReferenced(virtualService.Spec.Gateways, virtualService.Namespace, gateway)
+}
@@ -6,21 +6,30 @@
 package k8scontext_test
 
 import (
+	"errors"
 	go_flag "flag"
 	"reflect"
 	"time"
 
 	"github.com/getlantern/deepcopy"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	managedv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
+	prohibitedv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gateway_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
 	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
@@ -30,6 +39,9 @@ import (
 
 var _ = Describe("K8scontext", func() {
 	var k8sClient kubernetes.Interface
+	var crdClient *fake.Clientset
+	var istioCrdClient *istio_fake.Clientset
+	var gatewayCrdClient *gateway_fake.Clientset
 	var ctxt *k8scontext.Context
 	ingressNS := "test-ingress-controller"
 	ingressName := "hello-world"
@@ -78,13 +90,21 @@ var _ = Describe("K8scontext", func() {
 		}
 	}
 
+	// forbid lets the client reject listing the resource, like the API server does for resources outside of the Roles of AGIC.
+	forbid := func(client *testclient.Clientset, resource string) {
+		client.PrependReactor("list", resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(v1.Resource(resource), "", errors.New("no RBAC policy matched"))
+		})
+	}
+
 	BeforeEach(func() {
 		stopChannel = make(chan struct{})
 
 		// Create the mock K8s client.
 		k8sClient = testclient.NewSimpleClientset()
-		crdClient := fake.NewSimpleClientset()
-		istioCrdClient := istio_fake.NewSimpleClientset()
+		crdClient = fake.NewSimpleClientset()
+		istioCrdClient = istio_fake.NewSimpleClientset()
+		gatewayCrdClient = gateway_fake.NewSimpleClientset()
 
 		_, err := k8sClient.CoreV1().Namespaces().Create(ns)
 		Expect(err).Should(BeNil(), "Unable to create the namespace %s: %v", ingressNS, err)
@@ -94,7 +114,7 @@ var _ = Describe("K8scontext", func() {
 		Expect(err).Should(BeNil(), "Unabled to create ingress resource due to: %v", err)
 
 		// Create a `k8scontext` to start listening to ingress resources.
-		ctxt = k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, []string{ingressNS}, 1000*time.Second)
+		ctxt = k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, gatewayCrdClient, []string{ingressNS}, nil, 1000*time.Second)
 
 		Expect(ctxt).ShouldNot(BeNil(), "Unable to create `k8scontext`")
 	})
@@ -111,7 +131,7 @@ var _ = Describe("K8scontext", func() {
 			Expect(len(ingresses.Items)).To(Equal(1), "Expected to have a single ingress stored in mock K8s but found: %d ingresses", len(ingresses.Items))
 
 			// Start the informers. This will sync the cache with the latest ingress.
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			ingressListInterface := ctxt.Caches.Ingress.List()
 			Expect(len(ingressListInterface)).To(Equal(1), "Expected to have a single ingress in the cache but found: %d ingresses", len(ingressListInterface))
@@ -138,7 +158,7 @@ var _ = Describe("K8scontext", func() {
 
 			// Due to the large sync time we don't expect the cache to be synced, till we force sync the cache.
 			// Start the informers. This will sync the cache with the latest ingress.
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			ingressListInterface := ctxt.Caches.Ingress.List()
 			// There should still be only one ingress resource.
@@ -163,7 +183,7 @@ var _ = Describe("K8scontext", func() {
 
 			// Due to the large sync time we don't expect the cache to be synced, till we force sync the cache.
 			// Start the informers. This will sync the cache with the latest ingress.
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			ingressListInterface := ctxt.Caches.Ingress.List()
 			// There should still be only one ingress resource.
@@ -191,7 +211,7 @@ var _ = Describe("K8scontext", func() {
 
 			// Due to the large sync time we don't expect the cache to be synced, till we force sync the cache.
 			// Start the informers. This will sync the cache with the latest ingress.
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			ingressListInterface := ctxt.Caches.Ingress.List()
 			// There should two ingress resource.
@@ -223,7 +243,7 @@ var _ = Describe("K8scontext", func() {
 			Expect(len(podList.Items)).To(Equal(2), "Expected to have two pod stored but found: %d pods", len(podList.Items))
 
 			// Run context
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			// Get and check that one of the pods exists.
 			_, exists, _ := ctxt.Caches.Pods.Get(pod)
@@ -262,7 +282,7 @@ var _ = Describe("K8scontext", func() {
 	Context("Checking if we are able to skip unrelated pod events", func() {
 		It("should be able to select related pods", func() {
 			// start context for syncing
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			// create a POD with labels
 			_, err := k8sClient.CoreV1().Pods(ingressNS).Create(pod)
@@ -287,7 +307,7 @@ var _ = Describe("K8scontext", func() {
 
 		It("should be able to skip unrelated pods", func() {
 			// start context for syncing
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			// modify the labels on the POD
 			pod.Labels = map[string]string{
@@ -317,7 +337,7 @@ var _ = Describe("K8scontext", func() {
 	Context("Checking if we are able to skip unrelated endpoints events", func() {
 		It("should be able to select related endpoints", func() {
 			// start context for syncing
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			endpoints := tests.NewEndpointsFixture()
 			endpoints.Namespace = ingressNS
@@ -345,7 +365,7 @@ var _ = Describe("K8scontext", func() {
 
 		It("should be able to skip unrelated endpoints", func() {
 			// start context for syncing
-			ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			ctxt.Run(stopChannel, environment.GetFakeEnv())
 
 			endpoints := tests.NewEndpointsFixture()
 			endpoints.Name = "random"
@@ -373,6 +393,236 @@ var _ = Describe("K8scontext", func() {
 		})
 	})
 
+	Context("Checking CRD informers", func() {
+		It("waits for the CRD informers to sync when brownfield and Istio are enabled", func() {
+			prohibitedTarget := &prohibitedv1.AzureIngressProhibitedTarget{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "prohibited-target",
+					Namespace: ingressNS,
+				},
+				Spec: prohibitedv1.AzureIngressProhibitedTargetSpec{
+					Hostname: "www.contoso.com",
+				},
+			}
+			_, err := crdClient.AzureingressprohibitedtargetsV1().AzureIngressProhibitedTargets(ingressNS).Create(prohibitedTarget)
+			Expect(err).ToNot(HaveOccurred())
+
+			managedTarget := &managedv1.AzureIngressManagedTarget{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "managed-target",
+					Namespace: ingressNS,
+				},
+				Spec: managedv1.AzureIngressManagedTargetSpec{
+					Hostname: "app.contoso.com",
+				},
+			}
+			_, err = crdClient.AzureingressmanagedtargetsV1().AzureIngressManagedTargets(ingressNS).Create(managedTarget)
+			Expect(err).ToNot(HaveOccurred())
+
+			env := environment.GetFakeEnv()
+			env.EnableBrownfieldDeployment = "true"
+			env.EnableIstioIntegration = "true"
+			Expect(ctxt.Run(stopChannel, env)).To(Succeed())
+
+			// The caches are complete as soon as Run returns.
+			Expect(len(ctxt.ListAzureProhibitedTargets())).To(Equal(1))
+			Expect(len(ctxt.ListAzureManagedTargets())).To(Equal(1))
+			Expect(ctxt.ListIstioGateways()).To(BeEmpty())
+		})
+	})
+
+	Context("Checking Istio informers", func() {
+		newGateway := func(namespace string, name string, annotated bool) *v1alpha3.Gateway {
+			gateway := &v1alpha3.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        name,
+					Namespace:   namespace,
+					Annotations: map[string]string{},
+				},
+			}
+			if annotated {
+				gateway.Annotations[annotations.IstioGatewayKey] = annotations.ApplicationGatewayIngressClass
+			}
+			return gateway
+		}
+
+		It("observes the Istio Gateways of all namespaces and the VirtualServices of the watched namespaces only", func() {
+			for _, gateway := range []*v1alpha3.Gateway{
+				newGateway(ingressNS, "annotated", true),
+				newGateway(ingressNS, "not-annotated", false),
+				newGateway("istio-system", "ingress", true),
+			} {
+				_, err := istioCrdClient.NetworkingV1alpha3().Gateways(gateway.Namespace).Create(gateway)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			for _, virtualService := range []*v1alpha3.VirtualService{
+				{ObjectMeta: metav1.ObjectMeta{Name: "short-name", Namespace: ingressNS}, Spec: v1alpha3.VirtualServiceSpec{Gateways: []string{"annotated"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "mesh-only", Namespace: ingressNS}, Spec: v1alpha3.VirtualServiceSpec{Gateways: []string{k8scontext.IstioMeshGateway}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "istio-system", Namespace: ingressNS}, Spec: v1alpha3.VirtualServiceSpec{Gateways: []string{"istio-system/ingress"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "not-watched", Namespace: "not-watched"}, Spec: v1alpha3.VirtualServiceSpec{Gateways: []string{ingressNS + "/annotated", "istio-system/ingress"}}},
+			} {
+				_, err := istioCrdClient.NetworkingV1alpha3().VirtualServices(virtualService.Namespace).Create(virtualService)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			env := environment.GetFakeEnv()
+			env.EnableIstioIntegration = "true"
+			Expect(ctxt.Run(stopChannel, env)).To(Succeed())
+
+			Expect(ctxt.ListIstioGateways()).To(HaveLen(3))
+			Expect(ctxt.ListIstioVirtualServices()).To(HaveLen(3))
+
+			virtualServiceNames := make(map[string][]string)
+			for _, gateway := range ctxt.GetGateways() {
+				gatewayKey := gateway.Namespace + "/" + gateway.Name
+				virtualServiceNames[gatewayKey] = []string{}
+				for _, virtualService := range ctxt.GetVirtualServicesForGateway(*gateway) {
+					virtualServiceNames[gatewayKey] = append(virtualServiceNames[gatewayKey], virtualService.Name)
+				}
+			}
+			Expect(virtualServiceNames).To(Equal(map[string][]string{
+				ingressNS + "/annotated": {"short-name"},
+				"istio-system/ingress":   {"istio-system"},
+			}))
+		})
+
+		It("resolves gateway references the way Istio does", func() {
+			gateway := newGateway("istio-system", "ingress", true)
+			Expect(k8scontext.IsIstioGatewayReferenced([]string{"ingress"}, "istio-system", gateway)).To(BeTrue())
+			Expect(k8scontext.IsIstioGatewayReferenced([]string{"istio-system/ingress"}, ingressNS, gateway)).To(BeTrue())
+			Expect(k8scontext.IsIstioGatewayReferenced([]string{"ingress"}, ingressNS, gateway)).To(BeFalse())
+			Expect(k8scontext.IsIstioGatewayReferenced([]string{"other/ingress", k8scontext.IstioMeshGateway}, ingressNS, gateway)).To(BeFalse())
+			Expect(k8scontext.IsIstioGatewayReferenced(nil, "istio-system", gateway)).To(BeFalse())
+		})
+	})
+
+	Context("Checking namespace selection", func() {
+		It("starts and stops observing namespaces as they come and go", func() {
+			selector, err := labels.Parse("agic=enabled")
+			Expect(err).ToNot(HaveOccurred())
+			selectedCtxt := k8scontext.NewContext(k8sClient, crdClient, istio_fake.NewSimpleClientset(), gateway_fake.NewSimpleClientset(), []string{"does-not-exist-yet"}, selector, 1000*time.Second)
+			Expect(selectedCtxt.Run(stopChannel, environment.GetFakeEnv())).To(Succeed())
+
+			// The namespace of the BeforeEach is neither listed nor labeled.
+			Expect(selectedCtxt.ListHTTPIngresses()).To(BeEmpty())
+
+			listIngressNames := func() []string {
+				var names []string
+				for _, ingress := range selectedCtxt.ListHTTPIngresses() {
+					names = append(names, ingress.Namespace+"/"+ingress.Name)
+				}
+				return names
+			}
+
+			// Label the namespace.
+			labeled := ns.DeepCopy()
+			labeled.Labels = map[string]string{"agic": "enabled"}
+			_, err = k8sClient.CoreV1().Namespaces().Update(labeled)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(listIngressNames).Should(Equal([]string{ingressNS + "/" + ingressName}))
+
+			// Create the listed namespace.
+			listedIngress := tests.NewIngressTestFixture("does-not-exist-yet", "listed")
+			_, err = k8sClient.CoreV1().Namespaces().Create(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "does-not-exist-yet"}})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = k8sClient.ExtensionsV1beta1().Ingresses("does-not-exist-yet").Create(&listedIngress)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(listIngressNames).Should(ConsistOf(ingressNS+"/"+ingressName, "does-not-exist-yet/listed"))
+
+			// Remove the label again.
+			_, err = k8sClient.CoreV1().Namespaces().Update(ns)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(listIngressNames).Should(Equal([]string{"does-not-exist-yet/listed"}))
+		})
+
+		It("observes the listed namespaces, when AGIC is not allowed to list namespaces", func() {
+			client := testclient.NewSimpleClientset(ns, ingress)
+			forbid(client, "namespaces")
+			forbiddenCtxt := k8scontext.NewContext(client, crdClient, istioCrdClient, gatewayCrdClient, []string{ingressNS}, nil, 1000*time.Second)
+			Expect(forbiddenCtxt.Run(stopChannel, environment.GetFakeEnv())).To(Succeed())
+			Expect(forbiddenCtxt.ListNamespaces()).To(BeEmpty())
+			Expect(forbiddenCtxt.ListObservedNamespaces()).To(Equal([]string{ingressNS}))
+			Expect(forbiddenCtxt.ListHTTPIngresses()).To(HaveLen(1))
+		})
+
+		It("fails to run with a namespace selector, when AGIC is not allowed to list namespaces", func() {
+			client := testclient.NewSimpleClientset(ns)
+			forbid(client, "namespaces")
+			selector, err := labels.Parse("agic=enabled")
+			Expect(err).ToNot(HaveOccurred())
+			forbiddenCtxt := k8scontext.NewContext(client, crdClient, istioCrdClient, gatewayCrdClient, nil, selector, 1000*time.Second)
+			Expect(forbiddenCtxt.Run(stopChannel, environment.GetFakeEnv())).ToNot(Succeed())
+		})
+	})
+
+	Context("Checking the Node informer", func() {
+		It("observes the readiness and addresses of the nodes, not their heartbeats", func() {
+			node := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Status: v1.NodeStatus{
+					Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.240.0.4"}},
+					Conditions: []v1.NodeCondition{
+						{Type: v1.NodeReady, Status: v1.ConditionTrue},
+					},
+				},
+			}
+			_, err := k8sClient.CoreV1().Nodes().Create(node)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ctxt.Run(stopChannel, environment.GetFakeEnv())).To(Succeed())
+
+			nodes := ctxt.ListNodes()
+			Expect(len(nodes)).To(Equal(1))
+			Expect(k8scontext.IsNodeReady(nodes[0])).To(BeTrue())
+
+			// nodeEvents drains the update channel and counts the events of the node.
+			nodeEvents := func() int {
+				count := 0
+				for {
+					select {
+					case in := <-ctxt.UpdateChannel.Out():
+						if _, ok := in.(events.Event).Value.(*v1.Node); ok {
+							count++
+						}
+					case <-time.After(100 * time.Millisecond):
+						return count
+					}
+				}
+			}
+			nodeEvents()
+
+			heartbeat := node.DeepCopy()
+			heartbeat.Status.Conditions[0].LastHeartbeatTime = metav1.Now()
+			_, err = k8sClient.CoreV1().Nodes().UpdateStatus(heartbeat)
+			Expect(err).ToNot(HaveOccurred())
+			Consistently(nodeEvents, 500*time.Millisecond).Should(Equal(0))
+
+			notReady := heartbeat.DeepCopy()
+			notReady.Status.Conditions[0].Status = v1.ConditionFalse
+			_, err = k8sClient.CoreV1().Nodes().UpdateStatus(notReady)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(nodeEvents).Should(Equal(1))
+			Expect(k8scontext.IsNodeReady(ctxt.ListNodes()[0])).To(BeFalse())
+		})
+
+		It("runs without the nodes, when AGIC is not allowed to list them", func() {
+			client := testclient.NewSimpleClientset(ns, ingress)
+			forbid(client, "nodes")
+			forbiddenCtxt := k8scontext.NewContext(client, crdClient, istioCrdClient, gatewayCrdClient, []string{ingressNS}, nil, 1000*time.Second)
+			Expect(forbiddenCtxt.Run(stopChannel, environment.GetFakeEnv())).To(Succeed())
+			Expect(forbiddenCtxt.ListNodes()).To(BeEmpty())
+			Expect(forbiddenCtxt.ListHTTPIngresses()).To(HaveLen(1))
+		})
+
+		It("fails to run in the nodeport backend mode, when AGIC is not allowed to list the nodes", func() {
+			client := testclient.NewSimpleClientset(ns)
+			forbid(client, "nodes")
+			forbiddenCtxt := k8scontext.NewContext(client, crdClient, istioCrdClient, gatewayCrdClient, []string{ingressNS}, nil, 1000*time.Second)
+			env := environment.GetFakeEnv()
+			env.BackendMode = annotations.BackendModeNodePort
+			Expect(forbiddenCtxt.Run(stopChannel, env)).ToNot(Succeed())
+		})
+	})
+
 	Context("Checking AddIngressStatus and RemoveIngressStatus", func() {
 		ip := k8scontext.IPAddress("address")
 		It("adds IP when not present and then removes", func() {
@@ -0,0 +1,443 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/informers/externalversions"
+	gateway_versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned"
+	gateway_externalversions "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/informers/externalversions"
+	istio_versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned"
+	istio_externalversions "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/informers/externalversions"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+// newNamespacedInformers creates the informers for the namespaced resources AGIC observes.
+func newNamespacedInformers(informerFactory informers.SharedInformerFactory, crdInformerFactory externalversions.SharedInformerFactory, istioCrdInformerFactory istio_externalversions.SharedInformerFactory, gatewayCrdInformerFactory gateway_externalversions.SharedInformerFactory) InformerCollection {
+	return InformerCollection{
+		Endpoints: informerFactory.Core().V1().Endpoints().Informer(),
+		Ingress:   informerFactory.Extensions().V1beta1().Ingresses().Informer(),
+		Pods:      informerFactory.Core().V1().Pods().Informer(),
+		Secret:    informerFactory.Core().V1().Secrets().Informer(),
+		Service:   informerFactory.Core().V1().Services().Informer(),
+
+		AzureIngressManagedTarget:    crdInformerFactory.Azureingressmanagedtargets().V1().AzureIngressManagedTargets().Informer(),
+		AzureIngressProhibitedTarget: crdInformerFactory.Azureingressprohibitedtargets().V1().AzureIngressProhibitedTargets().Informer(),
+
+		IstioVirtualService:  istioCrdInformerFactory.Networking().V1alpha3().VirtualServices().Informer(),
+		IstioDestinationRule: istioCrdInformerFactory.Networking().V1alpha3().DestinationRules().Informer(),
+
+		Gateway:   gatewayCrdInformerFactory.Gateway().V1beta1().Gateways().Informer(),
+		HTTPRoute: gatewayCrdInformerFactory.Gateway().V1beta1().HTTPRoutes().Informer(),
+	}
+}
+
+// registerHandlers registers the event handlers of the namespaced informers.
+func (ic InformerCollection) registerHandlers(h handlers) {
+	resourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.addFunc,
+		UpdateFunc: h.updateFunc,
+		DeleteFunc: h.deleteFunc,
+	}
+
+	ingressResourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.ingressAddFunc,
+		UpdateFunc: h.ingressUpdateFunc,
+		DeleteFunc: h.ingressDeleteFunc,
+	}
+
+	secretResourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.secretAddFunc,
+		UpdateFunc: h.secretUpdateFunc,
+		DeleteFunc: h.secretDeleteFunc,
+	}
+
+	gatewayResourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.gatewayAddFunc,
+		UpdateFunc: h.gatewayUpdateFunc,
+		DeleteFunc: h.gatewayDeleteFunc,
+	}
+
+	httpRouteResourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.addFunc,
+		UpdateFunc: h.gatewayAPIUpdateFunc,
+		DeleteFunc: h.deleteFunc,
+	}
+
+	ic.Endpoints.AddEventHandler(resourceHandler)
+	ic.Ingress.AddEventHandler(ingressResourceHandler)
+	ic.Pods.AddEventHandler(resourceHandler)
+	ic.Secret.AddEventHandler(secretResourceHandler)
+	ic.Service.AddEventHandler(resourceHandler)
+	ic.AzureIngressManagedTarget.AddEventHandler(resourceHandler)
+	ic.AzureIngressProhibitedTarget.AddEventHandler(resourceHandler)
+	ic.IstioVirtualService.AddEventHandler(resourceHandler)
+	ic.IstioDestinationRule.AddEventHandler(resourceHandler)
+	ic.Gateway.AddEventHandler(gatewayResourceHandler)
+	ic.HTTPRoute.AddEventHandler(httpRouteResourceHandler)
+}
+
+// namespacedStores are the stores of the namespaced resources across all observed namespaces.
+type namespacedStores struct {
+	Endpoints                    *namespacedStore
+	Ingress                      *namespacedStore
+	Pods                         *namespacedStore
+	Secret                       *namespacedStore
+	Service                      *namespacedStore
+	AzureIngressManagedTarget    *namespacedStore
+	AzureIngressProhibitedTarget *namespacedStore
+	IstioVirtualService          *namespacedStore
+	IstioDestinationRule         *namespacedStore
+	Gateway                      *namespacedStore
+	HTTPRoute                    *namespacedStore
+}
+
+// namespaceInformers are the informers of a single observed namespace.
+type namespaceInformers struct {
+	informers []cache.SharedInformer
+	done      chan struct{}
+}
+
+// namespaceWatcher starts the informers of a namespace once it is selected, and stops them once it is deleted or no longer selected.
+type namespaceWatcher struct {
+	sync.Mutex
+
+	kubeClient       kubernetes.Interface
+	crdClient        versioned.Interface
+	istioCrdClient   istio_versioned.Interface
+	gatewayCrdClient gateway_versioned.Interface
+	resyncPeriod     time.Duration
+	handlers         handlers
+
+	// names are the namespaces listed explicitly; selector selects more namespaces by their labels.
+	names    map[string]interface{}
+	selector labels.Selector
+
+	stores  namespacedStores
+	running map[string]*namespaceInformers
+
+	// stopChannel, withCRDs, withIstio and withGatewayAPI are set by run; Namespaces are not started before.
+	stopChannel    <-chan struct{}
+	withCRDs       bool
+	withIstio      bool
+	withGatewayAPI bool
+}
+
+func newNamespaceWatcher(kubeClient kubernetes.Interface, crdClient versioned.Interface, istioCrdClient istio_versioned.Interface, gatewayCrdClient gateway_versioned.Interface, namespaces []string, selector labels.Selector, resyncPeriod time.Duration, h handlers) *namespaceWatcher {
+	names := make(map[string]interface{})
+	for _, namespace := range namespaces {
+		names[namespace] = nil
+	}
+	return &namespaceWatcher{
+		kubeClient:       kubeClient,
+		crdClient:        crdClient,
+		istioCrdClient:   istioCrdClient,
+		gatewayCrdClient: gatewayCrdClient,
+		resyncPeriod:     resyncPeriod,
+		handlers:         h,
+		names:            names,
+		selector:         selector,
+		stores: namespacedStores{
+			Endpoints:                    newNamespacedStore(),
+			Ingress:                      newNamespacedStore(),
+			Pods:                         newNamespacedStore(),
+			Secret:                       newNamespacedStore(),
+			Service:                      newNamespacedStore(),
+			AzureIngressManagedTarget:    newNamespacedStore(),
+			AzureIngressProhibitedTarget: newNamespacedStore(),
+			IstioVirtualService:          newNamespacedStore(),
+			IstioDestinationRule:         newNamespacedStore(),
+			Gateway:                      newNamespacedStore(),
+			HTTPRoute:                    newNamespacedStore(),
+		},
+		running: make(map[string]*namespaceInformers),
+	}
+}
+
+// run lets the watcher start the informers of the namespaces synced from now on.
+func (w *namespaceWatcher) run(stopChannel <-chan struct{}, withCRDs bool, withIstio bool, withGatewayAPI bool) {
+	w.Lock()
+	defer w.Unlock()
+	w.stopChannel = stopChannel
+	w.withCRDs = withCRDs
+	w.withIstio = withIstio
+	w.withGatewayAPI = withGatewayAPI
+}
+
+// isSelected figures out whether AGIC observes the namespace.
+func (w *namespaceWatcher) isSelected(namespace *v1.Namespace) bool {
+	if _, exists := w.names[namespace.Name]; exists {
+		return true
+	}
+	return w.selector != nil && w.selector.Matches(labels.Set(namespace.Labels))
+}
+
+// sync starts or stops the informers of the namespace, depending on whether it is selected.
+func (w *namespaceWatcher) sync(namespace *v1.Namespace) {
+	if w.isSelected(namespace) {
+		w.start(namespace.Name)
+	} else {
+		w.stop(namespace.Name)
+	}
+}
+
+// start starts the informers of the namespace, unless they are running already.
+func (w *namespaceWatcher) start(namespace string) {
+	w.Lock()
+	defer w.Unlock()
+	if _, exists := w.running[namespace]; exists || w.stopChannel == nil {
+		return
+	}
+
+	glog.V(1).Infof("Starting to observe namespace %s", namespace)
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(w.kubeClient, w.resyncPeriod, informers.WithNamespace(namespace))
+	crdInformerFactory := externalversions.NewSharedInformerFactoryWithOptions(w.crdClient, w.resyncPeriod, externalversions.WithNamespace(namespace))
+	istioCrdInformerFactory := istio_externalversions.NewSharedInformerFactoryWithOptions(w.istioCrdClient, w.resyncPeriod, istio_externalversions.WithNamespace(namespace))
+	gatewayCrdInformerFactory := gateway_externalversions.NewSharedInformerFactoryWithOptions(w.gatewayCrdClient, w.resyncPeriod, gateway_externalversions.WithNamespace(namespace))
+	namespaced := newNamespacedInformers(informerFactory, crdInformerFactory, istioCrdInformerFactory, gatewayCrdInformerFactory)
+	namespaced.registerHandlers(w.handlers)
+
+	w.stores.Endpoints.add(namespace, namespaced.Endpoints.GetStore())
+	w.stores.Ingress.add(namespace, namespaced.Ingress.GetStore())
+	w.stores.Pods.add(namespace, namespaced.Pods.GetStore())
+	w.stores.Secret.add(namespace, namespaced.Secret.GetStore())
+	w.stores.Service.add(namespace, namespaced.Service.GetStore())
+	w.stores.AzureIngressManagedTarget.add(namespace, namespaced.AzureIngressManagedTarget.GetStore())
+	w.stores.AzureIngressProhibitedTarget.add(namespace, namespaced.AzureIngressProhibitedTarget.GetStore())
+	w.stores.IstioVirtualService.add(namespace, namespaced.IstioVirtualService.GetStore())
+	w.stores.IstioDestinationRule.add(namespace, namespaced.IstioDestinationRule.GetStore())
+	w.stores.Gateway.add(namespace, namespaced.Gateway.GetStore())
+	w.stores.HTTPRoute.add(namespace, namespaced.HTTPRoute.GetStore())
+
+	sharedInformers := []cache.SharedInformer{
+		namespaced.Endpoints,
+		namespaced.Ingress,
+		namespaced.Pods,
+		namespaced.Secret,
+		namespaced.Service,
+	}
+	if w.withCRDs {
+		sharedInformers = append(sharedInformers, namespaced.AzureIngressManagedTarget, namespaced.AzureIngressProhibitedTarget)
+	}
+	if w.withIstio {
+		sharedInformers = append(sharedInformers, namespaced.IstioVirtualService, namespaced.IstioDestinationRule)
+	}
+	if w.withGatewayAPI {
+		sharedInformers = append(sharedInformers, namespaced.Gateway, namespaced.HTTPRoute)
+	}
+
+	// The informers of the namespace stop when the namespace is no longer observed, or when AGIC stops.
+	done := make(chan struct{})
+	stop := make(chan struct{})
+	go func(stopChannel <-chan struct{}) {
+		select {
+		case <-stopChannel:
+		case <-done:
+		}
+		close(stop)
+	}(w.stopChannel)
+	for _, informer := range sharedInformers {
+		go informer.Run(stop)
+	}
+
+	w.running[namespace] = &namespaceInformers{
+		informers: sharedInformers,
+		done:      done,
+	}
+}
+
+// startListed starts the informers of the listed namespaces, whether they exist or not; AGIC can not tell without listing namespaces.
+func (w *namespaceWatcher) startListed() {
+	for namespace := range w.names {
+		w.start(namespace)
+	}
+}
+
+// stop stops the informers of the namespace, if they are running, and drops the resources of the namespace from the caches.
+func (w *namespaceWatcher) stop(namespace string) {
+	w.Lock()
+	defer w.Unlock()
+	running, exists := w.running[namespace]
+	if !exists {
+		return
+	}
+
+	glog.V(1).Infof("Stopping to observe namespace %s", namespace)
+	close(running.done)
+	delete(w.running, namespace)
+	w.stores.Endpoints.remove(namespace)
+	w.stores.Ingress.remove(namespace)
+	w.stores.Pods.remove(namespace)
+	w.stores.Secret.remove(namespace)
+	w.stores.Service.remove(namespace)
+	w.stores.AzureIngressManagedTarget.remove(namespace)
+	w.stores.AzureIngressProhibitedTarget.remove(namespace)
+	w.stores.IstioVirtualService.remove(namespace)
+	w.stores.IstioDestinationRule.remove(namespace)
+	w.stores.Gateway.remove(namespace)
+	w.stores.HTTPRoute.remove(namespace)
+
+	// The informers do not report the resources of the namespace as deleted; Reconcile to remove their config.
+	w.handlers.context.UpdateChannel.In() <- events.Event{
+		Type:  events.Delete,
+		Value: namespace,
+	}
+}
+
+// watched lists the namespaces observed at the moment.
+func (w *namespaceWatcher) watched() []string {
+	w.Lock()
+	defer w.Unlock()
+	var namespaces []string
+	for namespace := range w.running {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// hasSynced lists the HasSynced functions of the informers of all observed namespaces.
+func (w *namespaceWatcher) hasSynced() []cache.InformerSynced {
+	w.Lock()
+	defer w.Unlock()
+	var hasSynced []cache.InformerSynced
+	for _, running := range w.running {
+		for _, informer := range running.informers {
+			hasSynced = append(hasSynced, informer.HasSynced)
+		}
+	}
+	return hasSynced
+}
+
+// namespacedStore is a read-only cache.Store over the stores of the informers of each observed namespace.
+type namespacedStore struct {
+	sync.RWMutex
+	stores map[string]cache.Store
+}
+
+func newNamespacedStore() *namespacedStore {
+	return &namespacedStore{stores: make(map[string]cache.Store)}
+}
+
+func (s *namespacedStore) add(namespace string, store cache.Store) {
+	s.Lock()
+	defer s.Unlock()
+	s.stores[namespace] = store
+}
+
+func (s *namespacedStore) remove(namespace string) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.stores, namespace)
+}
+
+// storeOf returns the store of the namespace of the object.
+func (s *namespacedStore) storeOf(obj interface{}) (cache.Store, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	return s.storeByNamespace(accessor.GetNamespace())
+}
+
+func (s *namespacedStore) storeByNamespace(namespace string) (cache.Store, error) {
+	s.RLock()
+	defer s.RUnlock()
+	store, exists := s.stores[namespace]
+	if !exists {
+		return nil, fmt.Errorf("namespace %s is not observed", namespace)
+	}
+	return store, nil
+}
+
+// Add is not supported; The stores are populated by the informers.
+func (s *namespacedStore) Add(obj interface{}) error {
+	return errNamespacedStoreReadOnly
+}
+
+// Update is not supported; The stores are populated by the informers.
+func (s *namespacedStore) Update(obj interface{}) error {
+	return errNamespacedStoreReadOnly
+}
+
+// Delete is not supported; The stores are populated by the informers.
+func (s *namespacedStore) Delete(obj interface{}) error {
+	return errNamespacedStoreReadOnly
+}
+
+// Replace is not supported; The stores are populated by the informers.
+func (s *namespacedStore) Replace(list []interface{}, resourceVersion string) error {
+	return errNamespacedStoreReadOnly
+}
+
+// List lists the objects of all observed namespaces.
+func (s *namespacedStore) List() []interface{} {
+	s.RLock()
+	defer s.RUnlock()
+	var objects []interface{}
+	for _, store := range s.stores {
+		objects = append(objects, store.List()...)
+	}
+	return objects
+}
+
+// ListKeys lists the keys of the objects of all observed namespaces.
+func (s *namespacedStore) ListKeys() []string {
+	s.RLock()
+	defer s.RUnlock()
+	var keys []string
+	for _, store := range s.stores {
+		keys = append(keys, store.ListKeys()...)
+	}
+	return keys
+}
+
+// Get returns the object with the key of the given one; Objects of namespaces, which are not observed, do not exist.
+func (s *namespacedStore) Get(obj interface{}) (interface{}, bool, error) {
+	store, err := s.storeOf(obj)
+	if err != nil {
+		return nil, false, nil
+	}
+	return store.Get(obj)
+}
+
+// GetByKey returns the object with the given namespace/name key; Objects of namespaces, which are not observed, do not exist.
+func (s *namespacedStore) GetByKey(key string) (interface{}, bool, error) {
+	namespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	store, err := s.storeByNamespace(namespace)
+	if err != nil {
+		return nil, false, nil
+	}
+	return store.GetByKey(key)
+}
+
+// Resync resyncs the stores of all observed namespaces.
+func (s *namespacedStore) Resync() error {
+	s.RLock()
+	defer s.RUnlock()
+	for _, store := range s.stores {
+		if err := store.Resync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
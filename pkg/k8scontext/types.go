@@ -6,6 +6,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
+	gateway_versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned"
 	istio_versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
@@ -18,10 +19,15 @@ type InformerCollection struct {
 	Secret                       cache.SharedIndexInformer
 	Service                      cache.SharedIndexInformer
 	Namespace                    cache.SharedIndexInformer
-	AzureIngressManagedLocation  cache.SharedInformer
+	Node                         cache.SharedIndexInformer
+	AzureIngressManagedTarget    cache.SharedInformer
 	AzureIngressProhibitedTarget cache.SharedInformer
 	IstioGateway                 cache.SharedIndexInformer
 	IstioVirtualService          cache.SharedIndexInformer
+	IstioDestinationRule         cache.SharedIndexInformer
+	GatewayClass                 cache.SharedIndexInformer
+	Gateway                      cache.SharedIndexInformer
+	HTTPRoute                    cache.SharedIndexInformer
 }
 
 // CacheCollection : all the listers from the informers.
@@ -32,18 +38,24 @@ type CacheCollection struct {
 	Secret                       cache.Store
 	Service                      cache.Store
 	Namespaces                   cache.Store
-	AzureIngressManagedLocation  cache.Store
+	Nodes                        cache.Store
+	AzureIngressManagedTarget    cache.Store
 	AzureIngressProhibitedTarget cache.Store
 	IstioGateway                 cache.Store
 	IstioVirtualService          cache.Store
+	IstioDestinationRule         cache.Store
+	GatewayClass                 cache.Store
+	Gateway                      cache.Store
+	HTTPRoute                    cache.Store
 }
 
 // Context : cache and listener for k8s resources.
 type Context struct {
 	// k8s Clients
-	kubeClient     kubernetes.Interface
-	crdClient      versioned.Interface
-	istioCrdClient istio_versioned.Interface
+	kubeClient       kubernetes.Interface
+	crdClient        versioned.Interface
+	istioCrdClient   istio_versioned.Interface
+	gatewayCrdClient gateway_versioned.Interface
 
 	informers              *InformerCollection
 	Caches                 *CacheCollection
@@ -51,6 +63,15 @@ type Context struct {
 
 	ingressSecretsMap utils.ThreadsafeMultiMap
 
+	// istioGatewaySecretsMap holds the secrets with the TLS credentials of the servers of each Istio Gateway.
+	istioGatewaySecretsMap utils.ThreadsafeMultiMap
+
+	// gatewaySecretsMap holds the secrets with the certificates of the listeners of each Gateway API Gateway.
+	gatewaySecretsMap utils.ThreadsafeMultiMap
+
+	// namespaceWatcher runs the informers of each observed namespace; Nil when AGIC observes all namespaces.
+	namespaceWatcher *namespaceWatcher
+
 	UpdateChannel *channels.RingChannel
 }
 
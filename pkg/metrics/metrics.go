@@ -0,0 +1,40 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package metrics
+
+import (
+	"expvar"
+	"net/http"
+)
+
+var (
+	// DriftChecks is the number of drift checks AGIC performed.
+	DriftChecks = expvar.NewInt("agic_drift_checks_total")
+
+	// DriftDetected is the number of drift checks which found App Gateway differs from the config AGIC applied.
+	DriftDetected = expvar.NewInt("agic_drift_detected_total")
+
+	// DriftReverted is the number of times AGIC re-applied its config to revert a drift.
+	DriftReverted = expvar.NewInt("agic_drift_reverted_total")
+
+	// DriftedResources is the number of drifted App Gateway sub-resources found by the last drift check;
+	// Keyed by "<resource type>/<missing|added|modified>".
+	DriftedResources = expvar.NewMap("agic_drifted_resources")
+
+	// DeletionGuardBlocked is the number of deployments refused by the deletion guard.
+	DeletionGuardBlocked = expvar.NewInt("agic_deletion_guard_blocked_total")
+
+	// DeletionGuardDeletions is the number of sub-resources the last deployment refused by the deletion guard would have deleted;
+	// Keyed by resource type.
+	DeletionGuardDeletions = expvar.NewMap("agic_deletion_guard_deletions")
+)
+
+// NewMetricsMux makes a new *http.ServeMux exposing all AGIC metrics in JSON format.
+func NewMetricsMux() *http.ServeMux {
+	router := http.NewServeMux()
+	router.Handle("/metrics", expvar.Handler())
+	return router
+}
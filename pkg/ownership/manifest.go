@@ -0,0 +1,138 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package ownership
+
+import (
+	"sort"
+)
+
+// ResourceType is the type of an App Gateway sub-resource; Named after its collection in the App Gateway JSON.
+type ResourceType string
+
+const (
+	// BackendAddressPools are App Gateway backend address pools.
+	BackendAddressPools ResourceType = "backendAddressPools"
+
+	// BackendHTTPSettings are App Gateway backend HTTP settings.
+	BackendHTTPSettings ResourceType = "backendHttpSettingsCollection"
+
+	// FrontendPorts are App Gateway frontend ports.
+	FrontendPorts ResourceType = "frontendPorts"
+
+	// HTTPListeners are App Gateway HTTP listeners.
+	HTTPListeners ResourceType = "httpListeners"
+
+	// Probes are App Gateway health probes.
+	Probes ResourceType = "probes"
+
+	// RedirectConfigurations are App Gateway redirect configurations.
+	RedirectConfigurations ResourceType = "redirectConfigurations"
+
+	// RewriteRuleSets are App Gateway rewrite rule sets.
+	RewriteRuleSets ResourceType = "rewriteRuleSets"
+
+	// RequestRoutingRules are App Gateway request routing rules.
+	RequestRoutingRules ResourceType = "requestRoutingRules"
+
+	// SslCertificates are App Gateway SSL certificates.
+	SslCertificates ResourceType = "sslCertificates"
+
+	// URLPathMaps are App Gateway URL path maps.
+	URLPathMaps ResourceType = "urlPathMaps"
+)
+
+// Resource is an App Gateway sub-resource AGIC created.
+type Resource struct {
+	Type ResourceType `json:"type"`
+	Name string       `json:"name"`
+
+	// Ingresses and Services are the namespace/name of the Kubernetes resources the sub-resource was generated for.
+	// Both are empty for the defaults AGIC creates regardless of Ingresses.
+	Ingresses []string `json:"ingresses,omitempty"`
+	Services  []string `json:"services,omitempty"`
+}
+
+type resourceKey struct {
+	Type ResourceType
+	Name string
+}
+
+// Manifest lists the App Gateway sub-resources AGIC created; AGIC updates and deletes only these.
+type Manifest struct {
+	Resources []Resource `json:"resources"`
+}
+
+// NewManifest creates an empty Manifest.
+func NewManifest() *Manifest {
+	return &Manifest{}
+}
+
+// Add records the sub-resource of the given type and name in the manifest, along with the Ingress and Service it was
+// generated for; Either may be empty. Adding a sub-resource again adds to the Ingresses and Services it was generated for.
+func (m *Manifest) Add(resourceType ResourceType, name string, ingress string, service string) {
+	resource := m.Get(resourceType, name)
+	if resource == nil {
+		m.Resources = append(m.Resources, Resource{Type: resourceType, Name: name})
+		resource = &m.Resources[len(m.Resources)-1]
+	}
+	if ingress != "" && !contains(resource.Ingresses, ingress) {
+		resource.Ingresses = append(resource.Ingresses, ingress)
+		sort.Strings(resource.Ingresses)
+	}
+	if service != "" && !contains(resource.Services, service) {
+		resource.Services = append(resource.Services, service)
+		sort.Strings(resource.Services)
+	}
+}
+
+// Get returns the sub-resource of the given type and name; nil when AGIC did not create it.
+func (m *Manifest) Get(resourceType ResourceType, name string) *Resource {
+	for idx := range m.Resources {
+		if m.Resources[idx].Type == resourceType && m.Resources[idx].Name == name {
+			return &m.Resources[idx]
+		}
+	}
+	return nil
+}
+
+// Owns figures out whether AGIC created the sub-resource of the given type and name.
+// A nil Manifest owns everything: without a manifest AGIC falls back to prohibited and managed targets alone.
+func (m *Manifest) Owns(resourceType ResourceType, name string) bool {
+	if m == nil {
+		return true
+	}
+	return m.Get(resourceType, name) != nil
+}
+
+// GetByIngress lists the sub-resources AGIC created for the Ingress with the given namespace/name.
+func (m *Manifest) GetByIngress(ingress string) []Resource {
+	var resources []Resource
+	for _, resource := range m.Resources {
+		if contains(resource.Ingresses, ingress) {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+// Sort orders the sub-resources by type and name, so that equal manifests serialize the same.
+func (m *Manifest) Sort() {
+	sort.SliceStable(m.Resources, func(i, j int) bool {
+		if m.Resources[i].Type != m.Resources[j].Type {
+			return m.Resources[i].Type < m.Resources[j].Type
+		}
+		return m.Resources[i].Name < m.Resources[j].Name
+	})
+}
+
+func contains(list []string, item string) bool {
+	for _, listItem := range list {
+		if listItem == item {
+			return true
+		}
+	}
+	return false
+}
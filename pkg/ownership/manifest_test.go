@@ -0,0 +1,59 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package ownership
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ownership manifest tests", func() {
+	Context("ensure Add and Get work as expected", func() {
+		It("merges the Ingresses and Services a sub-resource was generated for", func() {
+			manifest := NewManifest()
+			manifest.Add(HTTPListeners, "fl-80", "default/b", "")
+			manifest.Add(HTTPListeners, "fl-80", "default/a", "")
+			manifest.Add(HTTPListeners, "fl-80", "default/a", "default/svc")
+			manifest.Add(BackendAddressPools, "fl-80", "", "")
+
+			Expect(manifest.Resources).To(HaveLen(2))
+			Expect(*manifest.Get(HTTPListeners, "fl-80")).To(Equal(Resource{
+				Type:      HTTPListeners,
+				Name:      "fl-80",
+				Ingresses: []string{"default/a", "default/b"},
+				Services:  []string{"default/svc"},
+			}))
+			Expect(manifest.Get(HTTPListeners, "fl-443")).To(BeNil())
+		})
+
+		It("looks sub-resources up by the Ingress they were generated for", func() {
+			manifest := NewManifest()
+			manifest.Add(HTTPListeners, "fl-80", "default/a", "")
+			manifest.Add(URLPathMaps, "url-80", "default/a", "")
+			manifest.Add(Probes, "defaultprobe", "", "")
+
+			resources := manifest.GetByIngress("default/a")
+			Expect(resources).To(HaveLen(2))
+			Expect(resources[0].Name).To(Equal("fl-80"))
+			Expect(resources[1].Name).To(Equal("url-80"))
+		})
+	})
+
+	Context("ensure Owns works as expected", func() {
+		It("owns only the recorded sub-resources", func() {
+			manifest := NewManifest()
+			manifest.Add(HTTPListeners, "fl-80", "", "")
+			Expect(manifest.Owns(HTTPListeners, "fl-80")).To(BeTrue())
+			Expect(manifest.Owns(URLPathMaps, "fl-80")).To(BeFalse())
+			Expect(manifest.Owns(HTTPListeners, "manually-created")).To(BeFalse())
+		})
+
+		It("owns everything without a manifest", func() {
+			var manifest *Manifest
+			Expect(manifest.Owns(HTTPListeners, "manually-created")).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,93 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package ownership
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ConfigMapName is the name of the Kubernetes ConfigMap where AGIC keeps the ownership manifests; One key per App Gateway.
+// App Gateway tags are limited to 256 characters, which is too little for a manifest.
+const ConfigMapName = "agic-ownership"
+
+// Store keeps the ownership manifest of an App Gateway in a Kubernetes ConfigMap.
+type Store struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	appGwName  string
+}
+
+// NewStore creates a Store keeping the manifest of the given App Gateway in the given namespace.
+func NewStore(kubeClient kubernetes.Interface, namespace string, appGwName string) *Store {
+	return &Store{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		appGwName:  appGwName,
+	}
+}
+
+// Load returns the ownership manifest of the App Gateway; nil when AGIC did not record one yet.
+func (s *Store) Load() (*Manifest, error) {
+	configMap, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Get(ConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	data, exists := configMap.Data[s.appGwName]
+	if !exists {
+		return nil, nil
+	}
+	manifest := NewManifest()
+	if err := json.Unmarshal([]byte(data), manifest); err != nil {
+		return nil, fmt.Errorf("ownership manifest of App Gateway %s is corrupted: %s", s.appGwName, err)
+	}
+	return manifest, nil
+}
+
+// Save replaces the ownership manifest of the App Gateway; The ConfigMap is not updated when the manifest did not change.
+func (s *Store) Save(manifest *Manifest) error {
+	manifest.Sort()
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	configMaps := s.kubeClient.CoreV1().ConfigMaps(s.namespace)
+	configMap, err := configMaps.Get(ConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		configMap = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ConfigMapName,
+				Namespace: s.namespace,
+			},
+			Data: map[string]string{
+				s.appGwName: string(data),
+			},
+		}
+		_, err := configMaps.Create(configMap)
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	if configMap.Data[s.appGwName] == string(data) {
+		return nil
+	}
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[s.appGwName] = string(data)
+	_, err = configMaps.Update(configMap)
+	return err
+}
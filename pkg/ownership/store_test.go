@@ -0,0 +1,81 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package ownership
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("ownership store tests", func() {
+	var store *Store
+	var k8sClient *testclient.Clientset
+
+	BeforeEach(func() {
+		k8sClient = testclient.NewSimpleClientset()
+		store = NewStore(k8sClient, tests.Namespace, tests.AppGwName)
+	})
+
+	Context("ensure Save and Load work as expected", func() {
+		It("returns no manifest before the first one is saved", func() {
+			manifest, err := store.Load()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(manifest).To(BeNil())
+		})
+
+		It("loads the manifest it saved", func() {
+			manifest := NewManifest()
+			manifest.Add(URLPathMaps, "url-80", "default/a", "")
+			manifest.Add(HTTPListeners, "fl-80", "default/a", "")
+			Expect(store.Save(manifest)).To(Succeed())
+
+			loaded, err := store.Load()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loaded.Resources).To(Equal([]Resource{
+				{Type: HTTPListeners, Name: "fl-80", Ingresses: []string{"default/a"}},
+				{Type: URLPathMaps, Name: "url-80", Ingresses: []string{"default/a"}},
+			}))
+
+			manifest.Add(Probes, "defaultprobe", "", "")
+			Expect(store.Save(manifest)).To(Succeed())
+			loaded, err = store.Load()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loaded.Resources).To(HaveLen(3))
+		})
+
+		It("keeps the manifests of other App Gateways", func() {
+			otherStore := NewStore(k8sClient, tests.Namespace, "other-gateway")
+			otherManifest := NewManifest()
+			otherManifest.Add(HTTPListeners, "fl-443", "", "")
+			Expect(otherStore.Save(otherManifest)).To(Succeed())
+
+			manifest := NewManifest()
+			manifest.Add(HTTPListeners, "fl-80", "", "")
+			Expect(store.Save(manifest)).To(Succeed())
+
+			loaded, err := otherStore.Load()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loaded.Owns(HTTPListeners, "fl-443")).To(BeTrue())
+			Expect(loaded.Owns(HTTPListeners, "fl-80")).To(BeFalse())
+		})
+
+		It("fails on a corrupted manifest", func() {
+			_, err := k8sClient.CoreV1().ConfigMaps(tests.Namespace).Create(&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: tests.Namespace},
+				Data:       map[string]string{tests.AppGwName: "{"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = store.Load()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,119 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ChangeType describes how a sub-resource changed between two snapshots.
+type ChangeType string
+
+const (
+	// Added sub-resources exist only in the newer snapshot.
+	Added ChangeType = "added"
+
+	// Removed sub-resources exist only in the older snapshot.
+	Removed ChangeType = "removed"
+
+	// Modified sub-resources exist in both snapshots with different properties.
+	Modified ChangeType = "modified"
+)
+
+// Collections are the App Gateway sub-resource collections AGIC manages; Diff compares these.
+var Collections = []string{
+	"backendAddressPools",
+	"backendHttpSettingsCollection",
+	"probes",
+	"frontendPorts",
+	"httpListeners",
+	"sslCertificates",
+	"redirectConfigurations",
+	"urlPathMaps",
+	"requestRoutingRules",
+}
+
+// Change is a sub-resource, which differs between two snapshots.
+type Change struct {
+	Collection string
+	Name       string
+	Type       ChangeType
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s %s/%s", c.Type, c.Collection, c.Name)
+}
+
+// Diff lists the sub-resources, which changed between the two snapshots.
+func Diff(from Snapshot, to Snapshot) ([]Change, error) {
+	fromProperties, err := getProperties(from)
+	if err != nil {
+		return nil, err
+	}
+	toProperties, err := getProperties(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, collection := range Collections {
+		fromByName := indexByName(fromProperties[collection])
+		toByName := indexByName(toProperties[collection])
+		var names []string
+		for name := range fromByName {
+			names = append(names, name)
+		}
+		for name := range toByName {
+			if _, exists := fromByName[name]; !exists {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			fromResource, inFrom := fromByName[name]
+			toResource, inTo := toByName[name]
+			switch {
+			case !inFrom:
+				changes = append(changes, Change{Collection: collection, Name: name, Type: Added})
+			case !inTo:
+				changes = append(changes, Change{Collection: collection, Name: name, Type: Removed})
+			case !reflect.DeepEqual(fromResource, toResource):
+				changes = append(changes, Change{Collection: collection, Name: name, Type: Modified})
+			}
+		}
+	}
+	return changes, nil
+}
+
+func getProperties(snapshot Snapshot) (map[string]interface{}, error) {
+	var appGw map[string]interface{}
+	if err := json.Unmarshal(snapshot.Config, &appGw); err != nil {
+		return nil, fmt.Errorf("snapshot %s is corrupted: %s", snapshot.ID, err)
+	}
+	properties, _ := appGw["properties"].(map[string]interface{})
+	return properties, nil
+}
+
+func indexByName(collection interface{}) map[string]interface{} {
+	indexed := make(map[string]interface{})
+	resources, _ := collection.([]interface{})
+	for _, resource := range resources {
+		resourceMap, ok := resource.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, ok := resourceMap["name"].(string); ok {
+			// ETags change on every deployment and are not a change of config.
+			delete(resourceMap, "etag")
+			indexed[name] = resourceMap
+		}
+	}
+	return indexed
+}
@@ -0,0 +1,293 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// SecretName is the name of the Kubernetes Secret where AGIC keeps the snapshots.
+	SecretName = "agic-config-snapshots"
+
+	// idFormat is the format of snapshot IDs. IDs sort chronologically and are valid Secret keys; Fixed width
+	// nanoseconds keep the IDs of snapshots taken within the same second apart.
+	idFormat = "20060102T150405.000000000Z"
+)
+
+var (
+	// ErrSnapshotNotFound is returned when there is no snapshot with the given ID.
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+
+	// ErrNoSnapshots is returned when no snapshot was taken yet.
+	ErrNoSnapshots = errors.New("there are no snapshots")
+)
+
+// Snapshot is an App Gateway config AGIC applied successfully.
+// Certificates are stored by reference: the snapshot keeps their names, but not their payload.
+type Snapshot struct {
+	ID        string          `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Config    json.RawMessage `json:"config"`
+}
+
+// Store keeps the last few snapshots in a Kubernetes Secret.
+type Store struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	limit      int
+
+	now func() time.Time
+}
+
+// NewStore creates a Store keeping up to limit snapshots in the given namespace.
+func NewStore(kubeClient kubernetes.Interface, namespace string, limit int) *Store {
+	return &Store{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		limit:      limit,
+		now:        time.Now,
+	}
+}
+
+// Save takes a snapshot of the given App Gateway config and discards the oldest snapshots over the limit.
+func (s *Store) Save(appGw n.ApplicationGateway) (*Snapshot, error) {
+	config, err := marshalWithCertificateReferences(appGw)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := s.kubeClient.CoreV1().Secrets(s.namespace)
+	secret, getErr := secrets.Get(SecretName, metav1.GetOptions{})
+	if getErr != nil && !apierrors.IsNotFound(getErr) {
+		return nil, getErr
+	}
+
+	now := s.now().UTC()
+	if getErr == nil {
+		// Never overwrite a snapshot; Clocks of a coarse resolution may give the same time twice.
+		for {
+			if _, exists := secret.Data[now.Format(idFormat)]; !exists {
+				break
+			}
+			now = now.Add(time.Nanosecond)
+		}
+	}
+	snapshot := Snapshot{
+		ID:        now.Format(idFormat),
+		Timestamp: now,
+		Config:    config,
+	}
+	compressed, err := compress(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	if apierrors.IsNotFound(getErr) {
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      SecretName,
+				Namespace: s.namespace,
+			},
+			Data: map[string][]byte{
+				snapshot.ID: compressed,
+			},
+		}
+		if _, err := secrets.Create(secret); err != nil {
+			return nil, err
+		}
+		return &snapshot, nil
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[snapshot.ID] = compressed
+	ids := sortedIDs(secret.Data)
+	for len(ids) > s.limit {
+		delete(secret.Data, ids[len(ids)-1])
+		ids = ids[:len(ids)-1]
+	}
+	if _, err := secrets.Update(secret); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// List returns all snapshots; newest first.
+func (s *Store) List() ([]Snapshot, error) {
+	secret, err := s.kubeClient.CoreV1().Secrets(s.namespace).Get(SecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	for _, id := range sortedIDs(secret.Data) {
+		snapshot, err := decompress(secret.Data[id])
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s is corrupted: %s", id, err)
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+	return snapshots, nil
+}
+
+// Get returns the snapshot with the given ID.
+func (s *Store) Get(id string) (*Snapshot, error) {
+	snapshots, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	for idx := range snapshots {
+		if snapshots[idx].ID == id {
+			return &snapshots[idx], nil
+		}
+	}
+	return nil, ErrSnapshotNotFound
+}
+
+// Latest returns the most recent snapshot; This is the last known good config.
+func (s *Store) Latest() (*Snapshot, error) {
+	snapshots, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, ErrNoSnapshots
+	}
+	return &snapshots[0], nil
+}
+
+// ToAppGateway rebuilds the App Gateway config from the snapshot. Certificates are resolved against the existing
+// App Gateway; A snapshot can be restored only while App Gateway still has all the certificates it references.
+func (snapshot Snapshot) ToAppGateway(existing n.ApplicationGateway) (n.ApplicationGateway, error) {
+	var appGw n.ApplicationGateway
+	if err := appGw.UnmarshalJSON(snapshot.Config); err != nil {
+		return appGw, err
+	}
+	if appGw.ApplicationGatewayPropertiesFormat == nil || appGw.SslCertificates == nil {
+		return appGw, nil
+	}
+
+	existingCerts := make(map[string]n.ApplicationGatewaySslCertificate)
+	if existing.ApplicationGatewayPropertiesFormat != nil && existing.SslCertificates != nil {
+		for _, cert := range *existing.SslCertificates {
+			existingCerts[*cert.Name] = cert
+		}
+	}
+
+	var certs []n.ApplicationGatewaySslCertificate
+	var missing []string
+	for _, cert := range *appGw.SslCertificates {
+		existingCert, exists := existingCerts[*cert.Name]
+		if !exists {
+			missing = append(missing, *cert.Name)
+			continue
+		}
+		certs = append(certs, existingCert)
+	}
+	if len(missing) > 0 {
+		return appGw, fmt.Errorf("snapshot %s references certificates, which App Gateway no longer has: %s", snapshot.ID, strings.Join(missing, ", "))
+	}
+	appGw.SslCertificates = &certs
+	return appGw, nil
+}
+
+// Rollback applies the given snapshot to App Gateway and waits for the deployment to complete.
+// Returns the App Gateway config it applied.
+func Rollback(ctx context.Context, client n.ApplicationGatewaysClient, resourceGroup string, appGwName string, snapshot Snapshot) (n.ApplicationGateway, error) {
+	existing, err := client.Get(ctx, resourceGroup, appGwName)
+	if err != nil {
+		return n.ApplicationGateway{}, err
+	}
+	appGw, err := snapshot.ToAppGateway(existing)
+	if err != nil {
+		return n.ApplicationGateway{}, err
+	}
+	future, err := client.CreateOrUpdate(ctx, resourceGroup, appGwName, appGw)
+	if err != nil {
+		return n.ApplicationGateway{}, err
+	}
+	return appGw, future.WaitForCompletionRef(ctx, client.Client)
+}
+
+// marshalWithCertificateReferences serializes App Gateway without the ETag and certificate payloads.
+func marshalWithCertificateReferences(appGw n.ApplicationGateway) ([]byte, error) {
+	appGw.Etag = nil
+	if appGw.ApplicationGatewayPropertiesFormat != nil && appGw.SslCertificates != nil {
+		properties := *appGw.ApplicationGatewayPropertiesFormat
+		var certs []n.ApplicationGatewaySslCertificate
+		for _, cert := range *appGw.SslCertificates {
+			certs = append(certs, n.ApplicationGatewaySslCertificate{
+				Name: cert.Name,
+				ID:   cert.ID,
+			})
+		}
+		properties.SslCertificates = &certs
+		appGw.ApplicationGatewayPropertiesFormat = &properties
+	}
+	return appGw.MarshalJSON()
+}
+
+func compress(snapshot Snapshot) ([]byte, error) {
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(snapshotJSON); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func decompress(compressed []byte) (*Snapshot, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	snapshotJSON, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(snapshotJSON, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// sortedIDs returns the snapshot IDs; newest first.
+func sortedIDs(data map[string][]byte) []string {
+	var ids []string
+	for id := range data {
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids
+}
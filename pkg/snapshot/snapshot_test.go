@@ -0,0 +1,156 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package snapshot
+
+import (
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("snapshot store tests", func() {
+	var store *Store
+	var k8sClient *testclient.Clientset
+	var now time.Time
+
+	BeforeEach(func() {
+		k8sClient = testclient.NewSimpleClientset()
+		store = NewStore(k8sClient, tests.Namespace, 2)
+		now = time.Date(2019, 8, 1, 10, 0, 0, 0, time.UTC)
+		store.now = func() time.Time {
+			now = now.Add(time.Minute)
+			return now
+		}
+	})
+
+	Context("ensure Save and List work as expected", func() {
+		It("returns no snapshots before the first one is taken", func() {
+			snapshots, err := store.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshots).To(BeEmpty())
+
+			_, err = store.Latest()
+			Expect(err).To(Equal(ErrNoSnapshots))
+		})
+
+		It("keeps the newest snapshots up to the limit", func() {
+			for i := 0; i < 3; i++ {
+				_, err := store.Save(fixtures.GetAppGateway())
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			snapshots, err := store.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(snapshots)).To(Equal(2))
+			Expect(snapshots[0].ID).To(Equal("20190801T100300.000000000Z"))
+			Expect(snapshots[1].ID).To(Equal("20190801T100200.000000000Z"))
+
+			latest, err := store.Latest()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(latest.ID).To(Equal("20190801T100300.000000000Z"))
+
+			_, err = store.Get("20190801T100100.000000000Z")
+			Expect(err).To(Equal(ErrSnapshotNotFound))
+
+			secret, err := k8sClient.CoreV1().Secrets(tests.Namespace).Get(SecretName, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(secret.Data)).To(Equal(2))
+		})
+
+		It("keeps the snapshots taken at the same time apart", func() {
+			store.now = func() time.Time { return now }
+			first, err := store.Save(fixtures.GetAppGateway())
+			Expect(err).ToNot(HaveOccurred())
+			second, err := store.Save(fixtures.GetAppGateway())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(second.ID).ToNot(Equal(first.ID))
+
+			latest, err := store.Latest()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(latest.ID).To(Equal(second.ID))
+
+			snapshots, err := store.List()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(snapshots)).To(Equal(2))
+		})
+
+		It("stores certificates by reference", func() {
+			appGw := fixtures.GetAppGateway()
+			(*appGw.SslCertificates)[0].ApplicationGatewaySslCertificatePropertiesFormat = &n.ApplicationGatewaySslCertificatePropertiesFormat{
+				Data:     to.StringPtr("--certificate-data--"),
+				Password: to.StringPtr("--password--"),
+			}
+			snapshot, err := store.Save(appGw)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(snapshot.Config)).ToNot(ContainSubstring("--certificate-data--"))
+			Expect(string(snapshot.Config)).ToNot(ContainSubstring("--password--"))
+
+			// The original config is not modified.
+			Expect(*(*appGw.SslCertificates)[0].Data).To(Equal("--certificate-data--"))
+		})
+	})
+
+	Context("ensure ToAppGateway works as expected", func() {
+		It("resolves certificates against the existing App Gateway", func() {
+			snapshot, err := store.Save(fixtures.GetAppGateway())
+			Expect(err).ToNot(HaveOccurred())
+
+			existing := fixtures.GetAppGateway()
+			appGw, err := snapshot.ToAppGateway(existing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*appGw.SslCertificates).To(Equal(*existing.SslCertificates))
+			Expect(len(*appGw.HTTPListeners)).To(Equal(len(*existing.HTTPListeners)))
+		})
+
+		It("fails when a certificate no longer exists", func() {
+			snapshot, err := store.Save(fixtures.GetAppGateway())
+			Expect(err).ToNot(HaveOccurred())
+
+			existing := fixtures.GetAppGateway()
+			existing.SslCertificates = &[]n.ApplicationGatewaySslCertificate{
+				fixtures.GetCertificate1(),
+			}
+			_, err = snapshot.ToAppGateway(existing)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("ensure Diff works as expected", func() {
+		It("lists added, removed and modified sub-resources", func() {
+			from, err := store.Save(fixtures.GetAppGateway())
+			Expect(err).ToNot(HaveOccurred())
+
+			appGw := fixtures.GetAppGateway()
+			appGw.BackendAddressPools = &[]n.ApplicationGatewayBackendAddressPool{
+				fixtures.GetBackendPool1(),
+			}
+			appGw.SslCertificates = &[]n.ApplicationGatewaySslCertificate{
+				fixtures.GetCertificate1(),
+				fixtures.GetCertificate2(),
+			}
+			(*appGw.Probes)[0].Path = to.StringPtr("/changed")
+			(*appGw.Probes)[1].Etag = to.StringPtr("--etag--")
+			to, err := store.Save(appGw)
+			Expect(err).ToNot(HaveOccurred())
+
+			changes, err := Diff(*from, *to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]Change{
+				{Collection: "backendAddressPools", Name: fixtures.BackendAddressPoolName1, Type: Added},
+				{Collection: "probes", Name: *(*appGw.Probes)[0].Name, Type: Modified},
+				{Collection: "sslCertificates", Name: *fixtures.GetCertificate3().Name, Type: Removed},
+			}))
+		})
+	})
+})
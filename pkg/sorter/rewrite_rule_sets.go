@@ -0,0 +1,26 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package sorter
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2018-12-01/network"
+)
+
+// ByRewriteRuleSetName is a facility to sort slices of ApplicationGatewayRewriteRuleSet by Name
+type ByRewriteRuleSetName []n.ApplicationGatewayRewriteRuleSet
+
+func (a ByRewriteRuleSetName) Len() int      { return len(a) }
+func (a ByRewriteRuleSetName) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a ByRewriteRuleSetName) Less(i, j int) bool {
+	return getRewriteRuleSetName(a[i]) < getRewriteRuleSetName(a[j])
+}
+
+func getRewriteRuleSetName(ruleSet n.ApplicationGatewayRewriteRuleSet) string {
+	if ruleSet.Name == nil {
+		return ""
+	}
+	return *ruleSet.Name
+}
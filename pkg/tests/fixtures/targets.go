@@ -6,6 +6,7 @@
 package fixtures
 
 import (
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
@@ -53,3 +54,14 @@ func GetAzureIngressProhibitedTargets() []*ptv1.AzureIngressProhibitedTarget {
 		},
 	}
 }
+
+// GetAzureIngressManagedTargets creates a new struct for use in unit tests.
+func GetAzureIngressManagedTargets() []*mtv1.AzureIngressManagedTarget {
+	return []*mtv1.AzureIngressManagedTarget{
+		{
+			Spec: mtv1.AzureIngressManagedTargetSpec{
+				Hostname: tests.Host,
+			},
+		},
+	}
+}
@@ -0,0 +1,149 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/golang/glog"
+	admission "k8s.io/api/admission/v1beta1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+)
+
+const (
+	// IngressPath is the URL path of the webhook validating Ingresses.
+	IngressPath = "/validate/ingress"
+
+	// ProhibitedTargetPath is the URL path of the webhook validating AzureIngressProhibitedTargets.
+	ProhibitedTargetPath = "/validate/azureingressprohibitedtarget"
+)
+
+// IngressLister lists the Ingresses AGIC is responsible for; Implemented by k8scontext.Context.
+type IngressLister interface {
+	ListHTTPIngresses() []*v1beta1.Ingress
+}
+
+type validateFunc func(request *admission.AdmissionRequest) []string
+
+// NewWebhookMux makes a new *http.ServeMux serving the validating admission webhooks of AGIC.
+func NewWebhookMux(ingressLister IngressLister) *http.ServeMux {
+	router := http.NewServeMux()
+	router.HandleFunc(IngressPath, serve(func(request *admission.AdmissionRequest) []string {
+		return validateIngress(request, ingressLister)
+	}))
+	router.HandleFunc(ProhibitedTargetPath, serve(validateProhibitedTarget))
+	return router
+}
+
+// serve decodes the AdmissionReview, validates the object in it and responds with the verdict.
+func serve(validate validateFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("could not read request: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		review := admission.AdmissionReview{}
+		if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+			http.Error(w, "could not decode AdmissionReview", http.StatusBadRequest)
+			return
+		}
+
+		response := &admission.AdmissionResponse{
+			UID:     review.Request.UID,
+			Allowed: true,
+		}
+		if problems := validate(review.Request); len(problems) > 0 {
+			message := strings.Join(problems, "; ")
+			glog.V(3).Infof("[webhook] Rejecting %s %s/%s: %s", review.Request.Kind.Kind, review.Request.Namespace, review.Request.Name, message)
+			response.Allowed = false
+			response.Result = &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Reason:  metav1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+				Message: message,
+			}
+		}
+
+		responseJSON, err := json.Marshal(admission.AdmissionReview{
+			TypeMeta: review.TypeMeta,
+			Response: response,
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("could not encode response: %s", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(responseJSON); err != nil {
+			glog.Error("[webhook] Could not write response: ", err)
+		}
+	}
+}
+
+// validateIngress rejects Ingresses for AGIC with malformed annotations, ssl-redirect without TLS, or hosts and paths
+// the controller would drop in favour of another Ingress.
+func validateIngress(request *admission.AdmissionRequest, ingressLister IngressLister) []string {
+	if request.Operation == admission.Delete {
+		return nil
+	}
+
+	ingress := &v1beta1.Ingress{}
+	if err := json.Unmarshal(request.Object.Raw, ingress); err != nil {
+		return []string{fmt.Sprintf("could not decode Ingress: %s", err)}
+	}
+	if ingress.Namespace == "" {
+		ingress.Namespace = request.Namespace
+	}
+
+	// Ingresses for other ingress controllers are none of our business.
+	if isAGIC, _ := annotations.IsApplicationGatewayIngress(ingress); !isAGIC {
+		return nil
+	}
+
+	var problems []string
+	for _, err := range annotations.Validate(ingress) {
+		problems = append(problems, err.Error())
+	}
+
+	if err := appgw.ValidateRedirectHasTLS(ingress); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	for _, conflict := range appgw.GetHostPathConflicts(ingress, ingressLister.ListHTTPIngresses()) {
+		if conflict.ConflictingTLS {
+			problems = append(problems, fmt.Sprintf("host %q already uses a different TLS secret in Ingress %s/%s", conflict.Host, conflict.ConflictingIngress.Namespace, conflict.ConflictingIngress.Name))
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("host %q and path %s are already used with a different backend by Ingress %s/%s", conflict.Host, conflict.Path, conflict.ConflictingIngress.Namespace, conflict.ConflictingIngress.Name))
+	}
+
+	return problems
+}
+
+// validateProhibitedTarget rejects AzureIngressProhibitedTargets with an invalid spec.
+func validateProhibitedTarget(request *admission.AdmissionRequest) []string {
+	if request.Operation == admission.Delete {
+		return nil
+	}
+
+	target := &ptv1.AzureIngressProhibitedTarget{}
+	if err := json.Unmarshal(request.Object.Raw, target); err != nil {
+		return []string{fmt.Sprintf("could not decode AzureIngressProhibitedTarget: %s", err)}
+	}
+
+	return brownfield.ValidateProhibitedTargetSpec(target.Spec)
+}
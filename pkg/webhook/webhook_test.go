@@ -0,0 +1,130 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admission "k8s.io/api/admission/v1beta1"
+	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+type fakeIngressLister []*v1beta1.Ingress
+
+func (l fakeIngressLister) ListHTTPIngresses() []*v1beta1.Ingress {
+	return l
+}
+
+var _ = Describe("validating webhook tests", func() {
+	existing := tests.NewIngressTestFixture(tests.Namespace, "existing")
+	router := NewWebhookMux(fakeIngressLister{&existing})
+
+	review := func(path string, operation admission.Operation, object interface{}) *admission.AdmissionResponse {
+		raw, err := json.Marshal(object)
+		Expect(err).ToNot(HaveOccurred())
+		body, err := json.Marshal(admission.AdmissionReview{
+			Request: &admission.AdmissionRequest{
+				UID:       types.UID("--uid--"),
+				Namespace: tests.Namespace,
+				Operation: operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		response := admission.AdmissionReview{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Response.UID).To(Equal(types.UID("--uid--")))
+		return response.Response
+	}
+
+	newIngress := func() *v1beta1.Ingress {
+		ingress := tests.NewIngressTestFixture(tests.Namespace, "new")
+		ingress.Spec.Rules[0].Host = "new.com"
+		return &ingress
+	}
+
+	Context("ensure Ingresses are validated", func() {
+		It("admits a valid Ingress", func() {
+			Expect(review(IngressPath, admission.Create, newIngress()).Allowed).To(BeTrue())
+		})
+
+		It("rejects malformed annotations", func() {
+			ingress := newIngress()
+			ingress.Annotations[annotations.RequestTimeoutKey] = "abc"
+			response := review(IngressPath, admission.Create, ingress)
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring(annotations.RequestTimeoutKey))
+		})
+
+		It("rejects ssl-redirect without TLS", func() {
+			ingress := newIngress()
+			ingress.Annotations[annotations.SslRedirectKey] = "true"
+			response := review(IngressPath, admission.Update, ingress)
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("ssl-redirect"))
+		})
+
+		It("rejects a host and path already used with a different backend by another Ingress", func() {
+			ingress := newIngress()
+			ingress.Spec.Rules[0].Host = existing.Spec.Rules[0].Host
+			ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName = "other-service"
+			response := review(IngressPath, admission.Create, ingress)
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring(tests.Namespace + "/existing"))
+		})
+
+		It("admits a host and path already used with the same backend by another Ingress", func() {
+			ingress := newIngress()
+			ingress.Spec.Rules[0].Host = existing.Spec.Rules[0].Host
+			Expect(review(IngressPath, admission.Create, ingress).Allowed).To(BeTrue())
+		})
+
+		It("admits Ingresses for other ingress controllers", func() {
+			ingress := newIngress()
+			ingress.Annotations[annotations.IngressClassKey] = "nginx"
+			ingress.Annotations[annotations.RequestTimeoutKey] = "abc"
+			Expect(review(IngressPath, admission.Create, ingress).Allowed).To(BeTrue())
+		})
+	})
+
+	Context("ensure AzureIngressProhibitedTargets are validated", func() {
+		It("admits a valid prohibited target", func() {
+			target := ptv1.AzureIngressProhibitedTarget{Spec: ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host, Paths: []string{"/api/*"}}}
+			Expect(review(ProhibitedTargetPath, admission.Create, target).Allowed).To(BeTrue())
+		})
+
+		It("rejects paths which do not begin with a /", func() {
+			target := ptv1.AzureIngressProhibitedTarget{Spec: ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host, Paths: []string{"api/*"}}}
+			response := review(ProhibitedTargetPath, admission.Create, target)
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("must begin with a /"))
+		})
+	})
+
+	Context("ensure malformed requests are refused", func() {
+		It("responds with bad request", func() {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, IngressPath, bytes.NewReader([]byte("{}"))))
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
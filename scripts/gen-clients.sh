@@ -15,6 +15,13 @@ echo -e "Generate AzureIngressManagedTarget, AzureIngressProhibitedTarget..."
     github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis \
     "azureingressmanagedtarget:v1 azureingressprohibitedtarget:v1"
 
+echo -e "Generate Gateway API GatewayClass, Gateway, HTTPRoute..."
+../code-generator/generate-groups.sh \
+    all \
+    github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client \
+    github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis \
+    "gatewayapi:v1beta1"
+
 go get github.com/knative/pkg/apis/istio/v1alpha3
 
 go vendor